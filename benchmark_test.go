@@ -173,3 +173,56 @@ func Benchmark_strutils_TestNumbertFmtInt64UseHumanUnits(b *testing.B) {
 		}
 	}
 }
+
+func Benchmark_strutils_NumberFmtOf(b *testing.B) {
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		retval := strutils.NumberFmtOf(int64(123456789101112))
+		if retval != "123,456,789,101,112" {
+			b.Errorf("Return Value mismatch.\nExpected: %v\nActual: %v", "123,456,789,101,112", retval)
+		}
+	}
+}
+
+func Benchmark_strutils_AppendNumberFmt(b *testing.B) {
+	b.ReportAllocs()
+
+	buf := make([]byte, 0, 64)
+	for i := 0; i < b.N; i++ {
+		buf = strutils.AppendNumberFmt(buf[:0], int64(123456789101112))
+		buf = strutils.AppendNumberFmt(buf[:0], -12345.16)
+	}
+}
+
+func Benchmark_strutils_HumanByteSize(b *testing.B) {
+	b.ReportAllocs()
+
+	strproc := strutils.NewStringProc()
+	for i := 0; i < b.N; i++ {
+		if _, err := strproc.HumanByteSize(123456789, 2, strutils.UpperCaseDouble); err != nil {
+			b.Errorf("Return Error : %v", err)
+		}
+	}
+}
+
+func Benchmark_strutils_AppendHumanBytes(b *testing.B) {
+	b.ReportAllocs()
+
+	buf := make([]byte, 0, 64)
+	for i := 0; i < b.N; i++ {
+		var err error
+		if buf, err = strutils.AppendHumanBytes(buf[:0], 123456789, 2, strutils.UpperCaseDouble); err != nil {
+			b.Errorf("Return Error : %v", err)
+		}
+	}
+}
+
+func Benchmark_strutils_AppendToString(b *testing.B) {
+	b.ReportAllocs()
+
+	buf := make([]byte, 0, 64)
+	for i := 0; i < b.N; i++ {
+		buf = strutils.AppendToString(buf[:0], 1234.5678)
+	}
+}
@@ -0,0 +1,379 @@
+package strutils
+
+import (
+	"encoding/base64"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// Encoding type control for Canonicalize
+const (
+	_                      = uint8(iota)
+	EncodingHTMLEntity     // HTML character references (&lt; , &#60; , &#x3c;)
+	EncodingUnicodePercent // %uHHHH
+	EncodingPercent        // %HH
+	EncodingFormURL        // %HH and + (space) , same as application/x-www-form-urlencoded
+	EncodingJavaScript     // \xHH , \uHHHH , \u{H...} , \n \r \t \v and \\ \' \" \/
+	EncodingCSS            // \H ~ \HHHHHH (optional trailing white space) , \(any other character)
+	EncodingDataURIBase64  // data:[<mediatype>];base64,<data>
+)
+
+// maximum number of decoding passes, decoding is stopped at this depth
+const canonicalizeMaxDepth = 16
+
+var encodingNames = map[uint8]string{
+	EncodingHTMLEntity:     "html entity",
+	EncodingUnicodePercent: "unicode percent",
+	EncodingPercent:        "percent",
+	EncodingFormURL:        "form url",
+	EncodingJavaScript:     "javascript",
+	EncodingCSS:            "css",
+	EncodingDataURIBase64:  "data uri base64",
+}
+
+// default encodings of Canonicalize, (EncodingFormURL is not in, '+' is plain text in most contexts)
+var canonicalizeDefault = []uint8{EncodingHTMLEntity, EncodingUnicodePercent, EncodingPercent, EncodingJavaScript, EncodingCSS, EncodingDataURIBase64}
+
+var dataURIBase64Pattern = regexp.MustCompile(`(?i)data:([^,;]*)((?:;[^,;]*)*?);base64,([A-Za-z0-9+/_-]+=*)`)
+
+// CanonicalizeResult is the report of Canonicalize
+type CanonicalizeResult struct {
+	Str       string        // canonicalized string
+	Depth     int           // the number of decoding passes that changed the string
+	Encodings []uint8       // found encodings, in order of appearance
+	Found     map[uint8]int // found encoding => the number of passes that found it
+}
+
+// IsMixed returns true if two or more different encodings were found
+func (c *CanonicalizeResult) IsMixed() bool {
+	return len(c.Encodings) > 1
+}
+
+// IsMultiple returns true if the string was encoded two or more times (nested)
+func (c *CanonicalizeResult) IsMultiple() bool {
+	return c.Depth > 1
+}
+
+// IsSuspicious returns true if mixed or multiple encoding was found
+func (c *CanonicalizeResult) IsSuspicious() bool {
+	return c.IsMixed() || c.IsMultiple()
+}
+
+// EncodingNames returns names of the found encodings
+func (c *CanonicalizeResult) EncodingNames() []string {
+	retval := make([]string, 0, len(c.Encodings))
+	for _, v := range c.Encodings {
+		retval = append(retval, encodingNames[v])
+	}
+
+	return retval
+}
+
+// Canonicalize is Decodes the string repeatedly until it stops changing, and reports found encodings (like OWASP ESAPI canonicalizer)
+// NOTE : uses all encodings without EncodingFormURL if not given
+func (s *StringProc) Canonicalize(str string, encodings ...uint8) (*CanonicalizeResult, error) {
+	var enabled [EncodingDataURIBase64 + 1]bool
+
+	if len(encodings) == 0 {
+		encodings = canonicalizeDefault
+	}
+
+	for _, v := range encodings {
+		if v < EncodingHTMLEntity || v > EncodingDataURIBase64 {
+			return nil, fmt.Errorf("Not allow encodings parameter : %v", v)
+		}
+		enabled[v] = true
+	}
+
+	if enabled[EncodingPercent] && enabled[EncodingFormURL] {
+		enabled[EncodingPercent] = false
+	}
+
+	retval := &CanonicalizeResult{Found: make(map[uint8]int)}
+
+	for {
+		changed := false
+
+		// fixed order, regardless of parameter order
+		for enc := EncodingHTMLEntity; enc <= EncodingDataURIBase64; enc++ {
+			if !enabled[enc] {
+				continue
+			}
+
+			tmpstr := s.decodeLayer(enc, str)
+			if tmpstr == str {
+				continue
+			}
+
+			if _, ok := retval.Found[enc]; !ok {
+				retval.Encodings = append(retval.Encodings, enc)
+			}
+			retval.Found[enc]++
+
+			str = tmpstr
+			changed = true
+		}
+
+		if !changed {
+			break
+		}
+
+		retval.Depth++
+		if retval.Depth >= canonicalizeMaxDepth {
+			retval.Str = str
+			return retval, fmt.Errorf("Exceeded maximum decoding depth : %d", canonicalizeMaxDepth)
+		}
+	}
+
+	retval.Str = str
+	return retval, nil
+}
+
+func (s *StringProc) decodeLayer(enc uint8, str string) string {
+	switch enc {
+	case EncodingHTMLEntity:
+		if hasHTMLEntities(str) {
+			return decodeHTMLEntities(str)
+		}
+	case EncodingUnicodePercent:
+		return s.decodeUnicodePercent(str)
+	case EncodingPercent:
+		return s.decodePercent(str, false)
+	case EncodingFormURL:
+		return s.decodePercent(str, true)
+	case EncodingJavaScript:
+		return s.decodeJavaScriptEscape(str)
+	case EncodingCSS:
+		return s.decodeCSSEscape(str)
+	case EncodingDataURIBase64:
+		return s.decodeDataURIBase64(str)
+	}
+
+	return str
+}
+
+// parseHexRun parses up to max hex digits from str[i:], returns value and the number of digits
+func (s *StringProc) parseHexRun(str string, i int, max int) (rune, int) {
+	var v rune
+	n := 0
+	for ; n < max && i+n < len(str) && s.isHex(str[i+n]); n++ {
+		v = v<<4 | rune(s.unHex(str[i+n]))
+	}
+
+	return v, n
+}
+
+func appendCodePoint(buf []byte, r rune) []byte {
+	if r == 0 || r > utf8.MaxRune || (r >= 0xD800 && r <= 0xDFFF) {
+		r = utf8.RuneError
+	}
+
+	return append(buf, string(r)...)
+}
+
+// decodePercent decodes %HH , invalid sequences are kept as it is
+// if form is true, + is decoded to space when the string has one or more %HH
+func (s *StringProc) decodePercent(str string, form bool) string {
+	l := len(str)
+	if strings.IndexByte(str, 37) < 0 && !form { // %
+		return str
+	}
+
+	buf := make([]byte, 0, l) // prealloca
+	found := false
+
+	for i := 0; i < l; i++ {
+		if str[i] == 37 && i+2 < l && s.isHex(str[i+1]) && s.isHex(str[i+2]) { // %HH
+			buf = append(buf, s.unHex(str[i+1])<<4|s.unHex(str[i+2]))
+			i += 2
+			found = true
+			continue
+		}
+
+		buf = append(buf, str[i])
+	}
+
+	if !found {
+		return str
+	}
+
+	if form {
+		for k, v := range buf {
+			if v == 43 { // +
+				buf[k] = 32
+			}
+		}
+	}
+
+	return string(buf)
+}
+
+// decodeUnicodePercent decodes %uHHHH , invalid sequences are kept as it is
+func (s *StringProc) decodeUnicodePercent(str string) string {
+	l := len(str)
+	if !strings.Contains(str, "%u") && !strings.Contains(str, "%U") {
+		return str
+	}
+
+	buf := make([]byte, 0, l) // prealloca
+
+	for i := 0; i < l; i++ {
+		if str[i] == 37 && i+5 < l && (str[i+1] == 117 || str[i+1] == 85) { // % + u|U
+			if r, n := s.parseHexRun(str, i+2, 4); n == 4 {
+				buf = appendCodePoint(buf, r)
+				i += 5
+				continue
+			}
+		}
+
+		buf = append(buf, str[i])
+	}
+
+	return string(buf)
+}
+
+// decodeJavaScriptEscape decodes \xHH , \uHHHH , \u{H...} , \n \r \t \v and \\ \' \" \/
+// NOTE : octal and \b \f are left to EncodingCSS, both are hex digits in CSS
+func (s *StringProc) decodeJavaScriptEscape(str string) string {
+	l := len(str)
+	if strings.IndexByte(str, 92) < 0 { // backslash
+		return str
+	}
+
+	buf := make([]byte, 0, l) // prealloca
+
+	for i := 0; i < l; i++ {
+		if str[i] != 92 || i+1 >= l {
+			buf = append(buf, str[i])
+			continue
+		}
+
+		switch str[i+1] {
+		case 'x':
+			if r, n := s.parseHexRun(str, i+2, 2); n == 2 {
+				buf = appendCodePoint(buf, r)
+				i += 3
+				continue
+			}
+		case 'u':
+			if i+2 < l && str[i+2] == '{' {
+				r, n := s.parseHexRun(str, i+3, 6)
+				if n > 0 && i+3+n < l && str[i+3+n] == '}' {
+					buf = appendCodePoint(buf, r)
+					i += 3 + n
+					continue
+				}
+			} else if r, n := s.parseHexRun(str, i+2, 4); n == 4 {
+				buf = appendCodePoint(buf, r)
+				i += 5
+				continue
+			}
+		case 'n':
+			buf = append(buf, 10)
+			i++
+			continue
+		case 'r':
+			buf = append(buf, 13)
+			i++
+			continue
+		case 't':
+			buf = append(buf, 9)
+			i++
+			continue
+		case 'v':
+			buf = append(buf, 11)
+			i++
+			continue
+		case '\\', '\'', '"', '/':
+			buf = append(buf, str[i+1])
+			i++
+			continue
+		}
+
+		buf = append(buf, str[i])
+	}
+
+	return string(buf)
+}
+
+// decodeCSSEscape decodes \H ~ \HHHHHH (and one trailing white space), \(newline) and \(any other character)
+// referrer : https://www.w3.org/TR/css-syntax-3/#consume-escaped-code-point
+func (s *StringProc) decodeCSSEscape(str string) string {
+	l := len(str)
+	if strings.IndexByte(str, 92) < 0 { // backslash
+		return str
+	}
+
+	buf := make([]byte, 0, l) // prealloca
+
+	for i := 0; i < l; i++ {
+		if str[i] != 92 || i+1 >= l {
+			buf = append(buf, str[i])
+			continue
+		}
+
+		if r, n := s.parseHexRun(str, i+1, 6); n > 0 {
+			buf = appendCodePoint(buf, r)
+			i += n
+
+			// one white space after hex digits is a part of the escape
+			if i+1 < l && (str[i+1] == 32 || str[i+1] == 9 || str[i+1] == 10) {
+				i++
+			} else if i+2 < l && str[i+1] == 13 && str[i+2] == 10 {
+				i += 2
+			}
+			continue
+		}
+
+		switch str[i+1] {
+		case 10, 12: // line continuation
+			i++
+		case 13:
+			i++
+			if i+1 < l && str[i+1] == 10 {
+				i++
+			}
+		default:
+			_, size := utf8.DecodeRuneInString(str[i+1:])
+			buf = append(buf, str[i+1:i+1+size]...)
+			i += size
+		}
+	}
+
+	return string(buf)
+}
+
+// decodeDataURIBase64 decodes base64 payload of data URIs to the plain data URIs (data:text/html;base64,PGI+ => data:text/html,<b>)
+func (s *StringProc) decodeDataURIBase64(str string) string {
+	if !strings.Contains(strings.ToLower(str), "base64,") {
+		return str
+	}
+
+	return dataURIBase64Pattern.ReplaceAllStringFunc(str, func(m string) string {
+		sub := dataURIBase64Pattern.FindStringSubmatch(m)
+		payload := sub[3]
+
+		var data []byte
+		var err error
+
+		if strings.ContainsAny(payload, "-_") {
+			data, err = base64.URLEncoding.DecodeString(payload)
+			if err != nil {
+				data, err = base64.RawURLEncoding.DecodeString(strings.TrimRight(payload, "="))
+			}
+		} else {
+			data, err = base64.StdEncoding.DecodeString(payload)
+			if err != nil {
+				data, err = base64.RawStdEncoding.DecodeString(strings.TrimRight(payload, "="))
+			}
+		}
+
+		if err != nil {
+			return m
+		}
+
+		return "data:" + sub[1] + sub[2] + "," + string(data)
+	})
+}
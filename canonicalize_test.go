@@ -0,0 +1,73 @@
+package strutils_test
+
+import (
+	"strings"
+	"testing"
+
+	strutils "github.com/torden/go-strutil"
+)
+
+func Test_strutils_Canonicalize(t *testing.T) {
+	t.Parallel()
+
+	dataset := []struct {
+		str        string
+		ok         string
+		depth      int
+		encodings  []uint8
+		suspicious bool
+	}{
+		{"abcdefg", "abcdefg", 0, nil, false},
+		{"&lt;script&gt;", "<script>", 1, []uint8{strutils.EncodingHTMLEntity}, false},
+		{"%3Cscript%3E", "<script>", 1, []uint8{strutils.EncodingPercent}, false},
+		{"%u003Cscript%u003E", "<script>", 1, []uint8{strutils.EncodingUnicodePercent}, false},
+		{`\x3cscript>`, "<script>", 1, []uint8{strutils.EncodingJavaScript}, false},
+		{`\u{3c}script\u{3E}`, "<script>", 1, []uint8{strutils.EncodingJavaScript}, false},
+		{`\3c script\00003E`, "<script>", 1, []uint8{strutils.EncodingCSS}, false},
+		{"data:text/html;base64,PHNjcmlwdD4=", "data:text/html,<script>", 1, []uint8{strutils.EncodingDataURIBase64}, false},
+		{"%253Cscript%253E", "<script>", 2, []uint8{strutils.EncodingPercent}, true},
+		{"%26lt%3Bscript%26gt%3B", "<script>", 2, []uint8{strutils.EncodingPercent, strutils.EncodingHTMLEntity}, true},
+		{"&#x25;3Cscript&#x25;3E", "<script>", 1, []uint8{strutils.EncodingHTMLEntity, strutils.EncodingPercent}, true},
+		{`%5Cx3cscript%5Cx3e`, "<script>", 1, []uint8{strutils.EncodingPercent, strutils.EncodingJavaScript}, true},
+		{"ABC PERC%NT DEF", "ABC PERC%NT DEF", 0, nil, false},
+		{"%u00", "%u00", 0, nil, false},
+		{"1+1", "1+1", 0, nil, false},
+	}
+
+	// check : common
+	for _, v := range dataset {
+		retval, err := strproc.Canonicalize(v.str)
+		assert.AssertNil(t, err, "Error : %v", err)
+		assert.AssertEquals(t, retval.Str, v.ok, "Return Value mismatch.\nExpected: %v\nActual: %v", v.ok, retval.Str)
+		assert.AssertEquals(t, retval.Depth, v.depth, "Depth mismatch (%v).\nExpected: %v\nActual: %v", v.str, v.depth, retval.Depth)
+		assert.AssertEquals(t, len(retval.Encodings), len(v.encodings), "Encodings mismatch (%v).\nExpected: %v\nActual: %v", v.str, v.encodings, retval.Encodings)
+		for k := range v.encodings {
+			if k < len(retval.Encodings) {
+				assert.AssertEquals(t, retval.Encodings[k], v.encodings[k], "Encodings mismatch (%v).\nExpected: %v\nActual: %v", v.str, v.encodings, retval.Encodings)
+			}
+		}
+		assert.AssertEquals(t, retval.IsSuspicious(), v.suspicious, "Suspicious mismatch (%v).\nExpected: %v\nActual: %v", v.str, v.suspicious, retval.IsSuspicious())
+	}
+
+	// check : form url
+	retval, err := strproc.Canonicalize("a+b%21", strutils.EncodingFormURL)
+	assert.AssertNil(t, err, "Error : %v", err)
+	assert.AssertEquals(t, retval.Str, "a b!", "Return Value mismatch.\nExpected: %v\nActual: %v", "a b!", retval.Str)
+
+	// check : encoding names
+	retval, _ = strproc.Canonicalize("%26lt%3B")
+	assert.AssertEquals(t, strings.Join(retval.EncodingNames(), ","), "percent,html entity", "Return Value mismatch.\nActual: %v", retval.EncodingNames())
+	assert.AssertTrue(t, retval.IsMixed(), "Couldn't detect the mixed encoding")
+	assert.AssertTrue(t, retval.IsMultiple(), "Couldn't detect the multiple encoding")
+
+	// check : max depth
+	_, err = strproc.Canonicalize("%" + strings.Repeat("25", 20) + "3C")
+	assert.AssertNotNil(t, err, "Couldn't check the `maximum decoding depth`\nError : %v", err)
+
+	// check : not allow encodings
+	_, err = strproc.Canonicalize("abc", 0)
+	assert.AssertNotNil(t, err, "Couldn't check the `not allow encodings`\nError : %v", err)
+
+	_, err = strproc.Canonicalize("abc", 200)
+	assert.AssertNotNil(t, err, "Couldn't check the `not allow encodings`\nError : %v", err)
+}
@@ -0,0 +1,387 @@
+package strutils
+
+import (
+	"fmt"
+)
+
+// ChainError is the error of the first failed step of StringChain
+type ChainError struct {
+	Step  int    // number of the step, from 1
+	Name  string // name of the step (method name)
+	Input string // input string of the step
+	Err   error
+}
+
+func (e *ChainError) Error() string {
+	return fmt.Sprintf("chain step %d (%s) : %v", e.Step, e.Name, e.Err)
+}
+
+// Unwrap returns the error of the step
+func (e *ChainError) Unwrap() error {
+	return e.Err
+}
+
+// StringChain is the fluent chain of the StringProc methods, created by Chain
+// the first error short-circuits the rest of the steps, and is returned by Result as *ChainError
+type StringChain struct {
+	s    StringProc
+	str  string
+	step int
+	err  error
+}
+
+// Chain Creates and returns a StringChain of the string
+// Ex) strutils.Chain(str).Br2Nl().DecodeURLEncoded().StripTags().WordWrapAround(80, "\n").Result()
+func Chain(str string) *StringChain {
+	return &StringChain{str: str}
+}
+
+// Apply is the step of the function, the name is used for ChainError
+func (c *StringChain) Apply(name string, fn func(str string) (string, error)) *StringChain {
+	if c.err != nil {
+		return c
+	}
+
+	c.step++
+
+	retval, err := fn(c.str)
+	if err != nil {
+		c.err = &ChainError{Step: c.step, Name: name, Input: c.str, Err: err}
+		return c
+	}
+
+	c.str = retval
+	return c
+}
+
+// Result returns the string of the last step, or the error of the first failed step (*ChainError)
+func (c *StringChain) Result() (string, error) {
+	if c.err != nil {
+		return "", c.err
+	}
+
+	return c.str, nil
+}
+
+// Err returns the error of the first failed step (*ChainError), nil if not failed
+func (c *StringChain) Err() error {
+	return c.err
+}
+
+// String returns the string of the last succeeded step (fmt.Stringer)
+func (c *StringChain) String() string {
+	return c.str
+}
+
+// AddSlashes is the step of StringProc.AddSlashes
+func (c *StringChain) AddSlashes() *StringChain {
+	return c.Apply("AddSlashes", func(str string) (string, error) {
+		return c.s.AddSlashes(str), nil
+	})
+}
+
+// StripSlashes is the step of StringProc.StripSlashes
+func (c *StringChain) StripSlashes() *StringChain {
+	return c.Apply("StripSlashes", func(str string) (string, error) {
+		return c.s.StripSlashes(str), nil
+	})
+}
+
+// StripSlashesWithMode is the step of StringProc.StripSlashesWithMode
+func (c *StringChain) StripSlashesWithMode(mode int) *StringChain {
+	return c.Apply("StripSlashesWithMode", func(str string) (string, error) {
+		return c.s.StripSlashesWithMode(str, mode)
+	})
+}
+
+// Nl2Br is the step of StringProc.Nl2Br
+func (c *StringChain) Nl2Br() *StringChain {
+	return c.Apply("Nl2Br", func(str string) (string, error) {
+		return c.s.Nl2Br(str), nil
+	})
+}
+
+// Br2Nl is the step of StringProc.Br2Nl
+func (c *StringChain) Br2Nl() *StringChain {
+	return c.Apply("Br2Nl", func(str string) (string, error) {
+		return c.s.Br2Nl(str), nil
+	})
+}
+
+// Nl2BrWithOptions is the step of StringProc.Nl2BrWithOptions
+func (c *StringChain) Nl2BrWithOptions(opts *Nl2BrOptions) *StringChain {
+	return c.Apply("Nl2BrWithOptions", func(str string) (string, error) {
+		return c.s.Nl2BrWithOptions(str, opts)
+	})
+}
+
+// Br2NlWithOptions is the step of StringProc.Br2NlWithOptions
+func (c *StringChain) Br2NlWithOptions(opts *Nl2BrOptions) *StringChain {
+	return c.Apply("Br2NlWithOptions", func(str string) (string, error) {
+		return c.s.Br2NlWithOptions(str, opts), nil
+	})
+}
+
+// WordWrapSimple is the step of StringProc.WordWrapSimple
+func (c *StringChain) WordWrapSimple(wd int, breakstr string) *StringChain {
+	return c.Apply("WordWrapSimple", func(str string) (string, error) {
+		return c.s.WordWrapSimple(str, wd, breakstr)
+	})
+}
+
+// WordWrapAround is the step of StringProc.WordWrapAround
+func (c *StringChain) WordWrapAround(wd int, breakstr string) *StringChain {
+	return c.Apply("WordWrapAround", func(str string) (string, error) {
+		return c.s.WordWrapAround(str, wd, breakstr)
+	})
+}
+
+// NumberFmt is the step of StringProc.NumberFmt
+func (c *StringChain) NumberFmt() *StringChain {
+	return c.Apply("NumberFmt", func(str string) (string, error) {
+		return c.s.NumberFmt(str)
+	})
+}
+
+// PaddingBoth is the step of StringProc.PaddingBoth
+func (c *StringChain) PaddingBoth(fill string, mx int) *StringChain {
+	return c.Apply("PaddingBoth", func(str string) (string, error) {
+		return c.s.PaddingBoth(str, fill, mx), nil
+	})
+}
+
+// PaddingLeft is the step of StringProc.PaddingLeft
+func (c *StringChain) PaddingLeft(fill string, mx int) *StringChain {
+	return c.Apply("PaddingLeft", func(str string) (string, error) {
+		return c.s.PaddingLeft(str, fill, mx), nil
+	})
+}
+
+// PaddingRight is the step of StringProc.PaddingRight
+func (c *StringChain) PaddingRight(fill string, mx int) *StringChain {
+	return c.Apply("PaddingRight", func(str string) (string, error) {
+		return c.s.PaddingRight(str, fill, mx), nil
+	})
+}
+
+// Padding is the step of StringProc.Padding
+func (c *StringChain) Padding(fill string, m int, mx int) *StringChain {
+	return c.Apply("Padding", func(str string) (string, error) {
+		return c.s.Padding(str, fill, m, mx), nil
+	})
+}
+
+// LowerCaseFirstWords is the step of StringProc.LowerCaseFirstWords
+func (c *StringChain) LowerCaseFirstWords() *StringChain {
+	return c.Apply("LowerCaseFirstWords", func(str string) (string, error) {
+		return c.s.LowerCaseFirstWords(str), nil
+	})
+}
+
+// UpperCaseFirstWords is the step of StringProc.UpperCaseFirstWords
+func (c *StringChain) UpperCaseFirstWords() *StringChain {
+	return c.Apply("UpperCaseFirstWords", func(str string) (string, error) {
+		return c.s.UpperCaseFirstWords(str), nil
+	})
+}
+
+// SwapCaseFirstWords is the step of StringProc.SwapCaseFirstWords
+func (c *StringChain) SwapCaseFirstWords() *StringChain {
+	return c.Apply("SwapCaseFirstWords", func(str string) (string, error) {
+		return c.s.SwapCaseFirstWords(str), nil
+	})
+}
+
+// HumanByteSize is the step of StringProc.HumanByteSize
+func (c *StringChain) HumanByteSize(decimals int, unit uint8) *StringChain {
+	return c.Apply("HumanByteSize", func(str string) (string, error) {
+		return c.s.HumanByteSize(str, decimals, unit)
+	})
+}
+
+// HumanFileSize is the step of StringProc.HumanFileSize, the string is the file path
+func (c *StringChain) HumanFileSize(decimals int, unit uint8) *StringChain {
+	return c.Apply("HumanFileSize", func(str string) (string, error) {
+		return c.s.HumanFileSize(str, decimals, unit)
+	})
+}
+
+// DecodeUnicodeEntities is the step of StringProc.DecodeUnicodeEntities
+func (c *StringChain) DecodeUnicodeEntities() *StringChain {
+	return c.Apply("DecodeUnicodeEntities", func(str string) (string, error) {
+		return c.s.DecodeUnicodeEntities(str)
+	})
+}
+
+// DecodeURLEncoded is the step of StringProc.DecodeURLEncoded
+func (c *StringChain) DecodeURLEncoded() *StringChain {
+	return c.Apply("DecodeURLEncoded", func(str string) (string, error) {
+		return c.s.DecodeURLEncoded(str)
+	})
+}
+
+// StripTags is the step of StringProc.StripTags
+func (c *StringChain) StripTags() *StringChain {
+	return c.Apply("StripTags", func(str string) (string, error) {
+		return c.s.StripTags(str)
+	})
+}
+
+// ReverseStr is the step of StringProc.ReverseStr
+func (c *StringChain) ReverseStr() *StringChain {
+	return c.Apply("ReverseStr", func(str string) (string, error) {
+		return c.s.ReverseStr(str), nil
+	})
+}
+
+// ReverseNormalStr is the step of StringProc.ReverseNormalStr
+func (c *StringChain) ReverseNormalStr() *StringChain {
+	return c.Apply("ReverseNormalStr", func(str string) (string, error) {
+		return c.s.ReverseNormalStr(str), nil
+	})
+}
+
+// ReverseUnicode is the step of StringProc.ReverseUnicode
+func (c *StringChain) ReverseUnicode() *StringChain {
+	return c.Apply("ReverseUnicode", func(str string) (string, error) {
+		return c.s.ReverseUnicode(str), nil
+	})
+}
+
+// FileMD5Hash is the step of StringProc.FileMD5Hash, the string is the file path
+func (c *StringChain) FileMD5Hash() *StringChain {
+	return c.Apply("FileMD5Hash", func(str string) (string, error) {
+		return c.s.FileMD5Hash(str)
+	})
+}
+
+// MD5Hash is the step of StringProc.MD5Hash
+func (c *StringChain) MD5Hash() *StringChain {
+	return c.Apply("MD5Hash", func(str string) (string, error) {
+		return c.s.MD5Hash(str)
+	})
+}
+
+// EncodeHTMLEntities is the step of StringProc.EncodeHTMLEntities
+func (c *StringChain) EncodeHTMLEntities(mode int) *StringChain {
+	return c.Apply("EncodeHTMLEntities", func(str string) (string, error) {
+		return c.s.EncodeHTMLEntities(str, mode)
+	})
+}
+
+// DecodeHTMLEntities is the step of StringProc.DecodeHTMLEntities
+func (c *StringChain) DecodeHTMLEntities() *StringChain {
+	return c.Apply("DecodeHTMLEntities", func(str string) (string, error) {
+		return c.s.DecodeHTMLEntities(str), nil
+	})
+}
+
+// Canonicalize is the step of StringProc.Canonicalize, the result is the canonicalized string (CanonicalizeResult.Str)
+func (c *StringChain) Canonicalize(encodings ...uint8) *StringChain {
+	return c.Apply("Canonicalize", func(str string) (string, error) {
+		retval, err := c.s.Canonicalize(str, encodings...)
+		if err != nil {
+			return "", err
+		}
+
+		return retval.Str, nil
+	})
+}
+
+// StripTagsWithPolicy is the step of StringProc.StripTagsWithPolicy
+func (c *StringChain) StripTagsWithPolicy(policy *StripTagsPolicy) *StringChain {
+	return c.Apply("StripTagsWithPolicy", func(str string) (string, error) {
+		return c.s.StripTagsWithPolicy(str, policy)
+	})
+}
+
+// HTML2Text is the step of StringProc.HTML2Text
+func (c *StringChain) HTML2Text(linkStyle int) *StringChain {
+	return c.Apply("HTML2Text", func(str string) (string, error) {
+		return c.s.HTML2Text(str, linkStyle)
+	})
+}
+
+// Markdown2HTML is the step of StringProc.Markdown2HTML
+func (c *StringChain) Markdown2HTML() *StringChain {
+	return c.Apply("Markdown2HTML", func(str string) (string, error) {
+		return c.s.Markdown2HTML(str)
+	})
+}
+
+// HTML2Markdown is the step of StringProc.HTML2Markdown
+func (c *StringChain) HTML2Markdown() *StringChain {
+	return c.Apply("HTML2Markdown", func(str string) (string, error) {
+		return c.s.HTML2Markdown(str)
+	})
+}
+
+// EscapeString is the step of StringProc.EscapeString
+func (c *StringChain) EscapeString(dialect int) *StringChain {
+	return c.Apply("EscapeString", func(str string) (string, error) {
+		return c.s.EscapeString(str, dialect)
+	})
+}
+
+// UnescapeString is the step of StringProc.UnescapeString
+func (c *StringChain) UnescapeString(dialect int) *StringChain {
+	return c.Apply("UnescapeString", func(str string) (string, error) {
+		return c.s.UnescapeString(str, dialect)
+	})
+}
+
+// GraphemeAt is the step of StringProc.GraphemeAt
+func (c *StringChain) GraphemeAt(idx int) *StringChain {
+	return c.Apply("GraphemeAt", func(str string) (string, error) {
+		return c.s.GraphemeAt(str, idx)
+	})
+}
+
+// GraphemeTruncate is the step of StringProc.GraphemeTruncate
+func (c *StringChain) GraphemeTruncate(mx int, tail string) *StringChain {
+	return c.Apply("GraphemeTruncate", func(str string) (string, error) {
+		return c.s.GraphemeTruncate(str, mx, tail)
+	})
+}
+
+// ReverseGraphemes is the step of StringProc.ReverseGraphemes
+func (c *StringChain) ReverseGraphemes() *StringChain {
+	return c.Apply("ReverseGraphemes", func(str string) (string, error) {
+		return c.s.ReverseGraphemes(str), nil
+	})
+}
+
+// Hash is the step of StringProc.Hash
+func (c *StringChain) Hash(algo uint8, output ...uint8) *StringChain {
+	return c.Apply("Hash", func(str string) (string, error) {
+		return c.s.Hash(str, algo, output...)
+	})
+}
+
+// FileHash is the step of StringProc.FileHash, the string is the file path
+func (c *StringChain) FileHash(algo uint8, output ...uint8) *StringChain {
+	return c.Apply("FileHash", func(str string) (string, error) {
+		return c.s.FileHash(str, algo, output...)
+	})
+}
+
+// HMAC is the step of StringProc.HMAC
+func (c *StringChain) HMAC(key string, algo uint8, output ...uint8) *StringChain {
+	return c.Apply("HMAC", func(str string) (string, error) {
+		return c.s.HMAC(str, key, algo, output...)
+	})
+}
+
+// FileHMAC is the step of StringProc.FileHMAC, the string is the file path
+func (c *StringChain) FileHMAC(key string, algo uint8, output ...uint8) *StringChain {
+	return c.Apply("FileHMAC", func(str string) (string, error) {
+		return c.s.FileHMAC(str, key, algo, output...)
+	})
+}
+
+// HKDF is the step of StringProc.HKDF, the string is the secret
+func (c *StringChain) HKDF(salt string, info string, length int, algo uint8, output ...uint8) *StringChain {
+	return c.Apply("HKDF", func(str string) (string, error) {
+		return c.s.HKDF(str, salt, info, length, algo, output...)
+	})
+}
@@ -0,0 +1,109 @@
+package strutils_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	strutils "github.com/torden/go-strutil"
+)
+
+func Test_strutils_Chain(t *testing.T) {
+	t.Parallel()
+
+	str := "Just%20a<br />String&nbsp;<b>Processing</b> Library%21<BR>for Go-lang"
+
+	// check : same as the StringProc methods
+	expected := strproc.Br2Nl(str)
+	expected, _ = strproc.DecodeURLEncoded(expected)
+	expected, _ = strproc.StripTags(expected)
+	expected, _ = strproc.WordWrapAround(expected, 10, "\n")
+	expected = strproc.UpperCaseFirstWords(expected)
+
+	retval, err := strutils.Chain(str).Br2Nl().DecodeURLEncoded().StripTags().WordWrapAround(10, "\n").UpperCaseFirstWords().Result()
+	assert.AssertNil(t, err, "Error : %v", err)
+	assert.AssertEquals(t, retval, expected, "Return Value mismatch.\nExpected: %q\nActual: %q", expected, retval)
+
+	dataset := []struct {
+		chain *strutils.StringChain
+		str   string
+	}{
+		{strutils.Chain("abc").ReverseStr().PaddingBoth("*", 7), "**cba**"},
+		{strutils.Chain(`a\b`).AddSlashes().StripSlashes(), `a\b`},
+		{strutils.Chain("1234567").NumberFmt(), "1,234,567"},
+		{strutils.Chain("2048").HumanByteSize(1, strutils.UpperCaseDouble), "2.0KB"},
+		{strutils.Chain("123456789").MD5Hash(), "25f9e794323b453885f5181f1b624d0b"},
+		{strutils.Chain("%26lt%3Bb%26gt%3B").Canonicalize(strutils.EncodingHTMLEntity, strutils.EncodingFormURL), "<b>"},
+		{strutils.Chain("<a>&</a>").EncodeHTMLEntities(strutils.EntityEncodeMinimal), "&lt;a&gt;&amp;&lt;/a&gt;"},
+		{strutils.Chain("café bar").GraphemeTruncate(6, "~").ReverseGraphemes(), "~ éfac"},
+		{strutils.Chain("a b").Apply("Custom", func(str string) (string, error) { return "[" + str + "]", nil }).Nl2Br(), "[a b]"},
+		{strutils.Chain(""), ""},
+	}
+
+	// check : common
+	for _, v := range dataset {
+		retval, err := v.chain.Result()
+		assert.AssertNil(t, err, "Error : %v", err)
+		assert.AssertEquals(t, retval, v.str, "Return Value mismatch.\nExpected: %+q\nActual: %+q", v.str, retval)
+		assert.AssertEquals(t, v.chain.String(), v.str, "Return Value mismatch of String().\nExpected: %+q\nActual: %+q", v.str, v.chain.String())
+	}
+}
+
+func Test_strutils_ChainError(t *testing.T) {
+	t.Parallel()
+
+	called := false
+	chain := strutils.Chain("a b c").
+		UpperCaseFirstWords().
+		WordWrapSimple(0, "\n").
+		Apply("Never", func(str string) (string, error) {
+			called = true
+			return str, nil
+		}).
+		Nl2Br()
+
+	retval, err := chain.Result()
+	assert.AssertNotNil(t, err, "Couldn't check the `short-circuit`\nError : %v", err)
+	assert.AssertEquals(t, retval, "", "Return Value mismatch.\nExpected: %q\nActual: %q", "", retval)
+	assert.AssertFalse(t, called, "Called the step after the error")
+	assert.AssertEquals(t, chain.String(), "A B C", "Return Value mismatch of String().\nExpected: %q\nActual: %q", "A B C", chain.String())
+	assert.AssertEquals(t, chain.Err(), err, "Error mismatch of Err().\nExpected: %v\nActual: %v", err, chain.Err())
+
+	var chainErr *strutils.ChainError
+	assert.AssertTrue(t, errors.As(err, &chainErr), "Error is not *ChainError : %T", err)
+	assert.AssertEquals(t, chainErr.Step, 2, "Step mismatch.\nExpected: %v\nActual: %v", 2, chainErr.Step)
+	assert.AssertEquals(t, chainErr.Name, "WordWrapSimple", "Name mismatch.\nExpected: %v\nActual: %v", "WordWrapSimple", chainErr.Name)
+	assert.AssertEquals(t, chainErr.Input, "A B C", "Input mismatch.\nExpected: %v\nActual: %v", "A B C", chainErr.Input)
+	assert.AssertEquals(t, err.Error(), "chain step 2 (WordWrapSimple) : wd At least 1 or More", "Error mismatch.\nExpected: %v\nActual: %v", "chain step 2 (WordWrapSimple) : wd At least 1 or More", err.Error())
+
+	// check : the error of the custom step is unwrapped
+	stepErr := errors.New("custom error")
+	_, err = strutils.Chain("x").Apply("Custom", func(str string) (string, error) { return "", stepErr }).Result()
+	assert.AssertTrue(t, errors.Is(err, stepErr), "Error is not unwrapped : %v", err)
+}
+
+// check : every StringProc method of the string to string has the chain method with the same parameters
+func Test_strutils_ChainMethods(t *testing.T) {
+	t.Parallel()
+
+	procType := reflect.TypeOf(strproc)
+	chainType := reflect.TypeOf(strutils.Chain(""))
+
+	for i := 0; i < procType.NumMethod(); i++ {
+		m := procType.Method(i)
+		if m.Type.NumIn() < 2 || m.Type.In(1).Kind() != reflect.String || m.Type.NumOut() < 1 || m.Type.Out(0).Kind() != reflect.String {
+			continue
+		}
+
+		cm, ok := chainType.MethodByName(m.Name)
+		assert.AssertTrue(t, ok, "Not exists the chain method : %v", m.Name)
+		if !ok {
+			continue
+		}
+
+		assert.AssertEquals(t, cm.Type.NumIn(), m.Type.NumIn()-1, "Parameters mismatch of the chain method %v.\nExpected: %v\nActual: %v", m.Name, m.Type.NumIn()-1, cm.Type.NumIn())
+		for no := 1; no < cm.Type.NumIn() && no+1 < m.Type.NumIn(); no++ {
+			assert.AssertTrue(t, cm.Type.In(no) == m.Type.In(no+1), "Parameter type mismatch of the chain method %v (%v).\nExpected: %v\nActual: %v", m.Name, no, m.Type.In(no+1), cm.Type.In(no))
+		}
+	}
+}
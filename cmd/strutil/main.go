@@ -0,0 +1,533 @@
+// Command strutil is the command-line tool of the go-strutil, a Unix filter of the StringProc and the StringValidator
+//
+//	strutil numfmt [--json] [number...]
+//	strutil humansize [--json] [-d decimals] [-u unit] [size...]
+//	strutil wrap [--json] [-w width] [-b break] [--simple] [string...]
+//	strutil pad [--json] [--left|--right|--both] -w width [-f fill] [string...]
+//	strutil strip-tags|nl2br|decode-url [--json] [string...]
+//	strutil md5 [--json] [file...]
+//	strutil validate [--json] [-q] email|domain|url|ip|mac|path [string...]
+//	strutil compare [--json] a.json b.json
+//
+// the arguments are processed one by one, or the lines of the stdin if no arguments
+// nl2br keeps the newline of the line, the newline is converted to <br />
+//
+// exit codes : 0 success, 1 invalid, different or failed to process the input, 2 usage or I/O error
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	strutils "github.com/torden/go-strutil"
+)
+
+// Version and BuildTime are set by the Makefile (-ldflags)
+var (
+	Version   = "dev"
+	BuildTime = ""
+)
+
+// exit codes
+const (
+	exitOK    = 0 // success
+	exitFail  = 1 // invalid, different or failed to process the input
+	exitUsage = 2 // usage or I/O error
+)
+
+var (
+	strproc      = strutils.NewStringProc()
+	strvalidator = strutils.NewStringValidator()
+)
+
+// size units of humansize -u
+var byteUnits = map[string]uint8{
+	"lower_single": strutils.LowerCaseSingle,
+	"lower_double": strutils.LowerCaseDouble,
+	"upper_single": strutils.UpperCaseSingle,
+	"upper_double": strutils.UpperCaseDouble,
+	"camel_double": strutils.CamelCaseDouble,
+	"camel_long":   strutils.CamelCaseLong,
+}
+
+// validators of validate
+var validators = map[string]func(str string) bool{
+	"email":  strvalidator.IsValidEmail,
+	"domain": strvalidator.IsValidDomain,
+	"url":    strvalidator.IsValidURL,
+	"mac":    strvalidator.IsValidMACAddr,
+	"path":   strvalidator.IsValidFilePath,
+	"ip": func(str string) bool {
+		retval, _ := strvalidator.IsValidIPAddr(str, strutils.IPAny)
+		return retval
+	},
+}
+
+// cli is the context of the command
+type cli struct {
+	name    string
+	usage   string
+	stdin   io.Reader
+	stdout  io.Writer
+	stderr  io.Writer
+	json    bool
+	newline bool // the lines of the stdin keep the newline
+}
+
+// command is the subcommand of strutil
+type command struct {
+	usage string
+	run   func(c *cli, args []string) int
+}
+
+var commands = map[string]command{
+	"numfmt":     {"[number...]", runNumFmt},
+	"humansize":  {"[-d decimals] [-u unit] [size...]", runHumanSize},
+	"wrap":       {"[-w width] [-b break] [--simple] [string...]", runWrap},
+	"pad":        {"[--left|--right|--both] -w width [-f fill] [string...]", runPad},
+	"strip-tags": {"[string...]", runStripTags},
+	"nl2br":      {"[string...]", runNl2Br},
+	"decode-url": {"[string...]", runDecodeURL},
+	"md5":        {"[file...]", runMD5},
+	"validate":   {"[-q] email|domain|url|ip|mac|path [string...]", runValidate},
+	"compare":    {"a.json b.json", runCompare},
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run is the strutil with the arguments (without the program name), returns the exit code
+func run(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	if len(args) < 1 {
+		usage(stderr)
+		return exitUsage
+	}
+
+	switch args[0] {
+	case "help", "-h", "-help", "--help":
+		usage(stdout)
+		return exitOK
+
+	case "version", "-version", "--version":
+		fmt.Fprintln(stdout, strings.TrimSpace("strutil "+Version+" "+BuildTime))
+		return exitOK
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "strutil: unknown command %q\n", args[0])
+		usage(stderr)
+		return exitUsage
+	}
+
+	return cmd.run(&cli{name: args[0], usage: cmd.usage, stdin: stdin, stdout: stdout, stderr: stderr}, args[1:])
+}
+
+// usage prints the usage of strutil
+func usage(w io.Writer) {
+	names := make([]string, 0, len(commands))
+	for k := range commands {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintln(w, "Usage: strutil <command> [--json] [options] [arguments]")
+	fmt.Fprintln(w, "")
+	for _, v := range names {
+		fmt.Fprintf(w, "  strutil %s %s\n", v, commands[v].usage)
+	}
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, "The arguments are processed one by one, or the lines of the stdin if no arguments.")
+	fmt.Fprintln(w, "Exit codes : 0 success, 1 invalid, different or failed to process the input, 2 usage or I/O error")
+}
+
+// flags returns the FlagSet of the command with --json
+func (c *cli) flags() *flag.FlagSet {
+	fs := flag.NewFlagSet("strutil "+c.name, flag.ContinueOnError)
+	fs.SetOutput(c.stderr)
+	fs.BoolVar(&c.json, "json", false, "output JSON lines")
+	fs.Usage = func() {
+		fmt.Fprintf(c.stderr, "Usage: strutil %s [--json] %s\n", c.name, c.usage)
+		fs.PrintDefaults()
+	}
+
+	return fs
+}
+
+// parse parses the flags, returns false if failed (the usage is printed)
+func (c *cli) parse(fs *flag.FlagSet, args []string) bool {
+	return fs.Parse(args) == nil
+}
+
+// errorf prints the error message to stderr
+func (c *cli) errorf(format string, a ...interface{}) {
+	fmt.Fprintf(c.stderr, "strutil %s: %s\n", c.name, fmt.Sprintf(format, a...))
+}
+
+// writeJSON prints the JSON line
+func (c *cli) writeJSON(v interface{}) {
+	buf, _ := json.Marshal(v)
+	fmt.Fprintf(c.stdout, "%s\n", buf)
+}
+
+// eachInput calls fn with the arguments, or the lines of the stdin if no arguments
+func (c *cli) eachInput(args []string, fn func(str string)) error {
+	if len(args) > 0 {
+		for _, v := range args {
+			fn(v)
+		}
+
+		return nil
+	}
+
+	scanner := bufio.NewScanner(c.stdin)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	if c.newline {
+		scanner.Split(scanLinesNewline)
+	}
+
+	for scanner.Scan() {
+		if c.newline {
+			fn(scanner.Text())
+			continue
+		}
+
+		fn(strings.TrimSuffix(scanner.Text(), "\r"))
+	}
+
+	return scanner.Err()
+}
+
+// scanLinesNewline is the bufio.SplitFunc, same as bufio.ScanLines but keeps the newline
+func scanLinesNewline(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if i := bytes.IndexByte(data, 10); i >= 0 { // NL
+		return i + 1, data[:i+1], nil
+	}
+
+	if atEOF && len(data) > 0 {
+		return len(data), data, nil
+	}
+
+	return 0, nil, nil
+}
+
+// processResult is the JSON output of the processing commands
+type processResult struct {
+	Input  string `json:"input"`
+	Output string `json:"output,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// process prints the result of fn of the each input
+func (c *cli) process(args []string, fn func(str string) (string, error)) int {
+	rc := exitOK
+	err := c.eachInput(args, func(str string) {
+		retval, err := fn(str)
+		if err != nil {
+			rc = exitFail
+		}
+
+		switch {
+		case c.json && err != nil:
+			c.writeJSON(processResult{Input: str, Error: err.Error()})
+		case c.json:
+			c.writeJSON(processResult{Input: str, Output: retval})
+		case err != nil:
+			c.errorf("%q : %v", str, err)
+		default:
+			fmt.Fprintln(c.stdout, retval)
+		}
+	})
+
+	if err != nil {
+		c.errorf("%v", err)
+		return exitUsage
+	}
+
+	return rc
+}
+
+func runNumFmt(c *cli, args []string) int {
+	fs := c.flags()
+	if !c.parse(fs, args) {
+		return exitUsage
+	}
+
+	return c.process(fs.Args(), func(str string) (string, error) {
+		return strproc.NumberFmt(strings.TrimSpace(str))
+	})
+}
+
+func runHumanSize(c *cli, args []string) int {
+	fs := c.flags()
+	decimals := fs.Int("d", 2, "number of the decimal places")
+	unitName := fs.String("u", "upper_double", "unit : lower_single, lower_double, upper_single, upper_double, camel_double or camel_long")
+	if !c.parse(fs, args) {
+		return exitUsage
+	}
+
+	unit, ok := byteUnits[*unitName]
+	if !ok {
+		c.errorf("unknown unit %q", *unitName)
+		return exitUsage
+	}
+
+	return c.process(fs.Args(), func(str string) (string, error) {
+		return strproc.HumanByteSize(strings.TrimSpace(str), *decimals, unit)
+	})
+}
+
+func runWrap(c *cli, args []string) int {
+	fs := c.flags()
+	wd := fs.Int("w", 80, "width")
+	breakstr := fs.String("b", "\n", "line break string")
+	simple := fs.Bool("simple", false, "break at the first space after the width (WordWrapSimple)")
+	if !c.parse(fs, args) {
+		return exitUsage
+	}
+
+	if *wd < 1 {
+		c.errorf("width must be 1 or more : %v", *wd)
+		return exitUsage
+	}
+
+	return c.process(fs.Args(), func(str string) (string, error) {
+		if *simple {
+			return strproc.WordWrapSimple(str, *wd, *breakstr)
+		}
+
+		return strproc.WordWrapAround(str, *wd, *breakstr)
+	})
+}
+
+func runPad(c *cli, args []string) int {
+	fs := c.flags()
+	left := fs.Bool("left", false, "pad on the left (default)")
+	right := fs.Bool("right", false, "pad on the right")
+	both := fs.Bool("both", false, "pad on the both sides")
+	wd := fs.Int("w", 0, "width")
+	fill := fs.String("f", " ", "fill string")
+	if !c.parse(fs, args) {
+		return exitUsage
+	}
+
+	mode, n := strutils.PadLeft, 0
+	for _, v := range []struct {
+		set  bool
+		mode int
+	}{{*left, strutils.PadLeft}, {*right, strutils.PadRight}, {*both, strutils.PadBoth}} {
+		if v.set {
+			mode = v.mode
+			n++
+		}
+	}
+
+	if n > 1 {
+		c.errorf("only one of --left, --right and --both is allowed")
+		return exitUsage
+	}
+
+	if *wd < 1 || len(*fill) < 1 {
+		c.errorf("width must be 1 or more and fill must not be empty")
+		return exitUsage
+	}
+
+	return c.process(fs.Args(), func(str string) (string, error) {
+		return strproc.Padding(str, *fill, mode, *wd), nil
+	})
+}
+
+func runStripTags(c *cli, args []string) int {
+	fs := c.flags()
+	if !c.parse(fs, args) {
+		return exitUsage
+	}
+
+	return c.process(fs.Args(), strproc.StripTags)
+}
+
+func runNl2Br(c *cli, args []string) int {
+	fs := c.flags()
+	if !c.parse(fs, args) {
+		return exitUsage
+	}
+
+	c.newline = true // the newline of the line is the <br />
+	return c.process(fs.Args(), func(str string) (string, error) {
+		return strproc.Nl2Br(str), nil
+	})
+}
+
+func runDecodeURL(c *cli, args []string) int {
+	fs := c.flags()
+	if !c.parse(fs, args) {
+		return exitUsage
+	}
+
+	return c.process(fs.Args(), strproc.DecodeURLEncoded)
+}
+
+// md5Result is the JSON output of md5
+type md5Result struct {
+	File  string `json:"file"`
+	MD5   string `json:"md5,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+func runMD5(c *cli, args []string) int {
+	fs := c.flags()
+	if !c.parse(fs, args) {
+		return exitUsage
+	}
+
+	files := fs.Args()
+	if len(files) < 1 {
+		files = []string{"-"}
+	}
+
+	rc := exitOK
+	for _, v := range files {
+		var retval string
+		var err error
+
+		if v == "-" {
+			var buf []byte
+			if buf, err = io.ReadAll(c.stdin); err == nil {
+				retval, err = strproc.MD5Hash(string(buf))
+			}
+		} else {
+			retval, err = strproc.FileMD5Hash(v)
+		}
+
+		if err != nil {
+			rc = exitUsage
+		}
+
+		switch {
+		case c.json && err != nil:
+			c.writeJSON(md5Result{File: v, Error: err.Error()})
+		case c.json:
+			c.writeJSON(md5Result{File: v, MD5: retval})
+		case err != nil:
+			c.errorf("%v", err)
+		default:
+			fmt.Fprintf(c.stdout, "%s  %s\n", retval, v)
+		}
+	}
+
+	return rc
+}
+
+// validateResult is the JSON output of validate
+type validateResult struct {
+	Input string `json:"input"`
+	Valid bool   `json:"valid"`
+}
+
+func runValidate(c *cli, args []string) int {
+	fs := c.flags()
+	quiet := fs.Bool("q", false, "print nothing, only the exit code")
+	if !c.parse(fs, args) {
+		return exitUsage
+	}
+
+	if fs.NArg() < 1 {
+		fs.Usage()
+		return exitUsage
+	}
+
+	fn, ok := validators[fs.Arg(0)]
+	if !ok {
+		c.errorf("unknown type %q", fs.Arg(0))
+		return exitUsage
+	}
+
+	// prints the valid inputs like grep, or all inputs with the result in JSON
+	rc := exitOK
+	err := c.eachInput(fs.Args()[1:], func(str string) {
+		valid := fn(str)
+		if !valid {
+			rc = exitFail
+		}
+
+		switch {
+		case *quiet:
+		case c.json:
+			c.writeJSON(validateResult{Input: str, Valid: valid})
+		case valid:
+			fmt.Fprintln(c.stdout, str)
+		}
+	})
+
+	if err != nil {
+		c.errorf("%v", err)
+		return exitUsage
+	}
+
+	return rc
+}
+
+// compareResult is the JSON output of compare
+type compareResult struct {
+	Equal      bool   `json:"equal"`
+	Difference string `json:"difference,omitempty"`
+}
+
+func runCompare(c *cli, args []string) int {
+	fs := c.flags()
+	if !c.parse(fs, args) {
+		return exitUsage
+	}
+
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return exitUsage
+	}
+
+	objs := make([]interface{}, 2)
+	for i, v := range fs.Args() {
+		buf, err := os.ReadFile(v)
+		if err != nil {
+			c.errorf("%v", err)
+			return exitUsage
+		}
+
+		if err = json.Unmarshal(buf, &objs[i]); err != nil {
+			c.errorf("%s : %v", v, err)
+			return exitUsage
+		}
+	}
+
+	equal, err := strproc.AnyCompare(objs[0], objs[1])
+
+	var diff string
+	switch {
+	case !equal && err != nil:
+		diff = err.Error()
+	case !equal: // not equal without the reason
+		buf1, _ := json.Marshal(objs[0])
+		buf2, _ := json.Marshal(objs[1])
+		diff = fmt.Sprintf("Different Value : obj1(%s) != obj2(%s)", buf1, buf2)
+	}
+
+	switch {
+	case c.json:
+		c.writeJSON(compareResult{Equal: equal, Difference: diff})
+	case !equal:
+		fmt.Fprintln(c.stdout, diff)
+	}
+
+	if !equal {
+		return exitFail
+	}
+
+	return exitOK
+}
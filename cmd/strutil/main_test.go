@@ -0,0 +1,101 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	strutils "github.com/torden/go-strutil"
+)
+
+var assert = strutils.NewAssert()
+
+func Test_strutil_Run(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"a.json": `{"a":1,"b":[1,{"x":null}],"c":{"d":"e"}}`,
+		"b.json": `{"c":{"d":"e"},"b":[1,{"x":null}],"a":1}`,
+		"c.json": `{"a":1,"b":[1,{"x":2}],"c":{"d":"e"}}`,
+		"d.txt":  "hello",
+		"e.json": `{"a":`,
+		"f.json": `1`,
+		"g.json": `2`,
+		"h.json": `null`,
+	}
+
+	for k, v := range files {
+		err := os.WriteFile(filepath.Join(dir, k), []byte(v), 0644)
+		assert.AssertNil(t, err, "Error : %v", err)
+	}
+
+	dataset := []struct {
+		args   []string
+		stdin  string
+		stdout string
+		rc     int
+	}{
+		{[]string{"numfmt"}, "1234567\n-1234.5\r\n", "1,234,567\n-1,234.5\n", exitOK},
+		{[]string{"numfmt", "1234", "abc"}, "", "1,234\n", exitFail},
+		{[]string{"numfmt", "--json", "1234", "abc"}, "", "{\"input\":\"1234\",\"output\":\"1,234\"}\n{\"input\":\"abc\",\"error\":\"Not Support obj.(string) := abc \"}\n", exitFail},
+		{[]string{"humansize", "-d", "1", "2048", "1048576"}, "", "2.0KB\n1.0MB\n", exitOK},
+		{[]string{"humansize", "-u", "camel_long"}, "2048\n", "2.00KiloByte\n", exitOK},
+		{[]string{"humansize", "-u", "huge", "1"}, "", "", exitUsage},
+		{[]string{"wrap", "-w", "6"}, "just a string here\n", "just a\nstring\nhere\n", exitOK},
+		{[]string{"wrap", "-w", "2", "-b", "|", "--simple", "a bc def"}, "", "a bc|def\n", exitOK},
+		{[]string{"wrap", "-w", "0", "abc"}, "", "", exitUsage},
+		{[]string{"pad", "--left", "-w", "10", "-f", "0", "42"}, "", "0000000042\n", exitOK},
+		{[]string{"pad", "-w", "4", "-f", "*"}, "a\nab\n", "***a\n**ab\n", exitOK},
+		{[]string{"pad", "--right", "-w", "4", "-f", "*", "a"}, "", "a***\n", exitOK},
+		{[]string{"pad", "--both", "-w", "5", "-f", "*", "a"}, "", "**a**\n", exitOK},
+		{[]string{"pad", "--left", "--right", "-w", "3", "a"}, "", "", exitUsage},
+		{[]string{"pad", "a"}, "", "", exitUsage},
+		{[]string{"strip-tags"}, "<b>hi</b>\n<i>there</i>\n", "hi\nthere\n", exitOK},
+		{[]string{"strip-tags", "<b>hi</b>"}, "", "hi\n", exitOK},
+		{[]string{"nl2br"}, "a\nb\n", "a<br />\nb<br />\n", exitOK},
+		{[]string{"nl2br", "a\nb"}, "", "a<br />b\n", exitOK},
+		{[]string{"nl2br"}, "a\r\nb", "a<br />\nb\n", exitOK},
+		{[]string{"nl2br", "--json"}, "a\nb\n", "{\"input\":\"a\\n\",\"output\":\"a\\u003cbr /\\u003e\"}\n{\"input\":\"b\\n\",\"output\":\"b\\u003cbr /\\u003e\"}\n", exitOK},
+		{[]string{"decode-url", "a%20b"}, "", "a b\n", exitOK},
+		{[]string{"decode-url"}, "a%20b\nc%21\n", "a b\nc!\n", exitOK},
+		{[]string{"md5"}, "hello", "5d41402abc4b2a76b9719d911017c592  -\n", exitOK},
+		{[]string{"md5", filepath.Join(dir, "d.txt")}, "", "5d41402abc4b2a76b9719d911017c592  " + filepath.Join(dir, "d.txt") + "\n", exitOK},
+		{[]string{"md5", "--json", filepath.Join(dir, "d.txt")}, "", "{\"file\":\"" + filepath.Join(dir, "d.txt") + "\",\"md5\":\"5d41402abc4b2a76b9719d911017c592\"}\n", exitOK},
+		{[]string{"md5", filepath.Join(dir, "none")}, "", "", exitUsage},
+		{[]string{"validate", "email", "abc@example.com", "abc"}, "", "abc@example.com\n", exitFail},
+		{[]string{"validate", "email", "abc@example.com"}, "", "abc@example.com\n", exitOK},
+		{[]string{"validate", "-q", "ip"}, "127.0.0.1\n::1\nabc\n", "", exitFail},
+		{[]string{"validate", "--json", "url"}, "https://example.com\nabc\n", "{\"input\":\"https://example.com\",\"valid\":true}\n{\"input\":\"abc\",\"valid\":false}\n", exitFail},
+		{[]string{"validate", "phone", "1234"}, "", "", exitUsage},
+		{[]string{"validate"}, "", "", exitUsage},
+		{[]string{"compare", filepath.Join(dir, "a.json"), filepath.Join(dir, "b.json")}, "", "", exitOK},
+		{[]string{"compare", filepath.Join(dir, "a.json"), filepath.Join(dir, "c.json")}, "", "Different Value : (obj1[b] := [1 map[x:<nil>]]) != (obj2[b] := [1 map[x:2]])\n", exitFail},
+		{[]string{"compare", "--json", filepath.Join(dir, "a.json"), filepath.Join(dir, "b.json")}, "", "{\"equal\":true}\n", exitOK},
+		{[]string{"compare", filepath.Join(dir, "f.json"), filepath.Join(dir, "g.json")}, "", "Different Value : obj1(1) != obj2(2)\n", exitFail},
+		{[]string{"compare", "--json", filepath.Join(dir, "f.json"), filepath.Join(dir, "g.json")}, "", "{\"equal\":false,\"difference\":\"Different Value : obj1(1) != obj2(2)\"}\n", exitFail},
+		{[]string{"compare", filepath.Join(dir, "h.json"), filepath.Join(dir, "h.json")}, "", "", exitOK},
+		{[]string{"compare", filepath.Join(dir, "a.json"), filepath.Join(dir, "e.json")}, "", "", exitUsage},
+		{[]string{"compare", filepath.Join(dir, "a.json")}, "", "", exitUsage},
+		{[]string{"numfmt", "--unknown"}, "", "", exitUsage},
+		{[]string{"unknown"}, "", "", exitUsage},
+		{[]string{}, "", "", exitUsage},
+		{[]string{"version"}, "", "strutil dev\n", exitOK},
+	}
+
+	// check : common
+	for _, v := range dataset {
+		var stdout, stderr bytes.Buffer
+
+		rc := run(v.args, strings.NewReader(v.stdin), &stdout, &stderr)
+		assert.AssertEquals(t, rc, v.rc, "Return Value mismatch.\nExpected: %v\nActual: %v (%v, %v)", v.rc, rc, v.args, stderr.String())
+		assert.AssertEquals(t, stdout.String(), v.stdout, "Return Value mismatch.\nExpected: %q\nActual: %q (%v)", v.stdout, stdout.String(), v.args)
+
+		// check : the error message, not the invalid or the different
+		if rc == exitUsage || (rc == exitFail && !strings.Contains(strings.Join(v.args, " "), "--json") && v.args[0] != "validate" && v.args[0] != "compare") {
+			assert.AssertTrue(t, stderr.Len() > 0, "Failure : Couldn't print the error to stderr (%v)", v.args)
+		}
+	}
+}
@@ -0,0 +1,473 @@
+package strutils
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Collection format control for ConvertOptions
+const (
+	ConvertFormatPlain   = iota // a,b,c and k=v,k=v (sorted by key)
+	ConvertFormatBracket        // [a, b, c] and {k: v, k: v} (sorted by key)
+	ConvertFormatJSON           // encoding/json
+)
+
+// ConvertOptions is the options of ConvertToStrWithOptions, ConvertToArByteWithOptions
+type ConvertOptions struct {
+	Nil            string // placeholder of nil (pointer, interface, slice, map)
+	Separator      string // separator of the elements, "," (plain) or ", " (bracket) if empty
+	KeyValue       string // separator of the key and the value of map, "=" (plain) or ": " (bracket) if empty
+	Format         int    // ConvertFormatPlain, ConvertFormatBracket, ConvertFormatJSON
+	FloatVerb      byte   // 'f', 'e', 'g', ... of strconv.FormatFloat, %g (shortest) if 0
+	FloatPrecision int    // precision of the FloatVerb, -1 is the smallest number of digits necessary
+	TimeLayout     string // layout of time.Time, RFC3339Nano (encoding.TextMarshaler) if empty
+}
+
+// convertValue is convert the value to string by the options
+func (s *StringProc) convertValue(rv reflect.Value, opts *ConvertOptions) (string, error) {
+	if !rv.IsValid() {
+		return opts.Nil, nil
+	}
+
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan:
+		if rv.IsNil() {
+			return opts.Nil, nil
+		}
+	}
+
+	if rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		return s.convertValue(rv.Elem(), opts)
+	}
+
+	rt := rv.Type()
+	if rt == timeType && len(opts.TimeLayout) > 0 {
+		return rv.Interface().(time.Time).Format(opts.TimeLayout), nil
+	}
+
+	// the methods of the pointer receiver
+	obj := rv
+	if rv.CanAddr() {
+		obj = rv.Addr()
+	}
+
+	switch v := obj.Interface().(type) {
+	case error:
+		return v.Error(), nil
+
+	case encoding.TextMarshaler:
+		buf, err := v.MarshalText()
+		return string(buf), err
+
+	case fmt.Stringer:
+		return v.String(), nil
+	}
+
+	switch rv.Kind() {
+	case reflect.String:
+		return rv.String(), nil
+
+	case reflect.Bool:
+		return strconv.FormatBool(rv.Bool()), nil
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10), nil
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(rv.Uint(), 10), nil
+
+	case reflect.Float32, reflect.Float64:
+		if opts.FloatVerb == 0 {
+			return fmt.Sprintf("%g", rv.Interface()), nil
+		}
+
+		return strconv.FormatFloat(rv.Float(), opts.FloatVerb, opts.FloatPrecision, rt.Bits()), nil
+
+	case reflect.Complex64, reflect.Complex128:
+		return fmt.Sprintf("%g", rv.Interface()), nil
+
+	case reflect.Slice, reflect.Array:
+		if rt.Elem().Kind() == reflect.Uint8 && rv.Kind() == reflect.Slice { // []byte
+			return string(rv.Bytes()), nil
+		}
+
+		return s.convertCollection(rv, opts)
+
+	case reflect.Map:
+		return s.convertCollection(rv, opts)
+	}
+
+	return "", fmt.Errorf("Not Support obj.(%v)", rt)
+}
+
+// convertCollection is convert the slice, array or map to string by the options
+func (s *StringProc) convertCollection(rv reflect.Value, opts *ConvertOptions) (string, error) {
+	if opts.Format == ConvertFormatJSON {
+		buf, err := json.Marshal(rv.Interface())
+		return string(buf), err
+	}
+
+	sep, kv, begin, end := ",", "=", "", ""
+	if opts.Format == ConvertFormatBracket {
+		sep, kv = ", ", ": "
+		begin, end = "[", "]"
+		if rv.Kind() == reflect.Map {
+			begin, end = "{", "}"
+		}
+	}
+
+	if len(opts.Separator) > 0 {
+		sep = opts.Separator
+	}
+
+	if len(opts.KeyValue) > 0 {
+		kv = opts.KeyValue
+	}
+
+	items := make([]string, 0, rv.Len())
+	if rv.Kind() == reflect.Map {
+		keys := make([]string, 0, rv.Len())
+		vals := make(map[string]string, rv.Len())
+		for _, k := range rv.MapKeys() {
+			key, err := s.convertValue(k, opts)
+			if err != nil {
+				return "", err
+			}
+
+			val, err := s.convertValue(rv.MapIndex(k), opts)
+			if err != nil {
+				return "", err
+			}
+
+			keys = append(keys, key)
+			vals[key] = val
+		}
+
+		sort.Strings(keys)
+		for _, k := range keys {
+			items = append(items, k+kv+vals[k])
+		}
+	} else {
+		for i := 0; i < rv.Len(); i++ {
+			val, err := s.convertValue(rv.Index(i), opts)
+			if err != nil {
+				return "", err
+			}
+
+			items = append(items, val)
+		}
+	}
+
+	return begin + strings.Join(items, sep) + end, nil
+}
+
+// ConvertToStrWithOptions is Convert data type to string by the options
+// the pointers are dereferenced (nil is the placeholder), error, encoding.TextMarshaler and fmt.Stringer are used if implemented
+// the slices, arrays and maps are rendered with the separator and the format
+func (s *StringProc) ConvertToStrWithOptions(obj interface{}, opts *ConvertOptions) (string, error) {
+	if opts == nil {
+		opts = &ConvertOptions{}
+	}
+
+	switch opts.Format {
+	case ConvertFormatPlain, ConvertFormatBracket, ConvertFormatJSON:
+	default:
+		return "", fmt.Errorf("Not allow Format option : %v", opts.Format)
+	}
+
+	return s.convertValue(reflect.ValueOf(obj), opts)
+}
+
+// ConvertToArByteWithOptions returns Convert data type to []byte by the options, same as ConvertToStrWithOptions
+func (s *StringProc) ConvertToArByteWithOptions(obj interface{}, opts *ConvertOptions) ([]byte, error) {
+	retval, err := s.ConvertToStrWithOptions(obj, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(retval), nil
+}
+
+// number with the grouped digits (1,234,567 or 1_234_567), the fraction, the exponent and the size unit (10KB, 1.5 MiB)
+var convertNumberPattern = regexp.MustCompile(`^([+-]?(?:(?:[0-9]{1,3}(?:,[0-9]{3})+|[0-9]+(?:_[0-9]+)*)(?:\.[0-9]*)?|\.[0-9]+)(?:[eE][+-]?[0-9]+)?)\s*([A-Za-z]*)$`)
+
+// exponent of 1024 by the size unit (lower case), same as HumanByteSize
+var convertSizeUnits = map[string]uint{
+	"": 0, "b": 0, "byte": 0, "bytes": 0,
+	"k": 1, "kb": 1, "kib": 1, "kilobyte": 1,
+	"m": 2, "mb": 2, "mib": 2, "megabyte": 2,
+	"g": 3, "gb": 3, "gib": 3, "gigabyte": 3,
+	"t": 4, "tb": 4, "tib": 4, "terabyte": 4,
+	"p": 5, "pb": 5, "pib": 5, "petabyte": 5,
+	"e": 6, "eb": 6, "eib": 6, "exabyte": 6,
+	"z": 7, "zb": 7, "zib": 7, "zettabyte": 7,
+	"y": 8, "yb": 8, "yib": 8, "yottabyte": 8,
+}
+
+var (
+	bigIntType   = reflect.TypeOf(big.Int{})
+	bigFloatType = reflect.TypeOf(big.Float{})
+	bigRatType   = reflect.TypeOf(big.Rat{})
+)
+
+// parseNumber is parse the number string to the exact rational number
+// the base prefixes (0x, 0o, 0b), the grouped digits and the size units are allowed
+func (s *StringProc) parseNumber(str string) (*big.Rat, error) {
+	str = strings.TrimSpace(str)
+
+	digits := strings.TrimLeft(str, "+-")
+	if len(digits) > 1 && digits[0] == 48 && strings.ContainsRune("xXoObB", rune(digits[1])) { // 0x, 0o, 0b
+		i, ok := new(big.Int).SetString(str, 0)
+		if !ok {
+			return nil, fmt.Errorf("Not allow number : %q", str)
+		}
+
+		return new(big.Rat).SetInt(i), nil
+	}
+
+	m := convertNumberPattern.FindStringSubmatch(str)
+	if m == nil {
+		return nil, fmt.Errorf("Not allow number : %q", str)
+	}
+
+	exp, ok := convertSizeUnits[strings.ToLower(m[2])]
+	if !ok {
+		return nil, fmt.Errorf("Not allow size unit : %q", m[2])
+	}
+
+	r, ok := new(big.Rat).SetString(strings.NewReplacer(",", "", "_", "").Replace(m[1]))
+	if !ok {
+		return nil, fmt.Errorf("Not allow number : %q", str)
+	}
+
+	if exp > 0 {
+		r.Mul(r, new(big.Rat).SetInt(new(big.Int).Lsh(big.NewInt(1), 10*exp)))
+	}
+
+	return r, nil
+}
+
+// parseInteger is parse the number string to the integer, the fraction is not allowed
+func (s *StringProc) parseInteger(str string, rt reflect.Type) (*big.Int, error) {
+	r, err := s.parseNumber(str)
+	if err != nil {
+		return nil, err
+	}
+
+	if !r.IsInt() {
+		return nil, fmt.Errorf("Not allow fraction of %v : %q", rt, str)
+	}
+
+	return r.Num(), nil
+}
+
+// parseBool is parse the boolean string, true (1, t, true, y, yes, on) or false (0, f, false, n, no, off), case-insensitive
+func (s *StringProc) parseBool(str string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(str)) {
+	case "1", "t", "true", "y", "yes", "on":
+		return true, nil
+	case "0", "f", "false", "n", "no", "off":
+		return false, nil
+	}
+
+	return false, fmt.Errorf("Not allow bool : %q", str)
+}
+
+// convertFromStr is convert the str and set to the value
+func (s *StringProc) convertFromStr(rv reflect.Value, str string, opts *ConvertOptions) error {
+	rt := rv.Type()
+
+	switch rt {
+	case timeType:
+		layout := opts.TimeLayout
+		if layout == "" {
+			layout = time.RFC3339Nano
+		}
+
+		t, err := time.Parse(layout, strings.TrimSpace(str))
+		if err != nil {
+			return err
+		}
+
+		rv.Set(reflect.ValueOf(t))
+		return nil
+
+	case durationType:
+		d, err := time.ParseDuration(strings.TrimSpace(str))
+		if err != nil {
+			return err
+		}
+
+		rv.SetInt(int64(d))
+		return nil
+
+	case bigIntType:
+		i, err := s.parseInteger(str, rt)
+		if err != nil {
+			return err
+		}
+
+		rv.Addr().Interface().(*big.Int).Set(i)
+		return nil
+
+	case bigFloatType:
+		r, err := s.parseNumber(str)
+		if err != nil {
+			return err
+		}
+
+		rv.Addr().Interface().(*big.Float).SetRat(r)
+		return nil
+
+	case bigRatType:
+		r, err := s.parseNumber(str)
+		if err != nil {
+			return err
+		}
+
+		rv.Addr().Interface().(*big.Rat).Set(r)
+		return nil
+	}
+
+	if u, ok := rv.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return u.UnmarshalText([]byte(str))
+	}
+
+	switch rv.Kind() {
+	case reflect.Ptr:
+		v := reflect.New(rt.Elem())
+		if err := s.convertFromStr(v.Elem(), str, opts); err != nil {
+			return err
+		}
+
+		rv.Set(v)
+
+	case reflect.String:
+		rv.SetString(str)
+
+	case reflect.Bool:
+		b, err := s.parseBool(str)
+		if err != nil {
+			return err
+		}
+
+		rv.SetBool(b)
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := s.parseInteger(str, rt)
+		if err != nil {
+			return err
+		}
+
+		if !i.IsInt64() || rv.OverflowInt(i.Int64()) {
+			return fmt.Errorf("Out of range of %v : %q", rt, str)
+		}
+
+		rv.SetInt(i.Int64())
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		i, err := s.parseInteger(str, rt)
+		if err != nil {
+			return err
+		}
+
+		if !i.IsUint64() || rv.OverflowUint(i.Uint64()) {
+			return fmt.Errorf("Out of range of %v : %q", rt, str)
+		}
+
+		rv.SetUint(i.Uint64())
+
+	case reflect.Float32, reflect.Float64:
+		// inf, nan and the hexadecimal floats
+		f, err := strconv.ParseFloat(strings.TrimSpace(str), rt.Bits())
+		if err != nil {
+			var r *big.Rat
+			if r, err = s.parseNumber(str); err != nil {
+				return err
+			}
+
+			f, _ = r.Float64()
+			if rt.Bits() == 32 {
+				f32, _ := r.Float32()
+				f = float64(f32)
+			}
+		}
+
+		if math.IsInf(f, 0) && !strings.Contains(strings.ToLower(str), "inf") {
+			return fmt.Errorf("Out of range of %v : %q", rt, str)
+		}
+
+		rv.SetFloat(f)
+
+	case reflect.Slice:
+		if rt.Elem().Kind() == reflect.Uint8 { // []byte
+			rv.SetBytes([]byte(str))
+			return nil
+		}
+
+		if len(strings.TrimSpace(str)) == 0 {
+			rv.Set(reflect.MakeSlice(rt, 0, 0))
+			return nil
+		}
+
+		sep := opts.Separator
+		if sep == "" {
+			sep = ","
+		}
+
+		items := strings.Split(str, sep)
+		retval := reflect.MakeSlice(rt, len(items), len(items))
+		for i, v := range items {
+			if err := s.convertFromStr(retval.Index(i), strings.TrimSpace(v), opts); err != nil {
+				return fmt.Errorf("%v at index %v", err, i)
+			}
+		}
+
+		rv.Set(retval)
+
+	default:
+		return fmt.Errorf("Not Support obj.(%v)", rt)
+	}
+
+	return nil
+}
+
+// ConvertFromStr is Convert string to the data type of the dst (pointer), the inverse of ConvertToStr
+// same as ConvertFromStrWithOptions with the default options
+func (s *StringProc) ConvertFromStr(str string, dst interface{}) error {
+	return s.ConvertFromStrWithOptions(str, dst, nil)
+}
+
+// ConvertFromStrWithOptions is Convert string to the data type of the dst (pointer) by the options
+// supports string, bool (true, false, 1, 0, yes, no, on, off, ...), int, uint, float, time.Time (TimeLayout, RFC3339Nano if empty), time.Duration,
+// big.Int, big.Float, big.Rat, encoding.TextUnmarshaler, the pointers of them and the slices of them (split by the Separator, "," if empty)
+// the numbers are allowed the grouped digits (1,234 or 1_234), the size units of 1024 (10KB, 1.5MiB) and the base prefixes of integer (0x, 0o, 0b)
+// the out of range (overflow) and the fraction of integer are returned as an error, the dst is not changed if an error
+func (s *StringProc) ConvertFromStrWithOptions(str string, dst interface{}, opts *ConvertOptions) error {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("Not allow dst parameter, not a pointer : %T", dst)
+	}
+
+	if opts == nil {
+		opts = &ConvertOptions{}
+	}
+
+	// convert to a copy, the dst is not changed if an error
+	v := reflect.New(rv.Elem().Type())
+	if err := s.convertFromStr(v.Elem(), str, opts); err != nil {
+		return err
+	}
+
+	rv.Elem().Set(v.Elem())
+	return nil
+}
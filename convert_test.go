@@ -0,0 +1,254 @@
+package strutils_test
+
+import (
+	"errors"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	strutils "github.com/torden/go-strutil"
+)
+
+type convertStringer int
+
+func (c convertStringer) String() string {
+	return "#" + string(rune('0'+int(c)))
+}
+
+func Test_strutils_ConvertToStrWithOptions(t *testing.T) {
+	t.Parallel()
+
+	num := 42
+	pnum := &num
+	var nilnum *int
+	var nilerr error
+	tm := time.Date(2020, 2, 25, 13, 55, 36, 0, time.UTC)
+
+	dataset := []struct {
+		obj  interface{}
+		opts *strutils.ConvertOptions
+		str  string
+	}{
+		{"abc", nil, "abc"},
+		{&num, nil, "42"},
+		{&pnum, nil, "42"},
+		{nilnum, nil, ""},
+		{nilnum, &strutils.ConvertOptions{Nil: "<nil>"}, "<nil>"},
+		{nil, &strutils.ConvertOptions{Nil: "NULL"}, "NULL"},
+		{nilerr, &strutils.ConvertOptions{Nil: "-"}, "-"},
+		{tm, nil, "2020-02-25T13:55:36Z"},
+		{&tm, &strutils.ConvertOptions{TimeLayout: "2006-01-02"}, "2020-02-25"},
+		{1500 * time.Millisecond, nil, "1.5s"},
+		{errors.New("failed"), nil, "failed"},
+		{net.IPv4(127, 0, 0, 1), nil, "127.0.0.1"},
+		{convertStringer(7), nil, "#7"},
+		{[]byte("bytes"), nil, "bytes"},
+		{3.14159, &strutils.ConvertOptions{FloatVerb: 'f', FloatPrecision: 2}, "3.14"},
+		{float32(0.1), &strutils.ConvertOptions{FloatVerb: 'e', FloatPrecision: -1}, "1e-01"},
+		{1e21, nil, "1e+21"},
+		{complex(1, 2), nil, "(1+2i)"},
+
+		// collections
+		{[]string{"a", "b", "c"}, nil, "a,b,c"},
+		{[]string{"a", "b", "c"}, &strutils.ConvertOptions{Separator: " | "}, "a | b | c"},
+		{[3]int{1, 2, 3}, &strutils.ConvertOptions{Format: strutils.ConvertFormatBracket}, "[1, 2, 3]"},
+		{[]interface{}{1, "x", nil, &num, []int{1, 2}}, &strutils.ConvertOptions{Format: strutils.ConvertFormatBracket, Nil: "nil"}, "[1, x, nil, 42, [1, 2]]"},
+		{[]float64{1.5, 2}, &strutils.ConvertOptions{FloatVerb: 'f', FloatPrecision: 1}, "1.5,2.0"},
+		{map[string]int{"b": 2, "a": 1, "c": 3}, nil, "a=1,b=2,c=3"},
+		{map[string]int{"b": 2, "a": 1}, &strutils.ConvertOptions{Format: strutils.ConvertFormatBracket}, "{a: 1, b: 2}"},
+		{map[string]int{"b": 2, "a": 1}, &strutils.ConvertOptions{Separator: "&", KeyValue: ":"}, "a:1&b:2"},
+		{map[int][]string{2: {"x"}, 1: {"y", "z"}}, &strutils.ConvertOptions{Format: strutils.ConvertFormatJSON}, `{"1":["y","z"],"2":["x"]}`},
+		{[]time.Duration{time.Second, time.Minute}, nil, "1s,1m0s"},
+		{[]string{}, &strutils.ConvertOptions{Format: strutils.ConvertFormatBracket}, "[]"},
+		{[]string(nil), &strutils.ConvertOptions{Nil: "null"}, "null"},
+	}
+
+	// check : common
+	for _, v := range dataset {
+		retval, err := strproc.ConvertToStrWithOptions(v.obj, v.opts)
+		assert.AssertNil(t, err, "Error (%#v) : %v", v.obj, err)
+		assert.AssertEquals(t, retval, v.str, "Return Value mismatch (%#v).\nExpected: %v\nActual: %v", v.obj, v.str, retval)
+
+		bytes, err := strproc.ConvertToArByteWithOptions(v.obj, v.opts)
+		assert.AssertNil(t, err, "Error (%#v) : %v", v.obj, err)
+		assert.AssertEquals(t, string(bytes), v.str, "Return Value mismatch (%#v).\nExpected: %v\nActual: %v", v.obj, v.str, string(bytes))
+	}
+
+	// check : ConvertToStr, ConvertToArByte with the default options
+	retval, err := strproc.ConvertToStr([]*int{&num, nil})
+	assert.AssertNil(t, err, "Error : %v", err)
+	assert.AssertEquals(t, retval, "42,", "Return Value mismatch.\nExpected: %v\nActual: %v", "42,", retval)
+
+	bytes, err := strproc.ConvertToArByte(tm)
+	assert.AssertNil(t, err, "Error : %v", err)
+	assert.AssertEquals(t, string(bytes), "2020-02-25T13:55:36Z", "Return Value mismatch.\nExpected: %v\nActual: %v", "2020-02-25T13:55:36Z", string(bytes))
+
+	// check : not support, not allow format
+	notSupport := []interface{}{
+		struct{ A int }{1},
+		[]struct{ A int }{{1}},
+		map[string]chan int{"a": make(chan int)},
+		func() {},
+	}
+
+	for _, v := range notSupport {
+		_, err := strproc.ConvertToStrWithOptions(v, nil)
+		assert.AssertNotNil(t, err, "Couldn't check the `not support` (%T)\nError : %v", v, err)
+
+		_, err = strproc.ConvertToArByte(v)
+		assert.AssertNotNil(t, err, "Couldn't check the `not support` (%T)\nError : %v", v, err)
+	}
+
+	_, err = strproc.ConvertToStrWithOptions("abc", &strutils.ConvertOptions{Format: 9})
+	assert.AssertNotNil(t, err, "Couldn't check the `not allow format`\nError : %v", err)
+}
+
+func Test_strutils_ConvertFromStr(t *testing.T) {
+	t.Parallel()
+
+	var (
+		s    string
+		b    bool
+		i    int
+		i8   int8
+		i64  int64
+		u16  uint16
+		u64  uint64
+		f32  float32
+		f64  float64
+		d    time.Duration
+		tm   time.Time
+		ip   net.IP
+		pi   *int
+		ppi  **int
+		bi   big.Int
+		bf   big.Float
+		br   big.Rat
+		bis  []big.Int
+		ints []int
+		strs []string
+		durs []time.Duration
+		byts []byte
+	)
+
+	// check : common
+	dataset := []struct {
+		str string
+		dst interface{}
+		get func() interface{}
+		val interface{}
+	}{
+		{"abc ", &s, func() interface{} { return s }, "abc "},
+		{"yes", &b, func() interface{} { return b }, true},
+		{"Off", &b, func() interface{} { return b }, false},
+		{"1", &b, func() interface{} { return b }, true},
+		{" -123 ", &i, func() interface{} { return i }, -123},
+		{"1,234,567", &i, func() interface{} { return i }, 1234567},
+		{"1_000", &i, func() interface{} { return i }, 1000},
+		{"0x1F", &i, func() interface{} { return i }, 31},
+		{"0b101", &i, func() interface{} { return i }, 5},
+		{"1e3", &i, func() interface{} { return i }, 1000},
+		{"-128", &i8, func() interface{} { return i8 }, int8(-128)},
+		{"1.5KB", &i64, func() interface{} { return i64 }, int64(1536)},
+		{"10 MiB", &i64, func() interface{} { return i64 }, int64(10485760)},
+		{"2g", &i64, func() interface{} { return i64 }, int64(2147483648)},
+		{"65,535", &u16, func() interface{} { return u16 }, uint16(65535)},
+		{"18446744073709551615", &u64, func() interface{} { return u64 }, uint64(18446744073709551615)},
+		{"1EB", &u64, func() interface{} { return u64 }, uint64(1152921504606846976)},
+		{"0.5", &f32, func() interface{} { return f32 }, float32(0.5)},
+		{"1,234.5", &f64, func() interface{} { return f64 }, 1234.5},
+		{"2.5k", &f64, func() interface{} { return f64 }, 2560.0},
+		{"-1.5e-3", &f64, func() interface{} { return f64 }, -0.0015},
+		{"1m30s", &d, func() interface{} { return d }, 90 * time.Second},
+		{"2020-02-25T13:55:36Z", &tm, func() interface{} { return tm.Unix() }, int64(1582638936)},
+		{"127.0.0.1", &ip, func() interface{} { return ip.String() }, "127.0.0.1"},
+		{"42", &pi, func() interface{} { return *pi }, 42},
+		{"43", &ppi, func() interface{} { return **ppi }, 43},
+		{"123,456,789,012,345,678,901,234,567,890", &bi, func() interface{} { return bi.String() }, "123456789012345678901234567890"},
+		{"1.25", &bf, func() interface{} { return bf.String() }, "1.25"},
+		{"-7", &bi, func() interface{} { return bi.String() }, "-7"},
+		{"0.75", &br, func() interface{} { return br.String() }, "3/4"},
+		{"-1.5", &br, func() interface{} { return br.String() }, "-3/2"},
+		{"1,99999999999999999999", &bis, func() interface{} {
+			return len(bis) == 2 && bis[0].String() == "1" && bis[1].String() == "99999999999999999999"
+		}, true},
+		{"1, 2,3", &ints, func() interface{} { return len(ints) == 3 && ints[0] == 1 && ints[1] == 2 && ints[2] == 3 }, true},
+		{"a,b", &strs, func() interface{} { return len(strs) == 2 && strs[0] == "a" && strs[1] == "b" }, true},
+		{"", &strs, func() interface{} { return strs != nil && len(strs) == 0 }, true},
+		{"1s,2m", &durs, func() interface{} { return len(durs) == 2 && durs[1] == 2*time.Minute }, true},
+		{"bytes", &byts, func() interface{} { return string(byts) }, "bytes"},
+	}
+
+	for _, v := range dataset {
+		err := strproc.ConvertFromStr(v.str, v.dst)
+		assert.AssertNil(t, err, "Error (%q, %T) : %v", v.str, v.dst, err)
+
+		retval := v.get()
+		assert.AssertEquals(t, retval, v.val, "Return Value mismatch (%q, %T).\nExpected: %v\nActual: %v", v.str, v.dst, v.val, retval)
+	}
+
+	// check : options
+	err := strproc.ConvertFromStrWithOptions("1,234|5", &ints, &strutils.ConvertOptions{Separator: "|"})
+	assert.AssertNil(t, err, "Error : %v", err)
+	assert.AssertTrue(t, len(ints) == 2 && ints[0] == 1234 && ints[1] == 5, "Return Value mismatch.\nExpected: %v\nActual: %v", []int{1234, 5}, ints)
+
+	err = strproc.ConvertFromStrWithOptions("2020-02-25", &tm, &strutils.ConvertOptions{TimeLayout: "2006-01-02"})
+	assert.AssertNil(t, err, "Error : %v", err)
+	assert.AssertEquals(t, tm.Format("2006-01-02"), "2020-02-25", "Return Value mismatch.\nExpected: %v\nActual: %v", "2020-02-25", tm.Format("2006-01-02"))
+
+	// check : round trip of ConvertToStr
+	for _, v := range []interface{}{&i64, &u64, &f64, &d, &b, &strs} {
+		str, err := strproc.ConvertToStr(v)
+		assert.AssertNil(t, err, "Error (%T) : %v", v, err)
+
+		err = strproc.ConvertFromStr(str, v)
+		assert.AssertNil(t, err, "Error (%q, %T) : %v", str, v, err)
+
+		retval, err := strproc.ConvertToStr(v)
+		assert.AssertNil(t, err, "Error (%T) : %v", v, err)
+		assert.AssertEquals(t, retval, str, "Return Value mismatch (%T).\nExpected: %v\nActual: %v", v, str, retval)
+	}
+
+	// check : not allow, overflow
+	i = 7
+	errDataset := []struct {
+		str string
+		dst interface{}
+	}{
+		{"maybe", &b},
+		{"12a", &i},
+		{"1,23", &i},
+		{"1.5", &i},
+		{"10XB", &i},
+		{"128", &i8},
+		{"-1", &u16},
+		{"65536", &u16},
+		{"18446744073709551616", &u64},
+		{"16EB", &u64},
+		{"1e39", &f32},
+		{"1e400", &f64},
+		{"1,x", &ints},
+		{"1.5", &bi},
+		{"10", &d},
+		{"2020-02-30", &tm},
+		{"1.2.3.4.5", &ip},
+		{"1", i},
+		{"1", nil},
+		{"1", &struct{ A int }{}},
+		{"1", &map[string]int{}},
+	}
+
+	for _, v := range errDataset {
+		err := strproc.ConvertFromStr(v.str, v.dst)
+		assert.AssertNotNil(t, err, "Couldn't check the `not allow` (%q, %T)\nError : %v", v.str, v.dst, err)
+	}
+
+	assert.AssertEquals(t, i, 7, "Changed Value by the error.\nExpected: %v\nActual: %v", 7, i)
+
+	err = strproc.ConvertFromStr("65536", &u16)
+	assert.AssertEquals(t, err.Error(), `Out of range of uint16 : "65536"`, "Error mismatch.\nExpected: %v\nActual: %v", `Out of range of uint16 : "65536"`, err)
+
+	err = strproc.ConvertFromStr("1,x", &ints)
+	assert.AssertEquals(t, err.Error(), `Not allow number : "x" at index 1`, "Error mismatch.\nExpected: %v\nActual: %v", `Not allow number : "x" at index 1`, err)
+}
@@ -0,0 +1,699 @@
+package strutils
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// dialects of EscapeString and UnescapeString
+const (
+	EscapeMySQL      = iota + 1 // 'It\'s' , MySQL string literal (backslash escapes)
+	EscapePostgreSQL            // 'It''s' , PostgreSQL standard conforming string
+	EscapeSQLite                // 'It''s' , SQLite string literal
+	EscapeShell                 // 'It'\''s' , POSIX shell single-quoting
+	EscapeC                     // "It's\n" , C string literal
+	EscapeGo                    // "It's\n" , Go interpreted string literal
+	EscapeJSON                  // "It's\n" , JSON string
+	EscapeJavaScript            // 'It\x27s\n' , JavaScript string literal (safe in <script> and html attribute)
+	EscapeCSV                   // "a,""b""" , CSV field (RFC 4180), quoted only if needed
+	EscapeLDAPDN                // a\,b , attribute value of LDAP distinguished name (RFC 4514)
+	EscapeLDAPFilter            // a\2ab , assertion value of LDAP search filter (RFC 4515)
+)
+
+const hexDigits = "0123456789ABCDEF"
+
+func appendHexByte(buf []byte, prefix string, c byte) []byte {
+	buf = append(buf, prefix...)
+	return append(buf, hexDigits[c>>4], hexDigits[c&15])
+}
+
+// unquote returns the string between the quotes
+func unquote(str string, quotes string) (string, byte, error) {
+	if len(str) < 2 || strings.IndexByte(quotes, str[0]) < 0 || str[len(str)-1] != str[0] {
+		return "", 0, fmt.Errorf("Not allow unquoted string : %v", str)
+	}
+
+	return str[1 : len(str)-1], str[0], nil
+}
+
+func (s *StringProc) escapeMySQL(str string) string {
+	buf := make([]byte, 0, len(str)+16) // prealloca
+	buf = append(buf, 39)               // '
+
+	for i := 0; i < len(str); i++ {
+		switch c := str[i]; c {
+		case 0: // NUL
+			buf = append(buf, `\0`...)
+		case 10: // LF
+			buf = append(buf, `\n`...)
+		case 13: // CR
+			buf = append(buf, `\r`...)
+		case 26: // Ctrl-Z
+			buf = append(buf, `\Z`...)
+		case 34, 39, 92: // " ' backslash
+			buf = append(buf, 92, c)
+		default:
+			buf = append(buf, c)
+		}
+	}
+
+	return string(append(buf, 39))
+}
+
+func (s *StringProc) unescapeMySQL(str string) (string, error) {
+	body, quote, err := unquote(str, `'"`)
+	if err != nil {
+		return "", err
+	}
+
+	buf := make([]byte, 0, len(body)) // prealloca
+
+	for i := 0; i < len(body); i++ {
+		c := body[i]
+
+		switch {
+		case c == quote:
+			if i+1 >= len(body) || body[i+1] != quote {
+				return "", fmt.Errorf("Not allow unescaped quote at %d", i+1)
+			}
+			i++
+
+		case c == 92: // backslash
+			if i+1 >= len(body) {
+				return "", fmt.Errorf("Not allow incomplete escape sequence at %d", i+1)
+			}
+			i++
+
+			switch c = body[i]; c {
+			case 48: // 0
+				c = 0
+			case 98: // b
+				c = 8
+			case 110: // n
+				c = 10
+			case 114: // r
+				c = 13
+			case 116: // t
+				c = 9
+			case 90: // Z
+				c = 26
+			case 37, 95: // \% \_ are kept for LIKE
+				buf = append(buf, 92)
+			}
+		}
+
+		buf = append(buf, c)
+	}
+
+	return string(buf), nil
+}
+
+// escapeSQL quotes the standard SQL string literal, PostgreSQL and SQLite
+func (s *StringProc) escapeSQL(str string) (string, error) {
+	if n := strings.IndexByte(str, 0); n >= 0 {
+		return "", fmt.Errorf("Not allow NUL character at %d", n)
+	}
+
+	return "'" + strings.Replace(str, "'", "''", -1) + "'", nil
+}
+
+func (s *StringProc) unescapeSQL(str string) (string, error) {
+	body, _, err := unquote(str, "'")
+	if err != nil {
+		return "", err
+	}
+
+	buf := make([]byte, 0, len(body)) // prealloca
+
+	for i := 0; i < len(body); i++ {
+		if body[i] == 39 { // '
+			if i+1 >= len(body) || body[i+1] != 39 {
+				return "", fmt.Errorf("Not allow unescaped quote at %d", i+1)
+			}
+			i++
+		}
+
+		buf = append(buf, body[i])
+	}
+
+	return string(buf), nil
+}
+
+func (s *StringProc) escapeShell(str string) (string, error) {
+	if n := strings.IndexByte(str, 0); n >= 0 {
+		return "", fmt.Errorf("Not allow NUL character at %d", n)
+	}
+
+	return "'" + strings.Replace(str, "'", `'\''`, -1) + "'", nil
+}
+
+// unescapeShell unquotes a shell word of single-quoted strings, backslash escapes and safe characters
+func (s *StringProc) unescapeShell(str string) (string, error) {
+	buf := make([]byte, 0, len(str)) // prealloca
+
+	for i := 0; i < len(str); i++ {
+		c := str[i]
+
+		switch {
+		case c == 39: // '
+			end := strings.IndexByte(str[i+1:], 39)
+			if end < 0 {
+				return "", fmt.Errorf("Not allow unterminated quote at %d", i)
+			}
+			buf = append(buf, str[i+1:i+1+end]...)
+			i += end + 1
+
+		case c == 92: // backslash
+			if i+1 >= len(str) {
+				return "", fmt.Errorf("Not allow incomplete escape sequence at %d", i)
+			}
+			i++
+			buf = append(buf, str[i])
+
+		case isEntityAlnum(c) || c >= 128 || strings.IndexByte("_@%+=:,./-", c) >= 0:
+			buf = append(buf, c)
+
+		default:
+			return "", fmt.Errorf("Not allow unquoted character %q at %d", c, i)
+		}
+	}
+
+	return string(buf), nil
+}
+
+func (s *StringProc) escapeC(str string) string {
+	buf := make([]byte, 0, len(str)+16) // prealloca
+	buf = append(buf, 34)               // "
+
+	for i := 0; i < len(str); i++ {
+		switch c := str[i]; c {
+		case 7:
+			buf = append(buf, `\a`...)
+		case 8:
+			buf = append(buf, `\b`...)
+		case 9:
+			buf = append(buf, `\t`...)
+		case 10:
+			buf = append(buf, `\n`...)
+		case 11:
+			buf = append(buf, `\v`...)
+		case 12:
+			buf = append(buf, `\f`...)
+		case 13:
+			buf = append(buf, `\r`...)
+		case 34, 92: // " backslash
+			buf = append(buf, 92, c)
+		case 63: // ? , not to be a trigraph (??=)
+			if i+1 < len(str) && str[i+1] == 63 {
+				buf = append(buf, 92)
+			}
+			buf = append(buf, c)
+		default:
+			if c < 32 || c == 127 { // 3 digits octal, not to be continued by the next digit
+				buf = append(buf, 92, 48+c>>6, 48+(c>>3)&7, 48+c&7)
+				continue
+			}
+			buf = append(buf, c)
+		}
+	}
+
+	return string(append(buf, 34))
+}
+
+// unescapeC decodes the escape sequences of C (\n, \t, \xHH, \ooo, \uHHHH, \UHHHHHHHH, ...)
+// quote is the not allowed character without escaping (0 is none), offset is added to the position of errors
+// if strict is false, unknown or invalid escapes are the character after the backslash, and a trailing backslash is removed
+func (s *StringProc) unescapeC(str string, quote byte, offset int, strict bool) (string, error) {
+	buf := make([]byte, 0, len(str)) // prealloca
+
+	for i := 0; i < len(str); i++ {
+		c := str[i]
+
+		if quote != 0 && c == quote {
+			return "", fmt.Errorf("Not allow unescaped quote at %d", offset+i)
+		}
+
+		if c != 92 { // backslash
+			buf = append(buf, c)
+			continue
+		}
+
+		if i+1 >= len(str) {
+			if !strict {
+				break
+			}
+			return "", fmt.Errorf("Not allow incomplete escape sequence at %d", offset+i)
+		}
+
+		pos := i
+		i++
+
+		var err error
+
+		switch e := str[i]; {
+		case e == 97: // a
+			buf = append(buf, 7)
+		case e == 98: // b
+			buf = append(buf, 8)
+		case e == 102: // f
+			buf = append(buf, 12)
+		case e == 110: // n
+			buf = append(buf, 10)
+		case e == 114: // r
+			buf = append(buf, 13)
+		case e == 116: // t
+			buf = append(buf, 9)
+		case e == 118: // v
+			buf = append(buf, 11)
+		case e == 92 || e == 39 || e == 34 || e == 63: // backslash ' " ?
+			buf = append(buf, e)
+
+		case e >= 48 && e <= 55: // \ooo , 1~3 octal digits
+			v := 0
+			n := 0
+			for ; n < 3 && i+n < len(str) && str[i+n] >= 48 && str[i+n] <= 55; n++ {
+				v = v<<3 | int(str[i+n]-48)
+			}
+			if v > 255 {
+				err = fmt.Errorf("Not allow octal escape sequence out of range at %d", offset+pos)
+				break
+			}
+			buf = append(buf, byte(v))
+			i += n - 1
+
+		case e == 120: // \xHH..
+			v, n := s.parseHexRun(str, i+1, 8)
+			if n == 0 || v > 255 || (i+1+n < len(str) && s.isHex(str[i+1+n])) {
+				err = fmt.Errorf("Not allow hex escape sequence at %d", offset+pos)
+				break
+			}
+			buf = append(buf, byte(v))
+			i += n
+
+		case e == 117 || e == 85: // \uHHHH , \UHHHHHHHH
+			size := 4
+			if e == 85 {
+				size = 8
+			}
+
+			v, n := s.parseHexRun(str, i+1, size)
+			if n != size || v > utf8.MaxRune || (v >= 0xD800 && v <= 0xDFFF) {
+				err = fmt.Errorf("Not allow universal character name at %d", offset+pos)
+				break
+			}
+			buf = append(buf, string(v)...)
+			i += n
+
+		default:
+			err = fmt.Errorf("Not allow escape sequence %q at %d", str[pos:i+1], offset+pos)
+		}
+
+		if err != nil {
+			if strict {
+				return "", err
+			}
+			buf = append(buf, str[i])
+		}
+	}
+
+	return string(buf), nil
+}
+
+func (s *StringProc) escapeJSON(str string) string {
+	buf := make([]byte, 0, len(str)+16) // prealloca
+	buf = append(buf, 34)               // "
+
+	for i := 0; i < len(str); {
+		r, size := utf8.DecodeRuneInString(str[i:])
+
+		switch {
+		case r == utf8.RuneError && size == 1: // invalid utf-8
+			buf = append(buf, `\ufffd`...)
+		case r == 34 || r == 92: // " backslash
+			buf = append(buf, 92, byte(r))
+		case r == 8:
+			buf = append(buf, `\b`...)
+		case r == 9:
+			buf = append(buf, `\t`...)
+		case r == 10:
+			buf = append(buf, `\n`...)
+		case r == 12:
+			buf = append(buf, `\f`...)
+		case r == 13:
+			buf = append(buf, `\r`...)
+		case r < 32:
+			buf = appendHexByte(buf, `\u00`, byte(r))
+		default:
+			buf = append(buf, str[i:i+size]...)
+		}
+		i += size
+	}
+
+	return string(append(buf, 34))
+}
+
+func (s *StringProc) unescapeJSON(str string) (string, error) {
+	if _, _, err := unquote(str, `"`); err != nil {
+		return "", err
+	}
+
+	var retval string
+	if err := json.Unmarshal([]byte(str), &retval); err != nil {
+		return "", err
+	}
+
+	return retval, nil
+}
+
+func (s *StringProc) escapeJavaScript(str string) string {
+	buf := make([]byte, 0, len(str)+16) // prealloca
+	buf = append(buf, 39)               // '
+
+	for i := 0; i < len(str); {
+		r, size := utf8.DecodeRuneInString(str[i:])
+
+		switch {
+		case r == utf8.RuneError && size == 1: // invalid utf-8
+			buf = append(buf, `\ufffd`...)
+		case r == 92: // backslash
+			buf = append(buf, `\\`...)
+		case r == 8:
+			buf = append(buf, `\b`...)
+		case r == 9:
+			buf = append(buf, `\t`...)
+		case r == 10:
+			buf = append(buf, `\n`...)
+		case r == 11:
+			buf = append(buf, `\v`...)
+		case r == 12:
+			buf = append(buf, `\f`...)
+		case r == 13:
+			buf = append(buf, `\r`...)
+		case r < 32 || r == 127 || r == 34 || r == 38 || r == 39 || r == 60 || r == 62: // " & ' < > , safe in <script> and html attribute
+			buf = appendHexByte(buf, `\x`, byte(r))
+		case r == 0x2028 || r == 0x2029: // line terminators of javascript
+			buf = append(buf, `\u`+strconv.FormatInt(int64(r), 16)...)
+		default:
+			buf = append(buf, str[i:i+size]...)
+		}
+		i += size
+	}
+
+	return string(append(buf, 39))
+}
+
+func (s *StringProc) unescapeJavaScript(str string) (string, error) {
+	body, quote, err := unquote(str, `'"`)
+	if err != nil {
+		return "", err
+	}
+
+	buf := make([]byte, 0, len(body)) // prealloca
+
+	for i := 0; i < len(body); i++ {
+		c := body[i]
+
+		if c == quote || c == 10 || c == 13 {
+			return "", fmt.Errorf("Not allow unescaped character %q at %d", c, i+1)
+		}
+
+		if c != 92 { // backslash
+			buf = append(buf, c)
+			continue
+		}
+
+		if i+1 >= len(body) {
+			return "", fmt.Errorf("Not allow incomplete escape sequence at %d", i+1)
+		}
+
+		pos := i
+		i++
+
+		switch e := body[i]; {
+		case e == 98: // b
+			buf = append(buf, 8)
+		case e == 102: // f
+			buf = append(buf, 12)
+		case e == 110: // n
+			buf = append(buf, 10)
+		case e == 114: // r
+			buf = append(buf, 13)
+		case e == 116: // t
+			buf = append(buf, 9)
+		case e == 118: // v
+			buf = append(buf, 11)
+
+		case e == 48 && (i+1 >= len(body) || body[i+1] < 48 || body[i+1] > 57): // \0 , not followed by a digit
+			buf = append(buf, 0)
+
+		case e >= 48 && e <= 57: // legacy octal
+			return "", fmt.Errorf("Not allow octal escape sequence at %d", pos+1)
+
+		case e == 120: // \xHH
+			v, n := s.parseHexRun(body, i+1, 2)
+			if n != 2 {
+				return "", fmt.Errorf("Not allow hex escape sequence at %d", pos+1)
+			}
+			buf = append(buf, string(v)...)
+			i += n
+
+		case e == 117: // \uHHHH , \u{H..}
+			var v rune
+			var n int
+			if i+1 < len(body) && body[i+1] == 123 { // {
+				v, n = s.parseHexRun(body, i+2, 6)
+				if n == 0 || i+2+n >= len(body) || body[i+2+n] != 125 || v > utf8.MaxRune { // }
+					return "", fmt.Errorf("Not allow unicode escape sequence at %d", pos+1)
+				}
+				n += 2
+			} else {
+				v, n = s.parseHexRun(body, i+1, 4)
+				if n != 4 {
+					return "", fmt.Errorf("Not allow unicode escape sequence at %d", pos+1)
+				}
+
+				// surrogate pair
+				if v >= 0xD800 && v <= 0xDBFF && i+10 < len(body) && body[i+5] == 92 && body[i+6] == 117 {
+					if lo, m := s.parseHexRun(body, i+7, 4); m == 4 && lo >= 0xDC00 && lo <= 0xDFFF {
+						v = 0x10000 + (v-0xD800)<<10 + (lo - 0xDC00)
+						n += 6
+					}
+				}
+			}
+			buf = appendCodePoint(buf, v)
+			i += n
+
+		case e == 10: // line continuation
+		case e == 13:
+			if i+1 < len(body) && body[i+1] == 10 {
+				i++
+			}
+
+		default: // \' \" \\ and others are the character itself
+			r, size := utf8.DecodeRuneInString(body[i:])
+			if r != 0x2028 && r != 0x2029 { // line continuation
+				buf = append(buf, body[i:i+size]...)
+			}
+			i += size - 1
+		}
+	}
+
+	return string(buf), nil
+}
+
+func (s *StringProc) escapeCSV(str string) string {
+	if !strings.ContainsAny(str, ",\"\r\n") && strings.Trim(str, " \t") == str {
+		return str
+	}
+
+	return `"` + strings.Replace(str, `"`, `""`, -1) + `"`
+}
+
+func (s *StringProc) unescapeCSV(str string) (string, error) {
+	if len(str) == 0 || str[0] != 34 { // "
+		if n := strings.IndexAny(str, ",\"\r\n"); n >= 0 {
+			return "", fmt.Errorf("Not allow unquoted character %q at %d", str[n], n)
+		}
+		return str, nil
+	}
+
+	body, _, err := unquote(str, `"`)
+	if err != nil {
+		return "", err
+	}
+
+	buf := make([]byte, 0, len(body)) // prealloca
+
+	for i := 0; i < len(body); i++ {
+		if body[i] == 34 { // "
+			if i+1 >= len(body) || body[i+1] != 34 {
+				return "", fmt.Errorf("Not allow unescaped quote at %d", i+1)
+			}
+			i++
+		}
+
+		buf = append(buf, body[i])
+	}
+
+	return string(buf), nil
+}
+
+func (s *StringProc) escapeLDAPDN(str string) string {
+	buf := make([]byte, 0, len(str)+16) // prealloca
+
+	for i := 0; i < len(str); i++ {
+		switch c := str[i]; {
+		case c == 0:
+			buf = append(buf, `\00`...)
+		case strings.IndexByte(",+\"\\<>;=", c) >= 0:
+			buf = append(buf, 92, c)
+		case i == 0 && (c == 32 || c == 35): // leading space or #
+			buf = append(buf, 92, c)
+		case i == len(str)-1 && c == 32: // trailing space
+			buf = append(buf, 92, c)
+		default:
+			buf = append(buf, c)
+		}
+	}
+
+	return string(buf)
+}
+
+func (s *StringProc) unescapeLDAPDN(str string) (string, error) {
+	buf := make([]byte, 0, len(str)) // prealloca
+
+	for i := 0; i < len(str); i++ {
+		c := str[i]
+
+		switch {
+		case c == 92: // backslash
+			if i+1 < len(str) && strings.IndexByte(",+\"\\<>;= #", str[i+1]) >= 0 {
+				buf = append(buf, str[i+1])
+				i++
+				continue
+			}
+
+			v, n := s.parseHexRun(str, i+1, 2)
+			if n != 2 {
+				return "", fmt.Errorf("Not allow escape sequence at %d", i)
+			}
+			buf = append(buf, byte(v))
+			i += n
+
+		case c == 0 || strings.IndexByte(",+\"<>;", c) >= 0:
+			return "", fmt.Errorf("Not allow unescaped character %q at %d", c, i)
+
+		default:
+			buf = append(buf, c)
+		}
+	}
+
+	return string(buf), nil
+}
+
+func (s *StringProc) escapeLDAPFilter(str string) string {
+	buf := make([]byte, 0, len(str)+16) // prealloca
+
+	for i := 0; i < len(str); i++ {
+		switch c := str[i]; c {
+		case 0, 40, 41, 42, 92: // NUL ( ) * backslash
+			buf = append(buf, 92, hexDigits[c>>4]|32, hexDigits[c&15]|32) // lower case
+		default:
+			buf = append(buf, c)
+		}
+	}
+
+	return string(buf)
+}
+
+func (s *StringProc) unescapeLDAPFilter(str string) (string, error) {
+	buf := make([]byte, 0, len(str)) // prealloca
+
+	for i := 0; i < len(str); i++ {
+		switch c := str[i]; c {
+		case 92: // backslash
+			v, n := s.parseHexRun(str, i+1, 2)
+			if n != 2 {
+				return "", fmt.Errorf("Not allow escape sequence at %d", i)
+			}
+			buf = append(buf, byte(v))
+			i += n
+
+		case 0, 40, 41, 42: // NUL ( ) *
+			return "", fmt.Errorf("Not allow unescaped character %q at %d", c, i)
+
+		default:
+			buf = append(buf, c)
+		}
+	}
+
+	return string(buf), nil
+}
+
+// EscapeString is Quote or escape the string for the dialect (EscapeMySQL, EscapePostgreSQL, EscapeSQLite, EscapeShell, EscapeC, EscapeGo, EscapeJSON, EscapeJavaScript, EscapeCSV, EscapeLDAPDN, EscapeLDAPFilter)
+// NOTE : the string literals are returned with the quotes
+func (s *StringProc) EscapeString(str string, dialect int) (string, error) {
+	switch dialect {
+	case EscapeMySQL:
+		return s.escapeMySQL(str), nil
+	case EscapePostgreSQL, EscapeSQLite:
+		return s.escapeSQL(str)
+	case EscapeShell:
+		return s.escapeShell(str)
+	case EscapeC:
+		return s.escapeC(str), nil
+	case EscapeGo:
+		return strconv.Quote(str), nil
+	case EscapeJSON:
+		return s.escapeJSON(str), nil
+	case EscapeJavaScript:
+		return s.escapeJavaScript(str), nil
+	case EscapeCSV:
+		return s.escapeCSV(str), nil
+	case EscapeLDAPDN:
+		return s.escapeLDAPDN(str), nil
+	case EscapeLDAPFilter:
+		return s.escapeLDAPFilter(str), nil
+	}
+
+	return "", fmt.Errorf("Not allow dialect parameter : %v", dialect)
+}
+
+// UnescapeString is Unquote or unescape the string of the dialect, the reverse of EscapeString
+// NOTE : returns an error with the position if the string is malformed
+func (s *StringProc) UnescapeString(str string, dialect int) (string, error) {
+	switch dialect {
+	case EscapeMySQL:
+		return s.unescapeMySQL(str)
+	case EscapePostgreSQL, EscapeSQLite:
+		return s.unescapeSQL(str)
+	case EscapeShell:
+		return s.unescapeShell(str)
+	case EscapeC:
+		body, _, err := unquote(str, `"`)
+		if err != nil {
+			return "", err
+		}
+		return s.unescapeC(body, 34, 1, true)
+	case EscapeGo:
+		if len(str) == 0 || str[0] == 39 { // not a rune literal
+			return "", fmt.Errorf("Not allow unquoted string : %v", str)
+		}
+		return strconv.Unquote(str)
+	case EscapeJSON:
+		return s.unescapeJSON(str)
+	case EscapeJavaScript:
+		return s.unescapeJavaScript(str)
+	case EscapeCSV:
+		return s.unescapeCSV(str)
+	case EscapeLDAPDN:
+		return s.unescapeLDAPDN(str)
+	case EscapeLDAPFilter:
+		return s.unescapeLDAPFilter(str)
+	}
+
+	return "", fmt.Errorf("Not allow dialect parameter : %v", dialect)
+}
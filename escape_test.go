@@ -0,0 +1,152 @@
+package strutils_test
+
+import (
+	"testing"
+
+	strutils "github.com/torden/go-strutil"
+)
+
+type escapeTestVal struct {
+	dialect int
+	str     string
+	escaped string
+}
+
+func Test_strutils_EscapeString(t *testing.T) {
+	t.Parallel()
+
+	dataset := []escapeTestVal{
+		{strutils.EscapeMySQL, "It's \"a\"\\\x00\n\r\x1a", `'It\'s \"a\"\\\0\n\r\Z'`},
+		{strutils.EscapePostgreSQL, `It's \n`, `'It''s \n'`},
+		{strutils.EscapeSQLite, "''", `''''''`},
+		{strutils.EscapeShell, "It's $HOME", `'It'\''s $HOME'`},
+		{strutils.EscapeShell, "", `''`},
+		{strutils.EscapeC, "a\"b\\\n\t\x01\x7f??=é", `"a\"b\\\n\t\001\177\??=é"`},
+		{strutils.EscapeGo, "a\"b\n\x01é", `"a\"b\n\x01é"`},
+		{strutils.EscapeJSON, "a\"b\\\n\x01</é>\xff", `"a\"b\\\n\u0001</é>\ufffd"`},
+		{strutils.EscapeJavaScript, "It's \"a\"</script>\n\u2028&", `'It\x27s \x22a\x22\x3C/script\x3E\n\u2028\x26'`},
+		{strutils.EscapeCSV, "abc", `abc`},
+		{strutils.EscapeCSV, "a,\"b\"", `"a,""b"""`},
+		{strutils.EscapeCSV, " a", `" a"`},
+		{strutils.EscapeCSV, "a\nb", "\"a\nb\""},
+		{strutils.EscapeLDAPDN, " #Doe, John+<x>;=\\ ", `\ #Doe\, John\+\<x\>\;\=\\\ `},
+		{strutils.EscapeLDAPDN, "a#b\x00", `a#b\00`},
+		{strutils.EscapeLDAPFilter, "*)(uid=*)\\\x00", `\2a\29\28uid=\2a\29\5c\00`},
+	}
+
+	// check : common
+	for _, v := range dataset {
+		retval, err := strproc.EscapeString(v.str, v.dialect)
+		assert.AssertNil(t, err, "Error : %v", err)
+		assert.AssertEquals(t, retval, v.escaped, "Return Value mismatch (%v, %q).\nExpected: %v\nActual: %v", v.dialect, v.str, v.escaped, retval)
+	}
+
+	// check : not allowed NUL
+	for _, v := range []int{strutils.EscapePostgreSQL, strutils.EscapeSQLite, strutils.EscapeShell} {
+		_, err := strproc.EscapeString("a\x00b", v)
+		assert.AssertNotNil(t, err, "Couldn't check the `NUL character` (%v)\nError : %v", v, err)
+	}
+
+	// check : not allow dialect
+	_, err := strproc.EscapeString("abc", 0)
+	assert.AssertNotNil(t, err, "Couldn't check the `not allow dialect`\nError : %v", err)
+}
+
+func Test_strutils_UnescapeString(t *testing.T) {
+	t.Parallel()
+
+	dataset := []escapeTestVal{
+		{strutils.EscapeMySQL, "It's\t\\%\\_\x00", `'It''s\t\%\_\0'`},
+		{strutils.EscapeMySQL, "a'b", `"a'b"`},
+		{strutils.EscapeMySQL, "acd", `'\a\c\d'`},
+		{strutils.EscapeShell, "It's a", `It\'s' a'`},
+		{strutils.EscapeShell, "/usr/bin:x=1", `/usr/bin:x=1`},
+		{strutils.EscapeC, "\a\x7fAé\x00\x01", `"\a\x7f\101é\0\1"`},
+		{strutils.EscapeGo, "a\nb", "`a\nb`"},
+		{strutils.EscapeJavaScript, "a\"b'é\U0001F600\x00", `"a\"b'é😀\0"`},
+		{strutils.EscapeJavaScript, "ab\U0001F600c", "'a\\\nb\\u{1F600}\\c'"},
+		{strutils.EscapeLDAPDN, "Doe, John", `Doe\2C John`},
+		{strutils.EscapeLDAPFilter, "a*b", `a\2Ab`},
+	}
+
+	// check : common
+	for _, v := range dataset {
+		retval, err := strproc.UnescapeString(v.escaped, v.dialect)
+		assert.AssertNil(t, err, "Error : %v", err)
+		assert.AssertEquals(t, retval, v.str, "Return Value mismatch (%v, %v).\nExpected: %q\nActual: %q", v.dialect, v.escaped, v.str, retval)
+	}
+
+	// check : malformed
+	malformed := []escapeTestVal{
+		{strutils.EscapeMySQL, "", `abc`},
+		{strutils.EscapeMySQL, "", `'a'b'`},
+		{strutils.EscapeMySQL, "", `'a\'`},
+		{strutils.EscapePostgreSQL, "", `'a'b'`},
+		{strutils.EscapeSQLite, "", `"a"`},
+		{strutils.EscapeShell, "", `'abc`},
+		{strutils.EscapeShell, "", `a;rm -rf`},
+		{strutils.EscapeShell, "", `$(id)`},
+		{strutils.EscapeC, "", `"a"b"`},
+		{strutils.EscapeC, "", `"\q"`},
+		{strutils.EscapeC, "", `"\777"`},
+		{strutils.EscapeC, "", `"\x100"`},
+		{strutils.EscapeC, "", `"\ud800"`},
+		{strutils.EscapeGo, "", `'a'`},
+		{strutils.EscapeJSON, "", `"a\x"`},
+		{strutils.EscapeJSON, "", `abc`},
+		{strutils.EscapeJavaScript, "", `'a'b'`},
+		{strutils.EscapeJavaScript, "", `'\12'`},
+		{strutils.EscapeJavaScript, "", `'\xZ1'`},
+		{strutils.EscapeJavaScript, "", `'\u{110000}'`},
+		{strutils.EscapeCSV, "", `a,b`},
+		{strutils.EscapeCSV, "", `"a"b"`},
+		{strutils.EscapeLDAPDN, "", `a,b`},
+		{strutils.EscapeLDAPDN, "", `a\zz`},
+		{strutils.EscapeLDAPFilter, "", `a*`},
+		{strutils.EscapeLDAPFilter, "", `a\,`},
+	}
+
+	for _, v := range malformed {
+		retval, err := strproc.UnescapeString(v.escaped, v.dialect)
+		assert.AssertNotNil(t, err, "Couldn't check the `malformed` (%v, %v) : %q", v.dialect, v.escaped, retval)
+	}
+
+	// check : not allow dialect
+	_, err := strproc.UnescapeString("abc", 12)
+	assert.AssertNotNil(t, err, "Couldn't check the `not allow dialect`\nError : %v", err)
+}
+
+func Test_strutils_EscapeString_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	dialects := []int{
+		strutils.EscapeMySQL, strutils.EscapePostgreSQL, strutils.EscapeSQLite, strutils.EscapeShell,
+		strutils.EscapeC, strutils.EscapeGo, strutils.EscapeJSON, strutils.EscapeJavaScript,
+		strutils.EscapeCSV, strutils.EscapeLDAPDN, strutils.EscapeLDAPFilter,
+	}
+
+	dataset := []string{
+		"",
+		"abcdefg",
+		"대한민국만세",
+		"It's a \"quoted\" string",
+		`back\slash \\ \n`,
+		"new\nline\r\nand\ttab",
+		" leading and trailing ",
+		"#hash, comma; semi+plus <x> = *(a)",
+		"$(rm -rf /) `id` && | > ?? ??= %_",
+		"\x01\x1a\x7f  ",
+		"😀 emoji",
+	}
+
+	for _, d := range dialects {
+		for _, v := range dataset {
+			escaped, err := strproc.EscapeString(v, d)
+			assert.AssertNil(t, err, "Error : %v", err)
+
+			retval, err := strproc.UnescapeString(escaped, d)
+			assert.AssertNil(t, err, "Error (%v, %q => %q) : %v", d, v, escaped, err)
+			assert.AssertEquals(t, retval, v, "Return Value mismatch (%v, %q).\nExpected: %q\nActual: %q", d, escaped, v, retval)
+		}
+	}
+}
@@ -1,9 +1,15 @@
 package strutils_test
 
 import (
+	"context"
 	"fmt"
+	"html/template"
+	"io"
 	"io/ioutil"
 	"os"
+	"regexp"
+	"strings"
+	"time"
 
 	strutils "github.com/torden/go-strutil"
 )
@@ -698,3 +704,580 @@ func Example_strutils_MD5Hash() {
 	// 7ac66c0f148de9519b8bd264312c4d64
 	// 15f764f21d09b11102eb015fc8824d00
 }
+
+func Example_strutils_EncodeHTMLEntities() {
+	strproc := strutils.NewStringProc()
+
+	retval, err := strproc.EncodeHTMLEntities("<café>", strutils.EntityEncodeNamed)
+	if err != nil {
+		fmt.Println("Error : ", err)
+	}
+	fmt.Println(retval)
+
+	fmt.Println(strproc.DecodeHTMLEntities("&#x27;&#0000060&CounterClockwiseContourIntegral;&copy"))
+
+	// Output: &lt;caf&eacute;&gt;
+	// '<∳©
+}
+
+func Example_strutils_Canonicalize() {
+	strproc := strutils.NewStringProc()
+
+	retval, err := strproc.Canonicalize("%26lt%3Bscript%26gt%3B")
+	if err != nil {
+		fmt.Println("Error : ", err)
+	}
+
+	fmt.Println(retval.Str)
+	fmt.Println(retval.Depth, retval.EncodingNames(), retval.IsSuspicious())
+
+	// Output: <script>
+	// 2 [percent html entity] true
+}
+
+func Example_strutils_StripTagsWithPolicy() {
+	strproc := strutils.NewStringProc()
+
+	policy := &strutils.StripTagsPolicy{
+		Elements:   map[string][]string{"b": nil, "a": {"href"}},
+		URLSchemes: []string{"http", "https"},
+		ForceAttrs: map[string]map[string]string{"a": {"rel": "nofollow"}},
+	}
+
+	retval, err := strproc.StripTagsWithPolicy(`<b onclick="x()">Hi</b> <a href="javascript:alert(1)">a</a> <a href="https://golang.org">b</a><script>alert(1)</script>`, policy)
+	if err != nil {
+		fmt.Println("Error : ", err)
+	}
+	fmt.Println(retval)
+
+	// Output: <b>Hi</b> <a rel="nofollow">a</a> <a href="https://golang.org" rel="nofollow">b</a>
+}
+
+func Example_strutils_HTML2Text() {
+	strproc := strutils.NewStringProc()
+
+	retval, err := strproc.HTML2Text(`<h1>Fruits</h1><ul><li>apple</li><li>kiwi</li></ul><p>See <a href="https://golang.org">golang</a></p>`, strutils.LinkStyleFootnote)
+	if err != nil {
+		fmt.Println("Error : ", err)
+	}
+	fmt.Println(retval)
+
+	// Output: Fruits
+	//
+	// - apple
+	// - kiwi
+	//
+	// See golang[1]
+	//
+	// [1] https://golang.org
+}
+
+func Example_strutils_Markdown2HTML() {
+	strproc := strutils.NewStringProc()
+
+	retval, err := strproc.Markdown2HTML("# Hello\n\n*Go* is **fun**, see [golang](https://golang.org) <script>alert(1)</script>\n\n- one\n- two")
+	if err != nil {
+		fmt.Println("Error : ", err)
+	}
+	fmt.Print(retval)
+
+	retval, err = strproc.HTML2Markdown(retval)
+	if err != nil {
+		fmt.Println("Error : ", err)
+	}
+	fmt.Println(retval)
+
+	// Output: <h1>Hello</h1>
+	// <p><em>Go</em> is <strong>fun</strong>, see <a href="https://golang.org">golang</a> &lt;script&gt;alert(1)&lt;/script&gt;</p>
+	// <ul>
+	// <li>one</li>
+	// <li>two</li>
+	// </ul>
+	// # Hello
+	//
+	// *Go* is **fun**, see [golang](https://golang.org) \<script>alert(1)\</script>
+	//
+	// - one
+	// - two
+}
+
+func Example_strutils_Nl2BrWithOptions() {
+	strproc := strutils.NewStringProc()
+	opts := &strutils.Nl2BrOptions{Tag: strutils.BrTagHTML, KeepNewline: true, EscapeHTML: true, Paragraph: true}
+
+	retval, err := strproc.Nl2BrWithOptions("<b>a</b>\nb\n\nc", opts)
+	if err != nil {
+		fmt.Println("Error : ", err)
+	}
+	fmt.Println(retval)
+	fmt.Println(strproc.Br2NlWithOptions(retval, opts))
+
+	// Output: <p>&lt;b&gt;a&lt;/b&gt;<br>
+	// b</p>
+	//
+	// <p>c</p>
+	// <b>a</b>
+	// b
+	//
+	// c
+}
+
+func Example_strutils_EscapeString() {
+	strproc := strutils.NewStringProc()
+
+	retval, err := strproc.EscapeString("It's a \"test\"", strutils.EscapeMySQL)
+	if err != nil {
+		fmt.Println("Error : ", err)
+	}
+	fmt.Println(retval)
+
+	retval, err = strproc.EscapeString("It's a \"test\"", strutils.EscapeShell)
+	if err != nil {
+		fmt.Println("Error : ", err)
+	}
+	fmt.Println(retval)
+
+	retval, err = strproc.UnescapeString(retval, strutils.EscapeShell)
+	if err != nil {
+		fmt.Println("Error : ", err)
+	}
+	fmt.Println(retval)
+
+	_, err = strproc.UnescapeString(`'a'b'`, strutils.EscapePostgreSQL)
+	fmt.Println(err)
+
+	// Output: 'It\'s a \"test\"'
+	// 'It'\''s a "test"'
+	// It's a "test"
+	// Not allow unescaped quote at 2
+}
+
+func Example_strutils_StripSlashesWithMode() {
+	strproc := strutils.NewStringProc()
+
+	retval, err := strproc.StripSlashesWithMode(`a\tb\x41\u00e9\q`, strutils.StripSlashesEscape)
+	if err != nil {
+		fmt.Println("Error : ", err)
+	}
+	fmt.Printf("%q\n", retval)
+
+	_, err = strproc.StripSlashesWithMode(`a\tb\x41\u00e9\q`, strutils.StripSlashesStrict)
+	fmt.Println(err)
+
+	// Output: "a\tbAéq"
+	// Not allow escape sequence "\\q" at 14
+}
+
+func Example_strutils_Graphemes() {
+	strproc := strutils.NewStringProc()
+
+	str := "cafe\u0301 \U0001F1F0\U0001F1F7 \U0001F468\u200d\U0001F469\u200d\U0001F467"
+
+	fmt.Println(len([]rune(str)))
+	fmt.Println(strproc.GraphemeCount(str))
+	fmt.Printf("%+q\n", strproc.Graphemes("e\u0301\U0001F1F0\U0001F1F7"))
+
+	retval, err := strproc.GraphemeAt(str, -1)
+	if err != nil {
+		fmt.Println("Error : ", err)
+	}
+	fmt.Println(retval == "\U0001F468\u200d\U0001F469\u200d\U0001F467")
+
+	retval, err = strproc.GraphemeTruncate(str, 5, "...")
+	if err != nil {
+		fmt.Println("Error : ", err)
+	}
+	fmt.Println(retval)
+
+	fmt.Println(strproc.ReverseStr("\U0001F1F0\U0001F1F7\U0001F1EF\U0001F1F5") == "\U0001F1EF\U0001F1F5\U0001F1F0\U0001F1F7")
+
+	// Output: 14
+	// 8
+	// ["e\u0301" "\U0001f1f0\U0001f1f7"]
+	// true
+	// ca...
+	// true
+}
+
+func Example_strutils_Hash() {
+	strproc := strutils.NewStringProc()
+
+	retval, err := strproc.Hash("123456789", strutils.HashSHA256)
+	if err != nil {
+		fmt.Println("Error : ", err)
+	}
+	fmt.Println(retval)
+
+	retval, err = strproc.Hash("123456789", strutils.HashSHA256, strutils.HashOutputBase64)
+	if err != nil {
+		fmt.Println("Error : ", err)
+	}
+	fmt.Println(retval)
+
+	retval, err = strproc.Hash("123456789", strutils.HashCRC32Castagnoli)
+	if err != nil {
+		fmt.Println("Error : ", err)
+	}
+	fmt.Println(retval)
+
+	retval, err = strproc.FileHash("./LICENSE", strutils.HashSHA256)
+	if err != nil {
+		fmt.Println("Error : ", err)
+	}
+	fmt.Println(retval)
+
+	// Output: 15e2b0d3c33891ebb0f1ef609ec419420c20e320ce94c65fbc8c3312448eb225
+	// FeKw08M4keuw8e9gnsQZQgwg4yDOlMZfvIwzEkSOsiU=
+	// e3069283
+	// b6c6b249baaaaac1cce2217ffdd4974d8d47b372382c464f7cee71d0d619aa8f
+}
+
+func Example_strutils_FileHashes() {
+	strproc := strutils.NewStringProc()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	opts := &strutils.HashOptions{
+		Progress: func(done int64, total int64) {
+			if done == total {
+				fmt.Println("done")
+			}
+		},
+	}
+
+	retval, err := strproc.FileHashes(ctx, "./LICENSE", []uint8{strutils.HashMD5, strutils.HashSHA256}, opts)
+	if err != nil {
+		fmt.Println("Error : ", err)
+	}
+
+	fmt.Println(retval[strutils.HashMD5])
+	fmt.Println(retval[strutils.HashSHA256])
+
+	// Output: done
+	// 64e17a4e1c96bbfce57ab19cd0153e6a
+	// b6c6b249baaaaac1cce2217ffdd4974d8d47b372382c464f7cee71d0d619aa8f
+}
+
+func Example_strutils_VerifyManifest() {
+	strproc := strutils.NewStringProc()
+
+	manifest := "b6c6b249baaaaac1cce2217ffdd4974d8d47b372382c464f7cee71d0d619aa8f  LICENSE\n" +
+		"SHA256 (README.md) = 0000000000000000000000000000000000000000000000000000000000000000\n" +
+		"e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855  HELLO_GOLANG\n"
+
+	retval, err := strproc.VerifyManifest(context.Background(), manifest, "./", strutils.HashSHA256, 2)
+	if err != nil {
+		fmt.Println("Error : ", err)
+	}
+
+	for _, v := range retval {
+		fmt.Printf("%s: %s\n", v.Path, v.StatusName())
+	}
+
+	// Output: LICENSE: OK
+	// README.md: FAILED
+	// HELLO_GOLANG: MISSING
+}
+
+func Example_strutils_HMAC() {
+	strproc := strutils.NewStringProc()
+
+	retval, err := strproc.HMAC("what do ya want for nothing?", "Jefe", strutils.HashSHA256)
+	if err != nil {
+		fmt.Println("Error : ", err)
+	}
+	fmt.Println(retval)
+
+	ok, err := strproc.VerifyHMAC("what do ya want for nothing?", "Jefe", strutils.HashSHA256, retval)
+	if err != nil {
+		fmt.Println("Error : ", err)
+	}
+	fmt.Println(ok)
+
+	ok, err = strproc.VerifyHMAC("what do ya want for nothing!", "Jefe", strutils.HashSHA256, retval)
+	if err != nil {
+		fmt.Println("Error : ", err)
+	}
+	fmt.Println(ok)
+
+	retval, err = strproc.HKDF("secret", "salt", "session key", 16, strutils.HashSHA256, strutils.HashOutputBase64)
+	if err != nil {
+		fmt.Println("Error : ", err)
+	}
+	fmt.Println(len(retval))
+
+	// Output: 5bdcc146bf60754e6a042426089575c75a003f089d2739839dec58b964ec3843
+	// true
+	// false
+	// 24
+}
+
+func Example_strutils_RegExpNamedGroupsAll() {
+	strproc := strutils.NewStringProc()
+
+	regex := regexp.MustCompile(`(?P<key>[a-z]+)=(?P<val>[0-9]*)|(?P<key>[a-z]+):(?P<val>[0-9]*)`)
+
+	retval, err := strproc.RegExpNamedGroupsAll(regex, "a=1, bc=, d:23", -1)
+	if err != nil {
+		fmt.Println("Error : ", err)
+	}
+
+	for _, v := range retval {
+		key, _ := v.Get("key")
+		val, _ := v.Get("val")
+		fmt.Printf("%q [%d:%d] key=%q val=%q\n", v.Value, v.Start, v.End, key, val)
+	}
+
+	// Output: "a=1" [0:3] key="a" val="1"
+	// "bc=" [5:8] key="bc" val=""
+	// "d:23" [10:14] key="d" val="23"
+}
+
+func Example_strutils_RegExpUnmarshal() {
+	strproc := strutils.NewStringProc()
+
+	type release struct {
+		Name    string        `re:"name"`
+		Major   int           `re:"major"`
+		Minor   int           `re:"minor"`
+		Date    time.Time     `re:"date" layout:"2006-01-02"`
+		Timeout time.Duration `re:"timeout"`
+		Stable  bool          `re:"stable"`
+	}
+
+	regex := regexp.MustCompile(`^(?P<name>\w+) (?P<major>\d+)\.(?P<minor>\d+) (?P<date>\S+) (?P<timeout>\S+) (?P<stable>\w+)$`)
+
+	var retval release
+	err := strproc.RegExpUnmarshal(regex, "go 1.14 2020-02-25 30s true", &retval)
+	if err != nil {
+		fmt.Println("Error : ", err)
+	}
+	fmt.Println(retval.Name, retval.Major, retval.Minor, retval.Date.Format("Jan 2, 2006"), retval.Timeout, retval.Stable)
+
+	err = strproc.RegExpUnmarshal(regex, "go 1.999999999999999999999 2020-02-25 30s yes", &retval)
+	fmt.Println(err)
+
+	// Output: go 1 14 Feb 25, 2020 30s true
+	// field Minor (group minor) : couldn't convert "999999999999999999999" : value out of range, field Stable (group stable) : couldn't convert "yes" : invalid syntax
+}
+
+func Example_strutils_Grok() {
+	strproc := strutils.NewStringProc()
+	grok := strutils.NewGrok()
+
+	err := grok.Add("METHOD", `GET|POST|PUT|DELETE`)
+	if err != nil {
+		fmt.Println("Error : ", err)
+	}
+
+	regex, err := grok.Compile(`%{IP:client} %{METHOD:method} %{URIPATH:path}`)
+	if err != nil {
+		fmt.Println("Error : ", err)
+	}
+
+	retval, err := strproc.RegExpNamedGroups(regex, "55.3.244.1 GET /index.html")
+	if err != nil {
+		fmt.Println("Error : ", err)
+	}
+	fmt.Println(retval["client"], retval["method"], retval["path"])
+
+	err = grok.Add("LOOP", `a|%{LOOP}`)
+	fmt.Println(err)
+
+	// Output: 55.3.244.1 GET /index.html
+	// Not allow recursive grok pattern : LOOP -> LOOP
+}
+
+func Example_strutils_ConvertToStrWithOptions() {
+	strproc := strutils.NewStringProc()
+
+	var nilptr *int
+	opts := &strutils.ConvertOptions{
+		Nil:            "NULL",
+		Format:         strutils.ConvertFormatBracket,
+		FloatVerb:      'f',
+		FloatPrecision: 2,
+	}
+
+	dataset := []interface{}{
+		nilptr,
+		3 * time.Second,
+		[]interface{}{1, 2.5, "a", nil},
+		map[string]float64{"b": 2, "a": 1.125},
+	}
+
+	for _, v := range dataset {
+		retval, err := strproc.ConvertToStrWithOptions(v, opts)
+		if err != nil {
+			fmt.Println("Error : ", err)
+		}
+		fmt.Println(retval)
+	}
+
+	// Output: NULL
+	// 3s
+	// [1, 2.50, a, NULL]
+	// {a: 1.12, b: 2.00}
+}
+
+func Example_strutils_ConvertFromStr() {
+	strproc := strutils.NewStringProc()
+
+	var (
+		enabled bool
+		size    int64
+		count   uint8
+		timeout *time.Duration
+		ports   []int
+	)
+
+	dataset := []struct {
+		str string
+		dst interface{}
+	}{
+		{"yes", &enabled},
+		{"1.5MiB", &size},
+		{"30s", &timeout},
+		{"80, 443, 8080", &ports},
+		{"256", &count},
+	}
+
+	for _, v := range dataset {
+		if err := strproc.ConvertFromStr(v.str, v.dst); err != nil {
+			fmt.Println("Error : ", err)
+		}
+	}
+
+	fmt.Println(enabled, size, *timeout, ports, count)
+
+	// Output:
+	// Error :  Out of range of uint8 : "256"
+	// true 1572864 30s [80 443 8080] 0
+}
+
+func Example_strutils_NumberFmtOf() {
+	type FileSize int64
+
+	fmt.Println(strutils.NumberFmtOf(123456789))
+	fmt.Println(strutils.NumberFmtOf(-12345.678))
+	fmt.Println(strutils.ToString(float32(0.1)))
+
+	retval, err := strutils.HumanBytes(FileSize(1536), 2, strutils.UpperCaseDouble)
+	if err != nil {
+		fmt.Println("Error : ", err)
+	}
+	fmt.Println(retval)
+
+	buf := make([]byte, 0, 64)
+	buf = append(buf, "rows="...)
+	buf = strutils.AppendNumberFmt(buf, uint64(1000000))
+	buf = append(buf, " size="...)
+	buf, _ = strutils.AppendHumanBytes(buf, 3<<30, 1, strutils.LowerCaseSingle)
+	fmt.Println(string(buf))
+
+	// Output:
+	// 123,456,789
+	// -12,345.678
+	// 0.1
+	// 1.50KB
+	// rows=1,000,000 size=3.0g
+}
+
+func Example_strutils_NewReader() {
+	wordwrap, err := strutils.NewWordWrapTransformer(10, "\n")
+	if err != nil {
+		fmt.Println("Error : ", err)
+	}
+
+	// url decode -> strip tags -> word wrap -> nl2br
+	chain := strutils.ChainTransformers(
+		strutils.NewDecodeURLEncodedTransformer(),
+		strutils.NewStripTagsTransformer(),
+		wordwrap,
+		strutils.NewNl2BrTransformer(),
+	)
+
+	r := strutils.NewReader(strings.NewReader("%3Cb%3EJust%3C/b%3E a String Processing Library"), chain)
+	if _, err := io.Copy(os.Stdout, r); err != nil {
+		fmt.Println("Error : ", err)
+	}
+	fmt.Println()
+
+	w := strutils.NewWriter(os.Stdout, strutils.NewAddSlashesTransformer())
+	fmt.Fprint(w, `C:\Users\`)
+	fmt.Fprint(w, `torden`)
+	w.Close()
+	fmt.Println()
+
+	// Output:
+	// Just a String<br />Processing<br />Library
+	// C:\\Users\\torden
+}
+
+func Example_strutils_Chain() {
+	str := "Just%20a<br />String <b>Processing</b> Library%21"
+
+	retval, err := strutils.Chain(str).Br2Nl().DecodeURLEncoded().StripTags().WordWrapAround(10, "\n").Result()
+	if err != nil {
+		fmt.Println("Error : ", err)
+	}
+	fmt.Println(retval)
+
+	// the first error short-circuits the chain
+	_, err = strutils.Chain(str).StripTags().WordWrapSimple(0, "\n").Nl2Br().Result()
+	fmt.Println(err)
+
+	// Output:
+	// Just a
+	// String
+	// Processing
+	// Library!
+	// chain step 2 (WordWrapSimple) : wd At least 1 or More
+}
+
+func Example_strutils_Pipeline() {
+	registry := strutils.NewPipelineRegistry()
+
+	p, err := registry.CompileJSON([]byte(`[{"op":"strip_tags"},{"op":"pad_left","fill":"0","width":10}]`))
+	if err != nil {
+		fmt.Println("Error : ", err)
+	}
+
+	retval, err := p.Run("<b>1234</b>")
+	if err != nil {
+		fmt.Println("Error : ", err)
+	}
+	fmt.Println(retval)
+
+	// the spec is validated before running
+	_, err = registry.CompileJSON([]byte(`[{"op":"pad_left","fill":"0","width":"ten"}]`))
+	fmt.Println(err)
+
+	// Output:
+	// 0000001234
+	// pipeline step 1 (pad_left) parameter width : Not allow type string, expected int
+}
+
+func Example_strutils_FuncMap() {
+	tmpl := template.Must(template.New("mail").Funcs(strutils.FuncMap()).Parse(
+		`<p>{{ .Name | upperCaseFirstWords }}, {{ .Size | humanByteSize 1 "upper_double" }} of {{ .Count | numberFmt }} files</p>
+<p>{{ .Memo | nl2br }}</p>
+{{ if isValidEmail .Email }}<p>{{ .Email }}</p>{{ end }}`))
+
+	err := tmpl.Execute(os.Stdout, map[string]interface{}{
+		"Name":  "just a string",
+		"Size":  2048,
+		"Count": 1234567,
+		"Memo":  "<b>Hello</b>\nWorld",
+		"Email": "abc@example.com",
+	})
+	if err != nil {
+		fmt.Println("Error : ", err)
+	}
+
+	// Output:
+	// <p>Just A String, 2.0KB of 1,234,567 files</p>
+	// <p>&lt;b&gt;Hello&lt;/b&gt;<br />World</p>
+	// <p>abc@example.com</p>
+}
@@ -0,0 +1,127 @@
+package strutils
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"unsafe"
+)
+
+// Integer is the constraint of the integer types
+type Integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 | ~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+// Float is the constraint of the floating-point types
+type Float interface {
+	~float32 | ~float64
+}
+
+// Number is the constraint of the integer and the floating-point types
+type Number interface {
+	Integer | Float
+}
+
+// size units of HumanBytes by the unit type, same as HumanByteSize
+var humanByteUnits = [...][]string{
+	LowerCaseSingle: sizeStrLowerCaseSingle,
+	LowerCaseDouble: sizeStrLowerCaseDouble,
+	UpperCaseSingle: sizeStrUpperCaseSingle,
+	UpperCaseDouble: sizeStrUpperCaseDouble,
+	CamelCaseDouble: sizeStrCamelCaseDouble,
+	CamelCaseLong:   sizeStrCamelCaseLong,
+}
+
+// numberKind returns the kind of the T, floating-point or integer, signed or unsigned, and the bit size
+func numberKind[T Number]() (float bool, signed bool, bits int) {
+	var zero T
+	one := T(1)
+
+	return one/2 != zero, zero-one < zero, int(unsafe.Sizeof(zero)) * 8
+}
+
+// AppendToString appends the string form of the number to dst and returns the extended buffer, same as ConvertToStr
+// the floating-point is the shortest representation (%g)
+func AppendToString[T Number](dst []byte, v T) []byte {
+	float, signed, bits := numberKind[T]()
+
+	switch {
+	case float:
+		return strconv.AppendFloat(dst, float64(v), 'g', -1, bits)
+	case signed:
+		return strconv.AppendInt(dst, int64(v), 10)
+	}
+
+	return strconv.AppendUint(dst, uint64(v), 10)
+}
+
+// ToString is Convert the number to string without reflect, same as ConvertToStr
+func ToString[T Number](v T) string {
+	var buf [32]byte // prealloca
+	return string(AppendToString(buf[:0], v))
+}
+
+// AppendNumberFmt appends the number with english notation grouped thousands to dst and returns the extended buffer
+// only the digits of the integer part are grouped (1,234.5678, -1,234, 1.5e+21)
+func AppendNumberFmt[T Number](dst []byte, v T) []byte {
+	var buf [32]byte // prealloca
+	num := AppendToString(buf[:0], v)
+
+	start := 0
+	if len(num) > 0 && num[0] == 45 { // -
+		dst = append(dst, 45)
+		start = 1
+	}
+
+	end := start
+	for end < len(num) && num[end] >= 48 && num[end] <= 57 { // 0-9
+		end++
+	}
+
+	for i := start; i < end; i++ {
+		if i > start && (end-i)%3 == 0 {
+			dst = append(dst, 44) // ,
+		}
+
+		dst = append(dst, num[i])
+	}
+
+	return append(dst, num[end:]...)
+}
+
+// NumberFmtOf is format the number with english notation grouped thousands without reflect, the generic version of NumberFmt
+func NumberFmtOf[T Number](v T) string {
+	var buf [48]byte // prealloca
+	return string(AppendNumberFmt(buf[:0], v))
+}
+
+// AppendHumanBytes appends the easy readable size string of the byte size to dst and returns the extended buffer
+// the unit is grouped by the length of the string form (ToString, the sign and the fraction are counted), same as HumanByteSize
+func AppendHumanBytes[T Number](dst []byte, v T, decimals int, unit uint8) ([]byte, error) {
+	if unit < LowerCaseSingle || unit > CamelCaseLong {
+		return dst, fmt.Errorf("Not allow unit parameter : %v", unit)
+	}
+
+	var buf [32]byte // prealloca
+	factor := (len(AppendToString(buf[:0], v)) - 1) / 3
+
+	dst = strconv.AppendFloat(dst, float64(v)/math.Pow(1024, float64(factor)), 'f', decimals, 64)
+
+	sizeStr := humanByteUnits[unit]
+	if len(sizeStr) > factor {
+		return append(dst, sizeStr[factor]...), nil
+	}
+
+	return append(dst, "NaN"...), nil
+}
+
+// HumanBytes is Byte Size convert to Easy Readable Size String without reflect, the generic version of HumanByteSize
+func HumanBytes[T Number](v T, decimals int, unit uint8) (string, error) {
+	var buf [48]byte // prealloca
+	retval, err := AppendHumanBytes(buf[:0], v, decimals, unit)
+	if err != nil {
+		return "", err
+	}
+
+	return string(retval), nil
+}
@@ -0,0 +1,170 @@
+package strutils_test
+
+import (
+	"math"
+	"testing"
+
+	strutils "github.com/torden/go-strutil"
+)
+
+type genericSize int64
+
+func Test_strutils_NumberFmtOf(t *testing.T) {
+	t.Parallel()
+
+	// check : same as NumberFmt
+	dataset := []interface{}{
+		123456789101112, 123456.1234, 1.1234561e+06, 1234.1234, 12345.1234, -12345.16, 1234, 12.12123098123, 1.212e+24, 123456789,
+		int8(127), int16(12345), int32(-1234567), uint(1000), uint8(255), uint16(65535), uint32(4294967295), uint64(18446744073709551615),
+		float32(1234.5), float32(0.1), math.MaxInt64, -1234567,
+	}
+
+	for _, v := range dataset {
+		expected, err := strproc.NumberFmt(v)
+		assert.AssertNil(t, err, "Error (%v) : %v", v, err)
+
+		var retval string
+		switch n := v.(type) {
+		case int:
+			retval = strutils.NumberFmtOf(n)
+		case int8:
+			retval = strutils.NumberFmtOf(n)
+		case int16:
+			retval = strutils.NumberFmtOf(n)
+		case int32:
+			retval = strutils.NumberFmtOf(n)
+		case uint:
+			retval = strutils.NumberFmtOf(n)
+		case uint8:
+			retval = strutils.NumberFmtOf(n)
+		case uint16:
+			retval = strutils.NumberFmtOf(n)
+		case uint32:
+			retval = strutils.NumberFmtOf(n)
+		case uint64:
+			retval = strutils.NumberFmtOf(n)
+		case float32:
+			retval = strutils.NumberFmtOf(n)
+		case float64:
+			retval = strutils.NumberFmtOf(n)
+		}
+
+		assert.AssertEquals(t, retval, expected, "Return Value mismatch (%T).\nExpected: %v\nActual: %v", v, expected, retval)
+	}
+
+	// check : the negative, the exponent and the named type
+	assert.AssertEquals(t, strutils.NumberFmtOf(-123456), "-123,456", "Return Value mismatch.\nExpected: %v\nActual: %v", "-123,456", strutils.NumberFmtOf(-123456))
+	assert.AssertEquals(t, strutils.NumberFmtOf(-100), "-100", "Return Value mismatch.\nExpected: %v\nActual: %v", "-100", strutils.NumberFmtOf(-100))
+	assert.AssertEquals(t, strutils.NumberFmtOf(int64(math.MinInt64)), "-9,223,372,036,854,775,808", "Return Value mismatch.\nExpected: %v\nActual: %v", "-9,223,372,036,854,775,808", strutils.NumberFmtOf(int64(math.MinInt64)))
+	assert.AssertEquals(t, strutils.NumberFmtOf(1e21), "1e+21", "Return Value mismatch.\nExpected: %v\nActual: %v", "1e+21", strutils.NumberFmtOf(1e21))
+	assert.AssertEquals(t, strutils.NumberFmtOf(math.Inf(-1)), "-Inf", "Return Value mismatch.\nExpected: %v\nActual: %v", "-Inf", strutils.NumberFmtOf(math.Inf(-1)))
+	assert.AssertEquals(t, strutils.NumberFmtOf(genericSize(1234567)), "1,234,567", "Return Value mismatch.\nExpected: %v\nActual: %v", "1,234,567", strutils.NumberFmtOf(genericSize(1234567)))
+
+	retval := string(strutils.AppendNumberFmt([]byte("total: "), 1234.5))
+	assert.AssertEquals(t, retval, "total: 1,234.5", "Return Value mismatch.\nExpected: %v\nActual: %v", "total: 1,234.5", retval)
+}
+
+func Test_strutils_HumanBytes(t *testing.T) {
+	t.Parallel()
+
+	// check : same as HumanByteSize
+	dataset := []int64{0, 1, 1023, 1024, 1536, 1048576, 123456789, 1 << 40, -2048, math.MaxInt64}
+	units := []uint8{strutils.LowerCaseSingle, strutils.LowerCaseDouble, strutils.UpperCaseSingle, strutils.UpperCaseDouble, strutils.CamelCaseDouble, strutils.CamelCaseLong}
+
+	for _, v := range dataset {
+		for _, unit := range units {
+			expected, err := strproc.HumanByteSize(v, 2, unit)
+			assert.AssertNil(t, err, "Error (%v) : %v", v, err)
+
+			retval, err := strutils.HumanBytes(v, 2, unit)
+			assert.AssertNil(t, err, "Error (%v) : %v", v, err)
+			assert.AssertEquals(t, retval, expected, "Return Value mismatch (%v, %v).\nExpected: %v\nActual: %v", v, unit, expected, retval)
+		}
+	}
+
+	// check : same as HumanByteSize, the sign and the fraction are counted
+	floatset := []float64{-123, -123456, 123456.7, 1536.5, 0.5, -0.25, 1e21, 1048576.125}
+	for _, v := range floatset {
+		expected, err := strproc.HumanByteSize(v, 2, strutils.UpperCaseDouble)
+		assert.AssertNil(t, err, "Error (%v) : %v", v, err)
+
+		retval, err := strutils.HumanBytes(v, 2, strutils.UpperCaseDouble)
+		assert.AssertNil(t, err, "Error (%v) : %v", v, err)
+		assert.AssertEquals(t, retval, expected, "Return Value mismatch (%v).\nExpected: %v\nActual: %v", v, expected, retval)
+	}
+
+	for _, v := range []float32{-123, 123456.7, 1536.25} {
+		expected, err := strproc.HumanByteSize(v, 2, strutils.UpperCaseDouble)
+		assert.AssertNil(t, err, "Error (%v) : %v", v, err)
+
+		retval, err := strutils.HumanBytes(v, 2, strutils.UpperCaseDouble)
+		assert.AssertNil(t, err, "Error (%v) : %v", v, err)
+		assert.AssertEquals(t, retval, expected, "Return Value mismatch (%v).\nExpected: %v\nActual: %v", v, expected, retval)
+	}
+
+	retval, err := strutils.HumanBytes(-123, 2, strutils.UpperCaseDouble)
+	assert.AssertNil(t, err, "Error : %v", err)
+	assert.AssertEquals(t, retval, "-0.12KB", "Return Value mismatch.\nExpected: %v\nActual: %v", "-0.12KB", retval)
+
+	// check : float, named type, append
+	retval, err = strutils.HumanBytes(1536.5, 1, strutils.UpperCaseDouble)
+	assert.AssertNil(t, err, "Error : %v", err)
+	assert.AssertEquals(t, retval, "1.5KB", "Return Value mismatch.\nExpected: %v\nActual: %v", "1.5KB", retval)
+
+	retval, err = strutils.HumanBytes(genericSize(3<<20), 0, strutils.CamelCaseLong)
+	assert.AssertNil(t, err, "Error : %v", err)
+	assert.AssertEquals(t, retval, "3MegaByte", "Return Value mismatch.\nExpected: %v\nActual: %v", "3MegaByte", retval)
+
+	bytes, err := strutils.AppendHumanBytes([]byte("size="), uint32(2048), 2, strutils.LowerCaseSingle)
+	assert.AssertNil(t, err, "Error : %v", err)
+	assert.AssertEquals(t, string(bytes), "size=2.00k", "Return Value mismatch.\nExpected: %v\nActual: %v", "size=2.00k", string(bytes))
+
+	// check : not allow unit
+	for _, unit := range []uint8{0, strutils.CamelCaseLong + 1} {
+		_, err := strutils.HumanBytes(1024, 2, unit)
+		assert.AssertNotNil(t, err, "Couldn't check the `not allow unit` (%v)\nError : %v", unit, err)
+	}
+}
+
+func Test_strutils_ToString(t *testing.T) {
+	t.Parallel()
+
+	dataset := []struct {
+		retval string
+		str    string
+	}{
+		{strutils.ToString(-42), "-42"},
+		{strutils.ToString(uint64(18446744073709551615)), "18446744073709551615"},
+		{strutils.ToString(uintptr(255)), "255"},
+		{strutils.ToString(float32(0.1)), "0.1"},
+		{strutils.ToString(0.1), "0.1"},
+		{strutils.ToString(1e21), "1e+21"},
+		{strutils.ToString(genericSize(7)), "7"},
+		{string(strutils.AppendToString([]byte("n="), int8(-8))), "n=-8"},
+	}
+
+	// check : common
+	for _, v := range dataset {
+		assert.AssertEquals(t, v.retval, v.str, "Return Value mismatch.\nExpected: %v\nActual: %v", v.str, v.retval)
+	}
+
+	// check : same as ConvertToStr
+	for _, v := range []float64{123.456, 1e-7, math.MaxFloat64} {
+		expected, err := strproc.ConvertToStr(v)
+		assert.AssertNil(t, err, "Error (%v) : %v", v, err)
+		assert.AssertEquals(t, strutils.ToString(v), expected, "Return Value mismatch.\nExpected: %v\nActual: %v", expected, strutils.ToString(v))
+	}
+}
+
+func Test_strutils_GenericAllocs(t *testing.T) {
+	buf := make([]byte, 0, 64)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		buf = strutils.AppendNumberFmt(buf[:0], int64(123456789101112))
+		buf = strutils.AppendNumberFmt(buf[:0], -12345.16)
+		buf = strutils.AppendToString(buf[:0], uint32(4294967295))
+		buf, _ = strutils.AppendHumanBytes(buf[:0], 123456789, 2, strutils.UpperCaseDouble)
+	})
+
+	assert.AssertEquals(t, allocs, float64(0), "Allocations mismatch.\nExpected: %v\nActual: %v", 0, allocs)
+}
@@ -1,5 +1,5 @@
 module github.com/torden/go-strutil
 
-go 1.14
+go 1.18
 
 require github.com/dustin/go-humanize v1.0.0
@@ -0,0 +1,264 @@
+package strutils
+
+//go:generate go run ./internal/gen -ucd https://www.unicode.org/Public/16.0.0/ucd -o grapheme_table.go -test testdata/GraphemeBreakTest.txt
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// grapheme cluster break property (UAX #29)
+const (
+	graphemeAny uint8 = iota
+	graphemeCR
+	graphemeLF
+	graphemeControl
+	graphemeExtend
+	graphemeZWJ
+	graphemeRI
+	graphemePrepend
+	graphemeSpacingMark
+	graphemeL
+	graphemeV
+	graphemeT
+	graphemeLV
+	graphemeLVT
+	graphemeExtPict
+	graphemeInCBConsonant
+)
+
+type graphemeRange struct {
+	lo   rune
+	hi   rune
+	prop uint8
+}
+
+// graphemeLookup is binary search the range table
+func graphemeLookup(table []graphemeRange, r rune) (uint8, bool) {
+	lo, hi := 0, len(table)
+	for lo < hi {
+		m := int(uint(lo+hi) >> 1)
+		if r < table[m].lo {
+			hi = m
+		} else if r > table[m].hi {
+			lo = m + 1
+		} else {
+			return table[m].prop, true
+		}
+	}
+
+	return graphemeAny, false
+}
+
+// graphemeProperty is return the grapheme cluster break property of the rune
+func graphemeProperty(r rune) uint8 {
+	if r < 0x7f {
+		switch {
+		case r == 13: // \r
+			return graphemeCR
+		case r == 10: // \n
+			return graphemeLF
+		case r < 32:
+			return graphemeControl
+		}
+
+		return graphemeAny
+	}
+
+	prop, _ := graphemeLookup(graphemeBreakTable, r)
+	return prop
+}
+
+// graphemeInCBLinker is InCB=Linker (the virama of the Indic scripts)
+func graphemeInCBLinker(r rune) bool {
+	_, ok := graphemeLookup(graphemeInCBLinkerTable, r)
+	return ok
+}
+
+// graphemeLen is return the byte length of the first extended grapheme cluster of the str
+func graphemeLen(str string) int {
+	if len(str) == 0 {
+		return 0
+	}
+
+	r, pos := utf8.DecodeRuneInString(str)
+	prev := graphemeProperty(r)
+
+	// state of GB9c, 1 : after consonant, 2 : after consonant + linker
+	incb := 0
+	if prev == graphemeInCBConsonant {
+		incb = 1
+	}
+
+	// state of GB11, 1 : after pictographic + extend*, 2 : after pictographic + extend* + zwj
+	emoji := 0
+	if prev == graphemeExtPict {
+		emoji = 1
+	}
+
+	// GB12, GB13 : count of the regional indicator
+	ri := 0
+	if prev == graphemeRI {
+		ri = 1
+	}
+
+	for pos < len(str) {
+		r, size := utf8.DecodeRuneInString(str[pos:])
+		cur := graphemeProperty(r)
+
+		if !graphemeJoin(prev, cur, incb, emoji, ri) {
+			break
+		}
+
+		// update the state
+		switch {
+		case cur == graphemeInCBConsonant:
+			incb = 1
+		case graphemeInCBLinker(r):
+			if incb > 0 {
+				incb = 2
+			}
+		case incb > 0 && (cur == graphemeExtend || cur == graphemeZWJ):
+			if _, ok := graphemeLookup(graphemeInCBExtendTable, r); !ok {
+				incb = 0
+			}
+		default:
+			incb = 0
+		}
+
+		switch {
+		case cur == graphemeExtPict:
+			emoji = 1
+		case cur == graphemeExtend && emoji == 1:
+		case cur == graphemeZWJ && emoji == 1:
+			emoji = 2
+		default:
+			emoji = 0
+		}
+
+		if cur == graphemeRI {
+			ri++
+		} else {
+			ri = 0
+		}
+
+		prev = cur
+		pos += size
+	}
+
+	return pos
+}
+
+// graphemeJoin is return false when there is a boundary between prev and cur
+func graphemeJoin(prev uint8, cur uint8, incb int, emoji int, ri int) bool {
+	switch {
+	case prev == graphemeCR && cur == graphemeLF: // GB3
+		return true
+	case prev == graphemeCR || prev == graphemeLF || prev == graphemeControl: // GB4
+		return false
+	case cur == graphemeCR || cur == graphemeLF || cur == graphemeControl: // GB5
+		return false
+	case prev == graphemeL && (cur == graphemeL || cur == graphemeV || cur == graphemeLV || cur == graphemeLVT): // GB6
+		return true
+	case (prev == graphemeLV || prev == graphemeV) && (cur == graphemeV || cur == graphemeT): // GB7
+		return true
+	case (prev == graphemeLVT || prev == graphemeT) && cur == graphemeT: // GB8
+		return true
+	case cur == graphemeExtend || cur == graphemeZWJ: // GB9
+		return true
+	case cur == graphemeSpacingMark: // GB9a
+		return true
+	case prev == graphemePrepend: // GB9b
+		return true
+	case cur == graphemeInCBConsonant && incb == 2: // GB9c
+		return true
+	case cur == graphemeExtPict && emoji == 2 && prev == graphemeZWJ: // GB11
+		return true
+	case prev == graphemeRI && cur == graphemeRI && ri%2 == 1: // GB12, GB13
+		return true
+	}
+
+	return false // GB999
+}
+
+// Graphemes is split a string into the user-perceived characters (UAX #29 extended grapheme clusters)
+func (s *StringProc) Graphemes(str string) []string {
+	retval := make([]string, 0, len(str)) // prealloca
+	for len(str) > 0 {
+		n := graphemeLen(str)
+		retval = append(retval, str[:n])
+		str = str[n:]
+	}
+
+	return retval
+}
+
+// GraphemeCount is return the number of the user-perceived characters (UAX #29 extended grapheme clusters)
+func (s *StringProc) GraphemeCount(str string) int {
+	cnt := 0
+	for len(str) > 0 {
+		str = str[graphemeLen(str):]
+		cnt++
+	}
+
+	return cnt
+}
+
+// GraphemeAt is return the user-perceived character at the index, a negative index counts from the end
+func (s *StringProc) GraphemeAt(str string, idx int) (string, error) {
+	if idx < 0 {
+		idx += s.GraphemeCount(str)
+	}
+
+	if idx >= 0 {
+		for i := 0; len(str) > 0; i++ {
+			n := graphemeLen(str)
+			if i == idx {
+				return str[:n], nil
+			}
+
+			str = str[n:]
+		}
+	}
+
+	return "", fmt.Errorf("Not allow index parameter : %v", idx)
+}
+
+// GraphemeTruncate is truncate a string to the maximum number of the user-perceived characters, the tail is appended if truncated
+// the tail is counted in the maximum, the user-perceived character is never cut in the middle
+func (s *StringProc) GraphemeTruncate(str string, mx int, tail string) (string, error) {
+	if mx < 0 {
+		return "", fmt.Errorf("Not allow mx parameter : %v", mx)
+	}
+
+	if s.GraphemeCount(str) <= mx {
+		return str, nil
+	}
+
+	tailcnt := s.GraphemeCount(tail)
+	if tailcnt > mx {
+		return "", fmt.Errorf("Not allow tail parameter, longer than mx : %v", tail)
+	}
+
+	pos := 0
+	for i := 0; i < mx-tailcnt; i++ {
+		pos += graphemeLen(str[pos:])
+	}
+
+	return str[:pos] + tail, nil
+}
+
+// ReverseGraphemes is reverse a string by the user-perceived characters (UAX #29 extended grapheme clusters)
+// the combining marks, the flags and the emoji sequences are kept in order
+func (s *StringProc) ReverseGraphemes(str string) string {
+	buf := make([]byte, len(str)) // prealloca
+	pos := len(buf)
+	for len(str) > 0 {
+		n := graphemeLen(str)
+		pos -= n
+		copy(buf[pos:], str[:n])
+		str = str[n:]
+	}
+
+	return string(buf)
+}
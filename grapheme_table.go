@@ -0,0 +1,1872 @@
+// Code generated by internal/gen from the Unicode 16.0.0 character database (GraphemeBreakProperty.txt, emoji-data.txt, DerivedCoreProperties.txt). DO NOT EDIT.
+
+package strutils
+
+// ranges of the grapheme cluster break property (and Extended_Pictographic, InCB=Consonant), sorted. not listed is graphemeAny
+var graphemeBreakTable = []graphemeRange{
+	{0x0, 0x9, graphemeControl},
+	{0xA, 0xA, graphemeLF},
+	{0xB, 0xC, graphemeControl},
+	{0xD, 0xD, graphemeCR},
+	{0xE, 0x1F, graphemeControl},
+	{0x7F, 0x9F, graphemeControl},
+	{0xA9, 0xA9, graphemeExtPict},
+	{0xAD, 0xAD, graphemeControl},
+	{0xAE, 0xAE, graphemeExtPict},
+	{0x300, 0x36F, graphemeExtend},
+	{0x483, 0x489, graphemeExtend},
+	{0x591, 0x5BD, graphemeExtend},
+	{0x5BF, 0x5BF, graphemeExtend},
+	{0x5C1, 0x5C2, graphemeExtend},
+	{0x5C4, 0x5C5, graphemeExtend},
+	{0x5C7, 0x5C7, graphemeExtend},
+	{0x600, 0x605, graphemePrepend},
+	{0x610, 0x61A, graphemeExtend},
+	{0x61C, 0x61C, graphemeControl},
+	{0x64B, 0x65F, graphemeExtend},
+	{0x670, 0x670, graphemeExtend},
+	{0x6D6, 0x6DC, graphemeExtend},
+	{0x6DD, 0x6DD, graphemePrepend},
+	{0x6DF, 0x6E4, graphemeExtend},
+	{0x6E7, 0x6E8, graphemeExtend},
+	{0x6EA, 0x6ED, graphemeExtend},
+	{0x70F, 0x70F, graphemePrepend},
+	{0x711, 0x711, graphemeExtend},
+	{0x730, 0x74A, graphemeExtend},
+	{0x7A6, 0x7B0, graphemeExtend},
+	{0x7EB, 0x7F3, graphemeExtend},
+	{0x7FD, 0x7FD, graphemeExtend},
+	{0x816, 0x819, graphemeExtend},
+	{0x81B, 0x823, graphemeExtend},
+	{0x825, 0x827, graphemeExtend},
+	{0x829, 0x82D, graphemeExtend},
+	{0x859, 0x85B, graphemeExtend},
+	{0x890, 0x891, graphemePrepend},
+	{0x897, 0x89F, graphemeExtend},
+	{0x8CA, 0x8E1, graphemeExtend},
+	{0x8E2, 0x8E2, graphemePrepend},
+	{0x8E3, 0x902, graphemeExtend},
+	{0x903, 0x903, graphemeSpacingMark},
+	{0x915, 0x939, graphemeInCBConsonant},
+	{0x93A, 0x93A, graphemeExtend},
+	{0x93B, 0x93B, graphemeSpacingMark},
+	{0x93C, 0x93C, graphemeExtend},
+	{0x93E, 0x940, graphemeSpacingMark},
+	{0x941, 0x948, graphemeExtend},
+	{0x949, 0x94C, graphemeSpacingMark},
+	{0x94D, 0x94D, graphemeExtend},
+	{0x94E, 0x94F, graphemeSpacingMark},
+	{0x951, 0x957, graphemeExtend},
+	{0x958, 0x95F, graphemeInCBConsonant},
+	{0x962, 0x963, graphemeExtend},
+	{0x978, 0x97F, graphemeInCBConsonant},
+	{0x981, 0x981, graphemeExtend},
+	{0x982, 0x983, graphemeSpacingMark},
+	{0x995, 0x9A8, graphemeInCBConsonant},
+	{0x9AA, 0x9B0, graphemeInCBConsonant},
+	{0x9B2, 0x9B2, graphemeInCBConsonant},
+	{0x9B6, 0x9B9, graphemeInCBConsonant},
+	{0x9BC, 0x9BC, graphemeExtend},
+	{0x9BE, 0x9BE, graphemeExtend},
+	{0x9BF, 0x9C0, graphemeSpacingMark},
+	{0x9C1, 0x9C4, graphemeExtend},
+	{0x9C7, 0x9C8, graphemeSpacingMark},
+	{0x9CB, 0x9CC, graphemeSpacingMark},
+	{0x9CD, 0x9CD, graphemeExtend},
+	{0x9D7, 0x9D7, graphemeExtend},
+	{0x9DC, 0x9DD, graphemeInCBConsonant},
+	{0x9DF, 0x9DF, graphemeInCBConsonant},
+	{0x9E2, 0x9E3, graphemeExtend},
+	{0x9F0, 0x9F1, graphemeInCBConsonant},
+	{0x9FE, 0x9FE, graphemeExtend},
+	{0xA01, 0xA02, graphemeExtend},
+	{0xA03, 0xA03, graphemeSpacingMark},
+	{0xA3C, 0xA3C, graphemeExtend},
+	{0xA3E, 0xA40, graphemeSpacingMark},
+	{0xA41, 0xA42, graphemeExtend},
+	{0xA47, 0xA48, graphemeExtend},
+	{0xA4B, 0xA4D, graphemeExtend},
+	{0xA51, 0xA51, graphemeExtend},
+	{0xA70, 0xA71, graphemeExtend},
+	{0xA75, 0xA75, graphemeExtend},
+	{0xA81, 0xA82, graphemeExtend},
+	{0xA83, 0xA83, graphemeSpacingMark},
+	{0xA95, 0xAA8, graphemeInCBConsonant},
+	{0xAAA, 0xAB0, graphemeInCBConsonant},
+	{0xAB2, 0xAB3, graphemeInCBConsonant},
+	{0xAB5, 0xAB9, graphemeInCBConsonant},
+	{0xABC, 0xABC, graphemeExtend},
+	{0xABE, 0xAC0, graphemeSpacingMark},
+	{0xAC1, 0xAC5, graphemeExtend},
+	{0xAC7, 0xAC8, graphemeExtend},
+	{0xAC9, 0xAC9, graphemeSpacingMark},
+	{0xACB, 0xACC, graphemeSpacingMark},
+	{0xACD, 0xACD, graphemeExtend},
+	{0xAE2, 0xAE3, graphemeExtend},
+	{0xAF9, 0xAF9, graphemeInCBConsonant},
+	{0xAFA, 0xAFF, graphemeExtend},
+	{0xB01, 0xB01, graphemeExtend},
+	{0xB02, 0xB03, graphemeSpacingMark},
+	{0xB15, 0xB28, graphemeInCBConsonant},
+	{0xB2A, 0xB30, graphemeInCBConsonant},
+	{0xB32, 0xB33, graphemeInCBConsonant},
+	{0xB35, 0xB39, graphemeInCBConsonant},
+	{0xB3C, 0xB3C, graphemeExtend},
+	{0xB3E, 0xB3F, graphemeExtend},
+	{0xB40, 0xB40, graphemeSpacingMark},
+	{0xB41, 0xB44, graphemeExtend},
+	{0xB47, 0xB48, graphemeSpacingMark},
+	{0xB4B, 0xB4C, graphemeSpacingMark},
+	{0xB4D, 0xB4D, graphemeExtend},
+	{0xB55, 0xB57, graphemeExtend},
+	{0xB5C, 0xB5D, graphemeInCBConsonant},
+	{0xB5F, 0xB5F, graphemeInCBConsonant},
+	{0xB62, 0xB63, graphemeExtend},
+	{0xB71, 0xB71, graphemeInCBConsonant},
+	{0xB82, 0xB82, graphemeExtend},
+	{0xBBE, 0xBBE, graphemeExtend},
+	{0xBBF, 0xBBF, graphemeSpacingMark},
+	{0xBC0, 0xBC0, graphemeExtend},
+	{0xBC1, 0xBC2, graphemeSpacingMark},
+	{0xBC6, 0xBC8, graphemeSpacingMark},
+	{0xBCA, 0xBCC, graphemeSpacingMark},
+	{0xBCD, 0xBCD, graphemeExtend},
+	{0xBD7, 0xBD7, graphemeExtend},
+	{0xC00, 0xC00, graphemeExtend},
+	{0xC01, 0xC03, graphemeSpacingMark},
+	{0xC04, 0xC04, graphemeExtend},
+	{0xC15, 0xC28, graphemeInCBConsonant},
+	{0xC2A, 0xC39, graphemeInCBConsonant},
+	{0xC3C, 0xC3C, graphemeExtend},
+	{0xC3E, 0xC40, graphemeExtend},
+	{0xC41, 0xC44, graphemeSpacingMark},
+	{0xC46, 0xC48, graphemeExtend},
+	{0xC4A, 0xC4D, graphemeExtend},
+	{0xC55, 0xC56, graphemeExtend},
+	{0xC58, 0xC5A, graphemeInCBConsonant},
+	{0xC62, 0xC63, graphemeExtend},
+	{0xC81, 0xC81, graphemeExtend},
+	{0xC82, 0xC83, graphemeSpacingMark},
+	{0xCBC, 0xCBC, graphemeExtend},
+	{0xCBE, 0xCBE, graphemeSpacingMark},
+	{0xCBF, 0xCC0, graphemeExtend},
+	{0xCC1, 0xCC1, graphemeSpacingMark},
+	{0xCC2, 0xCC2, graphemeExtend},
+	{0xCC3, 0xCC4, graphemeSpacingMark},
+	{0xCC6, 0xCC8, graphemeExtend},
+	{0xCCA, 0xCCD, graphemeExtend},
+	{0xCD5, 0xCD6, graphemeExtend},
+	{0xCE2, 0xCE3, graphemeExtend},
+	{0xCF3, 0xCF3, graphemeSpacingMark},
+	{0xD00, 0xD01, graphemeExtend},
+	{0xD02, 0xD03, graphemeSpacingMark},
+	{0xD15, 0xD3A, graphemeInCBConsonant},
+	{0xD3B, 0xD3C, graphemeExtend},
+	{0xD3E, 0xD3E, graphemeExtend},
+	{0xD3F, 0xD40, graphemeSpacingMark},
+	{0xD41, 0xD44, graphemeExtend},
+	{0xD46, 0xD48, graphemeSpacingMark},
+	{0xD4A, 0xD4C, graphemeSpacingMark},
+	{0xD4D, 0xD4D, graphemeExtend},
+	{0xD4E, 0xD4E, graphemePrepend},
+	{0xD57, 0xD57, graphemeExtend},
+	{0xD62, 0xD63, graphemeExtend},
+	{0xD81, 0xD81, graphemeExtend},
+	{0xD82, 0xD83, graphemeSpacingMark},
+	{0xDCA, 0xDCA, graphemeExtend},
+	{0xDCF, 0xDCF, graphemeExtend},
+	{0xDD0, 0xDD1, graphemeSpacingMark},
+	{0xDD2, 0xDD4, graphemeExtend},
+	{0xDD6, 0xDD6, graphemeExtend},
+	{0xDD8, 0xDDE, graphemeSpacingMark},
+	{0xDDF, 0xDDF, graphemeExtend},
+	{0xDF2, 0xDF3, graphemeSpacingMark},
+	{0xE31, 0xE31, graphemeExtend},
+	{0xE33, 0xE33, graphemeSpacingMark},
+	{0xE34, 0xE3A, graphemeExtend},
+	{0xE47, 0xE4E, graphemeExtend},
+	{0xEB1, 0xEB1, graphemeExtend},
+	{0xEB3, 0xEB3, graphemeSpacingMark},
+	{0xEB4, 0xEBC, graphemeExtend},
+	{0xEC8, 0xECE, graphemeExtend},
+	{0xF18, 0xF19, graphemeExtend},
+	{0xF35, 0xF35, graphemeExtend},
+	{0xF37, 0xF37, graphemeExtend},
+	{0xF39, 0xF39, graphemeExtend},
+	{0xF3E, 0xF3F, graphemeSpacingMark},
+	{0xF71, 0xF7E, graphemeExtend},
+	{0xF7F, 0xF7F, graphemeSpacingMark},
+	{0xF80, 0xF84, graphemeExtend},
+	{0xF86, 0xF87, graphemeExtend},
+	{0xF8D, 0xF97, graphemeExtend},
+	{0xF99, 0xFBC, graphemeExtend},
+	{0xFC6, 0xFC6, graphemeExtend},
+	{0x102D, 0x1030, graphemeExtend},
+	{0x1031, 0x1031, graphemeSpacingMark},
+	{0x1032, 0x1037, graphemeExtend},
+	{0x1039, 0x103A, graphemeExtend},
+	{0x103B, 0x103C, graphemeSpacingMark},
+	{0x103D, 0x103E, graphemeExtend},
+	{0x1056, 0x1057, graphemeSpacingMark},
+	{0x1058, 0x1059, graphemeExtend},
+	{0x105E, 0x1060, graphemeExtend},
+	{0x1071, 0x1074, graphemeExtend},
+	{0x1082, 0x1082, graphemeExtend},
+	{0x1084, 0x1084, graphemeSpacingMark},
+	{0x1085, 0x1086, graphemeExtend},
+	{0x108D, 0x108D, graphemeExtend},
+	{0x109D, 0x109D, graphemeExtend},
+	{0x1100, 0x115F, graphemeL},
+	{0x1160, 0x11A7, graphemeV},
+	{0x11A8, 0x11FF, graphemeT},
+	{0x135D, 0x135F, graphemeExtend},
+	{0x1712, 0x1715, graphemeExtend},
+	{0x1732, 0x1734, graphemeExtend},
+	{0x1752, 0x1753, graphemeExtend},
+	{0x1772, 0x1773, graphemeExtend},
+	{0x17B4, 0x17B5, graphemeExtend},
+	{0x17B6, 0x17B6, graphemeSpacingMark},
+	{0x17B7, 0x17BD, graphemeExtend},
+	{0x17BE, 0x17C5, graphemeSpacingMark},
+	{0x17C6, 0x17C6, graphemeExtend},
+	{0x17C7, 0x17C8, graphemeSpacingMark},
+	{0x17C9, 0x17D3, graphemeExtend},
+	{0x17DD, 0x17DD, graphemeExtend},
+	{0x180B, 0x180D, graphemeExtend},
+	{0x180E, 0x180E, graphemeControl},
+	{0x180F, 0x180F, graphemeExtend},
+	{0x1885, 0x1886, graphemeExtend},
+	{0x18A9, 0x18A9, graphemeExtend},
+	{0x1920, 0x1922, graphemeExtend},
+	{0x1923, 0x1926, graphemeSpacingMark},
+	{0x1927, 0x1928, graphemeExtend},
+	{0x1929, 0x192B, graphemeSpacingMark},
+	{0x1930, 0x1931, graphemeSpacingMark},
+	{0x1932, 0x1932, graphemeExtend},
+	{0x1933, 0x1938, graphemeSpacingMark},
+	{0x1939, 0x193B, graphemeExtend},
+	{0x1A17, 0x1A18, graphemeExtend},
+	{0x1A19, 0x1A1A, graphemeSpacingMark},
+	{0x1A1B, 0x1A1B, graphemeExtend},
+	{0x1A55, 0x1A55, graphemeSpacingMark},
+	{0x1A56, 0x1A56, graphemeExtend},
+	{0x1A57, 0x1A57, graphemeSpacingMark},
+	{0x1A58, 0x1A5E, graphemeExtend},
+	{0x1A60, 0x1A60, graphemeExtend},
+	{0x1A62, 0x1A62, graphemeExtend},
+	{0x1A65, 0x1A6C, graphemeExtend},
+	{0x1A6D, 0x1A72, graphemeSpacingMark},
+	{0x1A73, 0x1A7C, graphemeExtend},
+	{0x1A7F, 0x1A7F, graphemeExtend},
+	{0x1AB0, 0x1ACE, graphemeExtend},
+	{0x1B00, 0x1B03, graphemeExtend},
+	{0x1B04, 0x1B04, graphemeSpacingMark},
+	{0x1B34, 0x1B3D, graphemeExtend},
+	{0x1B3E, 0x1B41, graphemeSpacingMark},
+	{0x1B42, 0x1B44, graphemeExtend},
+	{0x1B6B, 0x1B73, graphemeExtend},
+	{0x1B80, 0x1B81, graphemeExtend},
+	{0x1B82, 0x1B82, graphemeSpacingMark},
+	{0x1BA1, 0x1BA1, graphemeSpacingMark},
+	{0x1BA2, 0x1BA5, graphemeExtend},
+	{0x1BA6, 0x1BA7, graphemeSpacingMark},
+	{0x1BA8, 0x1BAD, graphemeExtend},
+	{0x1BE6, 0x1BE6, graphemeExtend},
+	{0x1BE7, 0x1BE7, graphemeSpacingMark},
+	{0x1BE8, 0x1BE9, graphemeExtend},
+	{0x1BEA, 0x1BEC, graphemeSpacingMark},
+	{0x1BED, 0x1BED, graphemeExtend},
+	{0x1BEE, 0x1BEE, graphemeSpacingMark},
+	{0x1BEF, 0x1BF3, graphemeExtend},
+	{0x1C24, 0x1C2B, graphemeSpacingMark},
+	{0x1C2C, 0x1C33, graphemeExtend},
+	{0x1C34, 0x1C35, graphemeSpacingMark},
+	{0x1C36, 0x1C37, graphemeExtend},
+	{0x1CD0, 0x1CD2, graphemeExtend},
+	{0x1CD4, 0x1CE0, graphemeExtend},
+	{0x1CE1, 0x1CE1, graphemeSpacingMark},
+	{0x1CE2, 0x1CE8, graphemeExtend},
+	{0x1CED, 0x1CED, graphemeExtend},
+	{0x1CF4, 0x1CF4, graphemeExtend},
+	{0x1CF7, 0x1CF7, graphemeSpacingMark},
+	{0x1CF8, 0x1CF9, graphemeExtend},
+	{0x1DC0, 0x1DFF, graphemeExtend},
+	{0x200B, 0x200B, graphemeControl},
+	{0x200C, 0x200C, graphemeExtend},
+	{0x200D, 0x200D, graphemeZWJ},
+	{0x200E, 0x200F, graphemeControl},
+	{0x2028, 0x202E, graphemeControl},
+	{0x203C, 0x203C, graphemeExtPict},
+	{0x2049, 0x2049, graphemeExtPict},
+	{0x2060, 0x206F, graphemeControl},
+	{0x20D0, 0x20F0, graphemeExtend},
+	{0x2122, 0x2122, graphemeExtPict},
+	{0x2139, 0x2139, graphemeExtPict},
+	{0x2194, 0x2199, graphemeExtPict},
+	{0x21A9, 0x21AA, graphemeExtPict},
+	{0x231A, 0x231B, graphemeExtPict},
+	{0x2328, 0x2328, graphemeExtPict},
+	{0x2388, 0x2388, graphemeExtPict},
+	{0x23CF, 0x23CF, graphemeExtPict},
+	{0x23E9, 0x23F3, graphemeExtPict},
+	{0x23F8, 0x23FA, graphemeExtPict},
+	{0x24C2, 0x24C2, graphemeExtPict},
+	{0x25AA, 0x25AB, graphemeExtPict},
+	{0x25B6, 0x25B6, graphemeExtPict},
+	{0x25C0, 0x25C0, graphemeExtPict},
+	{0x25FB, 0x25FE, graphemeExtPict},
+	{0x2600, 0x2605, graphemeExtPict},
+	{0x2607, 0x2612, graphemeExtPict},
+	{0x2614, 0x2685, graphemeExtPict},
+	{0x2690, 0x2705, graphemeExtPict},
+	{0x2708, 0x2712, graphemeExtPict},
+	{0x2714, 0x2714, graphemeExtPict},
+	{0x2716, 0x2716, graphemeExtPict},
+	{0x271D, 0x271D, graphemeExtPict},
+	{0x2721, 0x2721, graphemeExtPict},
+	{0x2728, 0x2728, graphemeExtPict},
+	{0x2733, 0x2734, graphemeExtPict},
+	{0x2744, 0x2744, graphemeExtPict},
+	{0x2747, 0x2747, graphemeExtPict},
+	{0x274C, 0x274C, graphemeExtPict},
+	{0x274E, 0x274E, graphemeExtPict},
+	{0x2753, 0x2755, graphemeExtPict},
+	{0x2757, 0x2757, graphemeExtPict},
+	{0x2763, 0x2767, graphemeExtPict},
+	{0x2795, 0x2797, graphemeExtPict},
+	{0x27A1, 0x27A1, graphemeExtPict},
+	{0x27B0, 0x27B0, graphemeExtPict},
+	{0x27BF, 0x27BF, graphemeExtPict},
+	{0x2934, 0x2935, graphemeExtPict},
+	{0x2B05, 0x2B07, graphemeExtPict},
+	{0x2B1B, 0x2B1C, graphemeExtPict},
+	{0x2B50, 0x2B50, graphemeExtPict},
+	{0x2B55, 0x2B55, graphemeExtPict},
+	{0x2CEF, 0x2CF1, graphemeExtend},
+	{0x2D7F, 0x2D7F, graphemeExtend},
+	{0x2DE0, 0x2DFF, graphemeExtend},
+	{0x302A, 0x302F, graphemeExtend},
+	{0x3030, 0x3030, graphemeExtPict},
+	{0x303D, 0x303D, graphemeExtPict},
+	{0x3099, 0x309A, graphemeExtend},
+	{0x3297, 0x3297, graphemeExtPict},
+	{0x3299, 0x3299, graphemeExtPict},
+	{0xA66F, 0xA672, graphemeExtend},
+	{0xA674, 0xA67D, graphemeExtend},
+	{0xA69E, 0xA69F, graphemeExtend},
+	{0xA6F0, 0xA6F1, graphemeExtend},
+	{0xA802, 0xA802, graphemeExtend},
+	{0xA806, 0xA806, graphemeExtend},
+	{0xA80B, 0xA80B, graphemeExtend},
+	{0xA823, 0xA824, graphemeSpacingMark},
+	{0xA825, 0xA826, graphemeExtend},
+	{0xA827, 0xA827, graphemeSpacingMark},
+	{0xA82C, 0xA82C, graphemeExtend},
+	{0xA880, 0xA881, graphemeSpacingMark},
+	{0xA8B4, 0xA8C3, graphemeSpacingMark},
+	{0xA8C4, 0xA8C5, graphemeExtend},
+	{0xA8E0, 0xA8F1, graphemeExtend},
+	{0xA8FF, 0xA8FF, graphemeExtend},
+	{0xA926, 0xA92D, graphemeExtend},
+	{0xA947, 0xA951, graphemeExtend},
+	{0xA952, 0xA952, graphemeSpacingMark},
+	{0xA953, 0xA953, graphemeExtend},
+	{0xA960, 0xA97C, graphemeL},
+	{0xA980, 0xA982, graphemeExtend},
+	{0xA983, 0xA983, graphemeSpacingMark},
+	{0xA9B3, 0xA9B3, graphemeExtend},
+	{0xA9B4, 0xA9B5, graphemeSpacingMark},
+	{0xA9B6, 0xA9B9, graphemeExtend},
+	{0xA9BA, 0xA9BB, graphemeSpacingMark},
+	{0xA9BC, 0xA9BD, graphemeExtend},
+	{0xA9BE, 0xA9BF, graphemeSpacingMark},
+	{0xA9C0, 0xA9C0, graphemeExtend},
+	{0xA9E5, 0xA9E5, graphemeExtend},
+	{0xAA29, 0xAA2E, graphemeExtend},
+	{0xAA2F, 0xAA30, graphemeSpacingMark},
+	{0xAA31, 0xAA32, graphemeExtend},
+	{0xAA33, 0xAA34, graphemeSpacingMark},
+	{0xAA35, 0xAA36, graphemeExtend},
+	{0xAA43, 0xAA43, graphemeExtend},
+	{0xAA4C, 0xAA4C, graphemeExtend},
+	{0xAA4D, 0xAA4D, graphemeSpacingMark},
+	{0xAA7C, 0xAA7C, graphemeExtend},
+	{0xAAB0, 0xAAB0, graphemeExtend},
+	{0xAAB2, 0xAAB4, graphemeExtend},
+	{0xAAB7, 0xAAB8, graphemeExtend},
+	{0xAABE, 0xAABF, graphemeExtend},
+	{0xAAC1, 0xAAC1, graphemeExtend},
+	{0xAAEB, 0xAAEB, graphemeSpacingMark},
+	{0xAAEC, 0xAAED, graphemeExtend},
+	{0xAAEE, 0xAAEF, graphemeSpacingMark},
+	{0xAAF5, 0xAAF5, graphemeSpacingMark},
+	{0xAAF6, 0xAAF6, graphemeExtend},
+	{0xABE3, 0xABE4, graphemeSpacingMark},
+	{0xABE5, 0xABE5, graphemeExtend},
+	{0xABE6, 0xABE7, graphemeSpacingMark},
+	{0xABE8, 0xABE8, graphemeExtend},
+	{0xABE9, 0xABEA, graphemeSpacingMark},
+	{0xABEC, 0xABEC, graphemeSpacingMark},
+	{0xABED, 0xABED, graphemeExtend},
+	{0xAC00, 0xAC00, graphemeLV},
+	{0xAC01, 0xAC1B, graphemeLVT},
+	{0xAC1C, 0xAC1C, graphemeLV},
+	{0xAC1D, 0xAC37, graphemeLVT},
+	{0xAC38, 0xAC38, graphemeLV},
+	{0xAC39, 0xAC53, graphemeLVT},
+	{0xAC54, 0xAC54, graphemeLV},
+	{0xAC55, 0xAC6F, graphemeLVT},
+	{0xAC70, 0xAC70, graphemeLV},
+	{0xAC71, 0xAC8B, graphemeLVT},
+	{0xAC8C, 0xAC8C, graphemeLV},
+	{0xAC8D, 0xACA7, graphemeLVT},
+	{0xACA8, 0xACA8, graphemeLV},
+	{0xACA9, 0xACC3, graphemeLVT},
+	{0xACC4, 0xACC4, graphemeLV},
+	{0xACC5, 0xACDF, graphemeLVT},
+	{0xACE0, 0xACE0, graphemeLV},
+	{0xACE1, 0xACFB, graphemeLVT},
+	{0xACFC, 0xACFC, graphemeLV},
+	{0xACFD, 0xAD17, graphemeLVT},
+	{0xAD18, 0xAD18, graphemeLV},
+	{0xAD19, 0xAD33, graphemeLVT},
+	{0xAD34, 0xAD34, graphemeLV},
+	{0xAD35, 0xAD4F, graphemeLVT},
+	{0xAD50, 0xAD50, graphemeLV},
+	{0xAD51, 0xAD6B, graphemeLVT},
+	{0xAD6C, 0xAD6C, graphemeLV},
+	{0xAD6D, 0xAD87, graphemeLVT},
+	{0xAD88, 0xAD88, graphemeLV},
+	{0xAD89, 0xADA3, graphemeLVT},
+	{0xADA4, 0xADA4, graphemeLV},
+	{0xADA5, 0xADBF, graphemeLVT},
+	{0xADC0, 0xADC0, graphemeLV},
+	{0xADC1, 0xADDB, graphemeLVT},
+	{0xADDC, 0xADDC, graphemeLV},
+	{0xADDD, 0xADF7, graphemeLVT},
+	{0xADF8, 0xADF8, graphemeLV},
+	{0xADF9, 0xAE13, graphemeLVT},
+	{0xAE14, 0xAE14, graphemeLV},
+	{0xAE15, 0xAE2F, graphemeLVT},
+	{0xAE30, 0xAE30, graphemeLV},
+	{0xAE31, 0xAE4B, graphemeLVT},
+	{0xAE4C, 0xAE4C, graphemeLV},
+	{0xAE4D, 0xAE67, graphemeLVT},
+	{0xAE68, 0xAE68, graphemeLV},
+	{0xAE69, 0xAE83, graphemeLVT},
+	{0xAE84, 0xAE84, graphemeLV},
+	{0xAE85, 0xAE9F, graphemeLVT},
+	{0xAEA0, 0xAEA0, graphemeLV},
+	{0xAEA1, 0xAEBB, graphemeLVT},
+	{0xAEBC, 0xAEBC, graphemeLV},
+	{0xAEBD, 0xAED7, graphemeLVT},
+	{0xAED8, 0xAED8, graphemeLV},
+	{0xAED9, 0xAEF3, graphemeLVT},
+	{0xAEF4, 0xAEF4, graphemeLV},
+	{0xAEF5, 0xAF0F, graphemeLVT},
+	{0xAF10, 0xAF10, graphemeLV},
+	{0xAF11, 0xAF2B, graphemeLVT},
+	{0xAF2C, 0xAF2C, graphemeLV},
+	{0xAF2D, 0xAF47, graphemeLVT},
+	{0xAF48, 0xAF48, graphemeLV},
+	{0xAF49, 0xAF63, graphemeLVT},
+	{0xAF64, 0xAF64, graphemeLV},
+	{0xAF65, 0xAF7F, graphemeLVT},
+	{0xAF80, 0xAF80, graphemeLV},
+	{0xAF81, 0xAF9B, graphemeLVT},
+	{0xAF9C, 0xAF9C, graphemeLV},
+	{0xAF9D, 0xAFB7, graphemeLVT},
+	{0xAFB8, 0xAFB8, graphemeLV},
+	{0xAFB9, 0xAFD3, graphemeLVT},
+	{0xAFD4, 0xAFD4, graphemeLV},
+	{0xAFD5, 0xAFEF, graphemeLVT},
+	{0xAFF0, 0xAFF0, graphemeLV},
+	{0xAFF1, 0xB00B, graphemeLVT},
+	{0xB00C, 0xB00C, graphemeLV},
+	{0xB00D, 0xB027, graphemeLVT},
+	{0xB028, 0xB028, graphemeLV},
+	{0xB029, 0xB043, graphemeLVT},
+	{0xB044, 0xB044, graphemeLV},
+	{0xB045, 0xB05F, graphemeLVT},
+	{0xB060, 0xB060, graphemeLV},
+	{0xB061, 0xB07B, graphemeLVT},
+	{0xB07C, 0xB07C, graphemeLV},
+	{0xB07D, 0xB097, graphemeLVT},
+	{0xB098, 0xB098, graphemeLV},
+	{0xB099, 0xB0B3, graphemeLVT},
+	{0xB0B4, 0xB0B4, graphemeLV},
+	{0xB0B5, 0xB0CF, graphemeLVT},
+	{0xB0D0, 0xB0D0, graphemeLV},
+	{0xB0D1, 0xB0EB, graphemeLVT},
+	{0xB0EC, 0xB0EC, graphemeLV},
+	{0xB0ED, 0xB107, graphemeLVT},
+	{0xB108, 0xB108, graphemeLV},
+	{0xB109, 0xB123, graphemeLVT},
+	{0xB124, 0xB124, graphemeLV},
+	{0xB125, 0xB13F, graphemeLVT},
+	{0xB140, 0xB140, graphemeLV},
+	{0xB141, 0xB15B, graphemeLVT},
+	{0xB15C, 0xB15C, graphemeLV},
+	{0xB15D, 0xB177, graphemeLVT},
+	{0xB178, 0xB178, graphemeLV},
+	{0xB179, 0xB193, graphemeLVT},
+	{0xB194, 0xB194, graphemeLV},
+	{0xB195, 0xB1AF, graphemeLVT},
+	{0xB1B0, 0xB1B0, graphemeLV},
+	{0xB1B1, 0xB1CB, graphemeLVT},
+	{0xB1CC, 0xB1CC, graphemeLV},
+	{0xB1CD, 0xB1E7, graphemeLVT},
+	{0xB1E8, 0xB1E8, graphemeLV},
+	{0xB1E9, 0xB203, graphemeLVT},
+	{0xB204, 0xB204, graphemeLV},
+	{0xB205, 0xB21F, graphemeLVT},
+	{0xB220, 0xB220, graphemeLV},
+	{0xB221, 0xB23B, graphemeLVT},
+	{0xB23C, 0xB23C, graphemeLV},
+	{0xB23D, 0xB257, graphemeLVT},
+	{0xB258, 0xB258, graphemeLV},
+	{0xB259, 0xB273, graphemeLVT},
+	{0xB274, 0xB274, graphemeLV},
+	{0xB275, 0xB28F, graphemeLVT},
+	{0xB290, 0xB290, graphemeLV},
+	{0xB291, 0xB2AB, graphemeLVT},
+	{0xB2AC, 0xB2AC, graphemeLV},
+	{0xB2AD, 0xB2C7, graphemeLVT},
+	{0xB2C8, 0xB2C8, graphemeLV},
+	{0xB2C9, 0xB2E3, graphemeLVT},
+	{0xB2E4, 0xB2E4, graphemeLV},
+	{0xB2E5, 0xB2FF, graphemeLVT},
+	{0xB300, 0xB300, graphemeLV},
+	{0xB301, 0xB31B, graphemeLVT},
+	{0xB31C, 0xB31C, graphemeLV},
+	{0xB31D, 0xB337, graphemeLVT},
+	{0xB338, 0xB338, graphemeLV},
+	{0xB339, 0xB353, graphemeLVT},
+	{0xB354, 0xB354, graphemeLV},
+	{0xB355, 0xB36F, graphemeLVT},
+	{0xB370, 0xB370, graphemeLV},
+	{0xB371, 0xB38B, graphemeLVT},
+	{0xB38C, 0xB38C, graphemeLV},
+	{0xB38D, 0xB3A7, graphemeLVT},
+	{0xB3A8, 0xB3A8, graphemeLV},
+	{0xB3A9, 0xB3C3, graphemeLVT},
+	{0xB3C4, 0xB3C4, graphemeLV},
+	{0xB3C5, 0xB3DF, graphemeLVT},
+	{0xB3E0, 0xB3E0, graphemeLV},
+	{0xB3E1, 0xB3FB, graphemeLVT},
+	{0xB3FC, 0xB3FC, graphemeLV},
+	{0xB3FD, 0xB417, graphemeLVT},
+	{0xB418, 0xB418, graphemeLV},
+	{0xB419, 0xB433, graphemeLVT},
+	{0xB434, 0xB434, graphemeLV},
+	{0xB435, 0xB44F, graphemeLVT},
+	{0xB450, 0xB450, graphemeLV},
+	{0xB451, 0xB46B, graphemeLVT},
+	{0xB46C, 0xB46C, graphemeLV},
+	{0xB46D, 0xB487, graphemeLVT},
+	{0xB488, 0xB488, graphemeLV},
+	{0xB489, 0xB4A3, graphemeLVT},
+	{0xB4A4, 0xB4A4, graphemeLV},
+	{0xB4A5, 0xB4BF, graphemeLVT},
+	{0xB4C0, 0xB4C0, graphemeLV},
+	{0xB4C1, 0xB4DB, graphemeLVT},
+	{0xB4DC, 0xB4DC, graphemeLV},
+	{0xB4DD, 0xB4F7, graphemeLVT},
+	{0xB4F8, 0xB4F8, graphemeLV},
+	{0xB4F9, 0xB513, graphemeLVT},
+	{0xB514, 0xB514, graphemeLV},
+	{0xB515, 0xB52F, graphemeLVT},
+	{0xB530, 0xB530, graphemeLV},
+	{0xB531, 0xB54B, graphemeLVT},
+	{0xB54C, 0xB54C, graphemeLV},
+	{0xB54D, 0xB567, graphemeLVT},
+	{0xB568, 0xB568, graphemeLV},
+	{0xB569, 0xB583, graphemeLVT},
+	{0xB584, 0xB584, graphemeLV},
+	{0xB585, 0xB59F, graphemeLVT},
+	{0xB5A0, 0xB5A0, graphemeLV},
+	{0xB5A1, 0xB5BB, graphemeLVT},
+	{0xB5BC, 0xB5BC, graphemeLV},
+	{0xB5BD, 0xB5D7, graphemeLVT},
+	{0xB5D8, 0xB5D8, graphemeLV},
+	{0xB5D9, 0xB5F3, graphemeLVT},
+	{0xB5F4, 0xB5F4, graphemeLV},
+	{0xB5F5, 0xB60F, graphemeLVT},
+	{0xB610, 0xB610, graphemeLV},
+	{0xB611, 0xB62B, graphemeLVT},
+	{0xB62C, 0xB62C, graphemeLV},
+	{0xB62D, 0xB647, graphemeLVT},
+	{0xB648, 0xB648, graphemeLV},
+	{0xB649, 0xB663, graphemeLVT},
+	{0xB664, 0xB664, graphemeLV},
+	{0xB665, 0xB67F, graphemeLVT},
+	{0xB680, 0xB680, graphemeLV},
+	{0xB681, 0xB69B, graphemeLVT},
+	{0xB69C, 0xB69C, graphemeLV},
+	{0xB69D, 0xB6B7, graphemeLVT},
+	{0xB6B8, 0xB6B8, graphemeLV},
+	{0xB6B9, 0xB6D3, graphemeLVT},
+	{0xB6D4, 0xB6D4, graphemeLV},
+	{0xB6D5, 0xB6EF, graphemeLVT},
+	{0xB6F0, 0xB6F0, graphemeLV},
+	{0xB6F1, 0xB70B, graphemeLVT},
+	{0xB70C, 0xB70C, graphemeLV},
+	{0xB70D, 0xB727, graphemeLVT},
+	{0xB728, 0xB728, graphemeLV},
+	{0xB729, 0xB743, graphemeLVT},
+	{0xB744, 0xB744, graphemeLV},
+	{0xB745, 0xB75F, graphemeLVT},
+	{0xB760, 0xB760, graphemeLV},
+	{0xB761, 0xB77B, graphemeLVT},
+	{0xB77C, 0xB77C, graphemeLV},
+	{0xB77D, 0xB797, graphemeLVT},
+	{0xB798, 0xB798, graphemeLV},
+	{0xB799, 0xB7B3, graphemeLVT},
+	{0xB7B4, 0xB7B4, graphemeLV},
+	{0xB7B5, 0xB7CF, graphemeLVT},
+	{0xB7D0, 0xB7D0, graphemeLV},
+	{0xB7D1, 0xB7EB, graphemeLVT},
+	{0xB7EC, 0xB7EC, graphemeLV},
+	{0xB7ED, 0xB807, graphemeLVT},
+	{0xB808, 0xB808, graphemeLV},
+	{0xB809, 0xB823, graphemeLVT},
+	{0xB824, 0xB824, graphemeLV},
+	{0xB825, 0xB83F, graphemeLVT},
+	{0xB840, 0xB840, graphemeLV},
+	{0xB841, 0xB85B, graphemeLVT},
+	{0xB85C, 0xB85C, graphemeLV},
+	{0xB85D, 0xB877, graphemeLVT},
+	{0xB878, 0xB878, graphemeLV},
+	{0xB879, 0xB893, graphemeLVT},
+	{0xB894, 0xB894, graphemeLV},
+	{0xB895, 0xB8AF, graphemeLVT},
+	{0xB8B0, 0xB8B0, graphemeLV},
+	{0xB8B1, 0xB8CB, graphemeLVT},
+	{0xB8CC, 0xB8CC, graphemeLV},
+	{0xB8CD, 0xB8E7, graphemeLVT},
+	{0xB8E8, 0xB8E8, graphemeLV},
+	{0xB8E9, 0xB903, graphemeLVT},
+	{0xB904, 0xB904, graphemeLV},
+	{0xB905, 0xB91F, graphemeLVT},
+	{0xB920, 0xB920, graphemeLV},
+	{0xB921, 0xB93B, graphemeLVT},
+	{0xB93C, 0xB93C, graphemeLV},
+	{0xB93D, 0xB957, graphemeLVT},
+	{0xB958, 0xB958, graphemeLV},
+	{0xB959, 0xB973, graphemeLVT},
+	{0xB974, 0xB974, graphemeLV},
+	{0xB975, 0xB98F, graphemeLVT},
+	{0xB990, 0xB990, graphemeLV},
+	{0xB991, 0xB9AB, graphemeLVT},
+	{0xB9AC, 0xB9AC, graphemeLV},
+	{0xB9AD, 0xB9C7, graphemeLVT},
+	{0xB9C8, 0xB9C8, graphemeLV},
+	{0xB9C9, 0xB9E3, graphemeLVT},
+	{0xB9E4, 0xB9E4, graphemeLV},
+	{0xB9E5, 0xB9FF, graphemeLVT},
+	{0xBA00, 0xBA00, graphemeLV},
+	{0xBA01, 0xBA1B, graphemeLVT},
+	{0xBA1C, 0xBA1C, graphemeLV},
+	{0xBA1D, 0xBA37, graphemeLVT},
+	{0xBA38, 0xBA38, graphemeLV},
+	{0xBA39, 0xBA53, graphemeLVT},
+	{0xBA54, 0xBA54, graphemeLV},
+	{0xBA55, 0xBA6F, graphemeLVT},
+	{0xBA70, 0xBA70, graphemeLV},
+	{0xBA71, 0xBA8B, graphemeLVT},
+	{0xBA8C, 0xBA8C, graphemeLV},
+	{0xBA8D, 0xBAA7, graphemeLVT},
+	{0xBAA8, 0xBAA8, graphemeLV},
+	{0xBAA9, 0xBAC3, graphemeLVT},
+	{0xBAC4, 0xBAC4, graphemeLV},
+	{0xBAC5, 0xBADF, graphemeLVT},
+	{0xBAE0, 0xBAE0, graphemeLV},
+	{0xBAE1, 0xBAFB, graphemeLVT},
+	{0xBAFC, 0xBAFC, graphemeLV},
+	{0xBAFD, 0xBB17, graphemeLVT},
+	{0xBB18, 0xBB18, graphemeLV},
+	{0xBB19, 0xBB33, graphemeLVT},
+	{0xBB34, 0xBB34, graphemeLV},
+	{0xBB35, 0xBB4F, graphemeLVT},
+	{0xBB50, 0xBB50, graphemeLV},
+	{0xBB51, 0xBB6B, graphemeLVT},
+	{0xBB6C, 0xBB6C, graphemeLV},
+	{0xBB6D, 0xBB87, graphemeLVT},
+	{0xBB88, 0xBB88, graphemeLV},
+	{0xBB89, 0xBBA3, graphemeLVT},
+	{0xBBA4, 0xBBA4, graphemeLV},
+	{0xBBA5, 0xBBBF, graphemeLVT},
+	{0xBBC0, 0xBBC0, graphemeLV},
+	{0xBBC1, 0xBBDB, graphemeLVT},
+	{0xBBDC, 0xBBDC, graphemeLV},
+	{0xBBDD, 0xBBF7, graphemeLVT},
+	{0xBBF8, 0xBBF8, graphemeLV},
+	{0xBBF9, 0xBC13, graphemeLVT},
+	{0xBC14, 0xBC14, graphemeLV},
+	{0xBC15, 0xBC2F, graphemeLVT},
+	{0xBC30, 0xBC30, graphemeLV},
+	{0xBC31, 0xBC4B, graphemeLVT},
+	{0xBC4C, 0xBC4C, graphemeLV},
+	{0xBC4D, 0xBC67, graphemeLVT},
+	{0xBC68, 0xBC68, graphemeLV},
+	{0xBC69, 0xBC83, graphemeLVT},
+	{0xBC84, 0xBC84, graphemeLV},
+	{0xBC85, 0xBC9F, graphemeLVT},
+	{0xBCA0, 0xBCA0, graphemeLV},
+	{0xBCA1, 0xBCBB, graphemeLVT},
+	{0xBCBC, 0xBCBC, graphemeLV},
+	{0xBCBD, 0xBCD7, graphemeLVT},
+	{0xBCD8, 0xBCD8, graphemeLV},
+	{0xBCD9, 0xBCF3, graphemeLVT},
+	{0xBCF4, 0xBCF4, graphemeLV},
+	{0xBCF5, 0xBD0F, graphemeLVT},
+	{0xBD10, 0xBD10, graphemeLV},
+	{0xBD11, 0xBD2B, graphemeLVT},
+	{0xBD2C, 0xBD2C, graphemeLV},
+	{0xBD2D, 0xBD47, graphemeLVT},
+	{0xBD48, 0xBD48, graphemeLV},
+	{0xBD49, 0xBD63, graphemeLVT},
+	{0xBD64, 0xBD64, graphemeLV},
+	{0xBD65, 0xBD7F, graphemeLVT},
+	{0xBD80, 0xBD80, graphemeLV},
+	{0xBD81, 0xBD9B, graphemeLVT},
+	{0xBD9C, 0xBD9C, graphemeLV},
+	{0xBD9D, 0xBDB7, graphemeLVT},
+	{0xBDB8, 0xBDB8, graphemeLV},
+	{0xBDB9, 0xBDD3, graphemeLVT},
+	{0xBDD4, 0xBDD4, graphemeLV},
+	{0xBDD5, 0xBDEF, graphemeLVT},
+	{0xBDF0, 0xBDF0, graphemeLV},
+	{0xBDF1, 0xBE0B, graphemeLVT},
+	{0xBE0C, 0xBE0C, graphemeLV},
+	{0xBE0D, 0xBE27, graphemeLVT},
+	{0xBE28, 0xBE28, graphemeLV},
+	{0xBE29, 0xBE43, graphemeLVT},
+	{0xBE44, 0xBE44, graphemeLV},
+	{0xBE45, 0xBE5F, graphemeLVT},
+	{0xBE60, 0xBE60, graphemeLV},
+	{0xBE61, 0xBE7B, graphemeLVT},
+	{0xBE7C, 0xBE7C, graphemeLV},
+	{0xBE7D, 0xBE97, graphemeLVT},
+	{0xBE98, 0xBE98, graphemeLV},
+	{0xBE99, 0xBEB3, graphemeLVT},
+	{0xBEB4, 0xBEB4, graphemeLV},
+	{0xBEB5, 0xBECF, graphemeLVT},
+	{0xBED0, 0xBED0, graphemeLV},
+	{0xBED1, 0xBEEB, graphemeLVT},
+	{0xBEEC, 0xBEEC, graphemeLV},
+	{0xBEED, 0xBF07, graphemeLVT},
+	{0xBF08, 0xBF08, graphemeLV},
+	{0xBF09, 0xBF23, graphemeLVT},
+	{0xBF24, 0xBF24, graphemeLV},
+	{0xBF25, 0xBF3F, graphemeLVT},
+	{0xBF40, 0xBF40, graphemeLV},
+	{0xBF41, 0xBF5B, graphemeLVT},
+	{0xBF5C, 0xBF5C, graphemeLV},
+	{0xBF5D, 0xBF77, graphemeLVT},
+	{0xBF78, 0xBF78, graphemeLV},
+	{0xBF79, 0xBF93, graphemeLVT},
+	{0xBF94, 0xBF94, graphemeLV},
+	{0xBF95, 0xBFAF, graphemeLVT},
+	{0xBFB0, 0xBFB0, graphemeLV},
+	{0xBFB1, 0xBFCB, graphemeLVT},
+	{0xBFCC, 0xBFCC, graphemeLV},
+	{0xBFCD, 0xBFE7, graphemeLVT},
+	{0xBFE8, 0xBFE8, graphemeLV},
+	{0xBFE9, 0xC003, graphemeLVT},
+	{0xC004, 0xC004, graphemeLV},
+	{0xC005, 0xC01F, graphemeLVT},
+	{0xC020, 0xC020, graphemeLV},
+	{0xC021, 0xC03B, graphemeLVT},
+	{0xC03C, 0xC03C, graphemeLV},
+	{0xC03D, 0xC057, graphemeLVT},
+	{0xC058, 0xC058, graphemeLV},
+	{0xC059, 0xC073, graphemeLVT},
+	{0xC074, 0xC074, graphemeLV},
+	{0xC075, 0xC08F, graphemeLVT},
+	{0xC090, 0xC090, graphemeLV},
+	{0xC091, 0xC0AB, graphemeLVT},
+	{0xC0AC, 0xC0AC, graphemeLV},
+	{0xC0AD, 0xC0C7, graphemeLVT},
+	{0xC0C8, 0xC0C8, graphemeLV},
+	{0xC0C9, 0xC0E3, graphemeLVT},
+	{0xC0E4, 0xC0E4, graphemeLV},
+	{0xC0E5, 0xC0FF, graphemeLVT},
+	{0xC100, 0xC100, graphemeLV},
+	{0xC101, 0xC11B, graphemeLVT},
+	{0xC11C, 0xC11C, graphemeLV},
+	{0xC11D, 0xC137, graphemeLVT},
+	{0xC138, 0xC138, graphemeLV},
+	{0xC139, 0xC153, graphemeLVT},
+	{0xC154, 0xC154, graphemeLV},
+	{0xC155, 0xC16F, graphemeLVT},
+	{0xC170, 0xC170, graphemeLV},
+	{0xC171, 0xC18B, graphemeLVT},
+	{0xC18C, 0xC18C, graphemeLV},
+	{0xC18D, 0xC1A7, graphemeLVT},
+	{0xC1A8, 0xC1A8, graphemeLV},
+	{0xC1A9, 0xC1C3, graphemeLVT},
+	{0xC1C4, 0xC1C4, graphemeLV},
+	{0xC1C5, 0xC1DF, graphemeLVT},
+	{0xC1E0, 0xC1E0, graphemeLV},
+	{0xC1E1, 0xC1FB, graphemeLVT},
+	{0xC1FC, 0xC1FC, graphemeLV},
+	{0xC1FD, 0xC217, graphemeLVT},
+	{0xC218, 0xC218, graphemeLV},
+	{0xC219, 0xC233, graphemeLVT},
+	{0xC234, 0xC234, graphemeLV},
+	{0xC235, 0xC24F, graphemeLVT},
+	{0xC250, 0xC250, graphemeLV},
+	{0xC251, 0xC26B, graphemeLVT},
+	{0xC26C, 0xC26C, graphemeLV},
+	{0xC26D, 0xC287, graphemeLVT},
+	{0xC288, 0xC288, graphemeLV},
+	{0xC289, 0xC2A3, graphemeLVT},
+	{0xC2A4, 0xC2A4, graphemeLV},
+	{0xC2A5, 0xC2BF, graphemeLVT},
+	{0xC2C0, 0xC2C0, graphemeLV},
+	{0xC2C1, 0xC2DB, graphemeLVT},
+	{0xC2DC, 0xC2DC, graphemeLV},
+	{0xC2DD, 0xC2F7, graphemeLVT},
+	{0xC2F8, 0xC2F8, graphemeLV},
+	{0xC2F9, 0xC313, graphemeLVT},
+	{0xC314, 0xC314, graphemeLV},
+	{0xC315, 0xC32F, graphemeLVT},
+	{0xC330, 0xC330, graphemeLV},
+	{0xC331, 0xC34B, graphemeLVT},
+	{0xC34C, 0xC34C, graphemeLV},
+	{0xC34D, 0xC367, graphemeLVT},
+	{0xC368, 0xC368, graphemeLV},
+	{0xC369, 0xC383, graphemeLVT},
+	{0xC384, 0xC384, graphemeLV},
+	{0xC385, 0xC39F, graphemeLVT},
+	{0xC3A0, 0xC3A0, graphemeLV},
+	{0xC3A1, 0xC3BB, graphemeLVT},
+	{0xC3BC, 0xC3BC, graphemeLV},
+	{0xC3BD, 0xC3D7, graphemeLVT},
+	{0xC3D8, 0xC3D8, graphemeLV},
+	{0xC3D9, 0xC3F3, graphemeLVT},
+	{0xC3F4, 0xC3F4, graphemeLV},
+	{0xC3F5, 0xC40F, graphemeLVT},
+	{0xC410, 0xC410, graphemeLV},
+	{0xC411, 0xC42B, graphemeLVT},
+	{0xC42C, 0xC42C, graphemeLV},
+	{0xC42D, 0xC447, graphemeLVT},
+	{0xC448, 0xC448, graphemeLV},
+	{0xC449, 0xC463, graphemeLVT},
+	{0xC464, 0xC464, graphemeLV},
+	{0xC465, 0xC47F, graphemeLVT},
+	{0xC480, 0xC480, graphemeLV},
+	{0xC481, 0xC49B, graphemeLVT},
+	{0xC49C, 0xC49C, graphemeLV},
+	{0xC49D, 0xC4B7, graphemeLVT},
+	{0xC4B8, 0xC4B8, graphemeLV},
+	{0xC4B9, 0xC4D3, graphemeLVT},
+	{0xC4D4, 0xC4D4, graphemeLV},
+	{0xC4D5, 0xC4EF, graphemeLVT},
+	{0xC4F0, 0xC4F0, graphemeLV},
+	{0xC4F1, 0xC50B, graphemeLVT},
+	{0xC50C, 0xC50C, graphemeLV},
+	{0xC50D, 0xC527, graphemeLVT},
+	{0xC528, 0xC528, graphemeLV},
+	{0xC529, 0xC543, graphemeLVT},
+	{0xC544, 0xC544, graphemeLV},
+	{0xC545, 0xC55F, graphemeLVT},
+	{0xC560, 0xC560, graphemeLV},
+	{0xC561, 0xC57B, graphemeLVT},
+	{0xC57C, 0xC57C, graphemeLV},
+	{0xC57D, 0xC597, graphemeLVT},
+	{0xC598, 0xC598, graphemeLV},
+	{0xC599, 0xC5B3, graphemeLVT},
+	{0xC5B4, 0xC5B4, graphemeLV},
+	{0xC5B5, 0xC5CF, graphemeLVT},
+	{0xC5D0, 0xC5D0, graphemeLV},
+	{0xC5D1, 0xC5EB, graphemeLVT},
+	{0xC5EC, 0xC5EC, graphemeLV},
+	{0xC5ED, 0xC607, graphemeLVT},
+	{0xC608, 0xC608, graphemeLV},
+	{0xC609, 0xC623, graphemeLVT},
+	{0xC624, 0xC624, graphemeLV},
+	{0xC625, 0xC63F, graphemeLVT},
+	{0xC640, 0xC640, graphemeLV},
+	{0xC641, 0xC65B, graphemeLVT},
+	{0xC65C, 0xC65C, graphemeLV},
+	{0xC65D, 0xC677, graphemeLVT},
+	{0xC678, 0xC678, graphemeLV},
+	{0xC679, 0xC693, graphemeLVT},
+	{0xC694, 0xC694, graphemeLV},
+	{0xC695, 0xC6AF, graphemeLVT},
+	{0xC6B0, 0xC6B0, graphemeLV},
+	{0xC6B1, 0xC6CB, graphemeLVT},
+	{0xC6CC, 0xC6CC, graphemeLV},
+	{0xC6CD, 0xC6E7, graphemeLVT},
+	{0xC6E8, 0xC6E8, graphemeLV},
+	{0xC6E9, 0xC703, graphemeLVT},
+	{0xC704, 0xC704, graphemeLV},
+	{0xC705, 0xC71F, graphemeLVT},
+	{0xC720, 0xC720, graphemeLV},
+	{0xC721, 0xC73B, graphemeLVT},
+	{0xC73C, 0xC73C, graphemeLV},
+	{0xC73D, 0xC757, graphemeLVT},
+	{0xC758, 0xC758, graphemeLV},
+	{0xC759, 0xC773, graphemeLVT},
+	{0xC774, 0xC774, graphemeLV},
+	{0xC775, 0xC78F, graphemeLVT},
+	{0xC790, 0xC790, graphemeLV},
+	{0xC791, 0xC7AB, graphemeLVT},
+	{0xC7AC, 0xC7AC, graphemeLV},
+	{0xC7AD, 0xC7C7, graphemeLVT},
+	{0xC7C8, 0xC7C8, graphemeLV},
+	{0xC7C9, 0xC7E3, graphemeLVT},
+	{0xC7E4, 0xC7E4, graphemeLV},
+	{0xC7E5, 0xC7FF, graphemeLVT},
+	{0xC800, 0xC800, graphemeLV},
+	{0xC801, 0xC81B, graphemeLVT},
+	{0xC81C, 0xC81C, graphemeLV},
+	{0xC81D, 0xC837, graphemeLVT},
+	{0xC838, 0xC838, graphemeLV},
+	{0xC839, 0xC853, graphemeLVT},
+	{0xC854, 0xC854, graphemeLV},
+	{0xC855, 0xC86F, graphemeLVT},
+	{0xC870, 0xC870, graphemeLV},
+	{0xC871, 0xC88B, graphemeLVT},
+	{0xC88C, 0xC88C, graphemeLV},
+	{0xC88D, 0xC8A7, graphemeLVT},
+	{0xC8A8, 0xC8A8, graphemeLV},
+	{0xC8A9, 0xC8C3, graphemeLVT},
+	{0xC8C4, 0xC8C4, graphemeLV},
+	{0xC8C5, 0xC8DF, graphemeLVT},
+	{0xC8E0, 0xC8E0, graphemeLV},
+	{0xC8E1, 0xC8FB, graphemeLVT},
+	{0xC8FC, 0xC8FC, graphemeLV},
+	{0xC8FD, 0xC917, graphemeLVT},
+	{0xC918, 0xC918, graphemeLV},
+	{0xC919, 0xC933, graphemeLVT},
+	{0xC934, 0xC934, graphemeLV},
+	{0xC935, 0xC94F, graphemeLVT},
+	{0xC950, 0xC950, graphemeLV},
+	{0xC951, 0xC96B, graphemeLVT},
+	{0xC96C, 0xC96C, graphemeLV},
+	{0xC96D, 0xC987, graphemeLVT},
+	{0xC988, 0xC988, graphemeLV},
+	{0xC989, 0xC9A3, graphemeLVT},
+	{0xC9A4, 0xC9A4, graphemeLV},
+	{0xC9A5, 0xC9BF, graphemeLVT},
+	{0xC9C0, 0xC9C0, graphemeLV},
+	{0xC9C1, 0xC9DB, graphemeLVT},
+	{0xC9DC, 0xC9DC, graphemeLV},
+	{0xC9DD, 0xC9F7, graphemeLVT},
+	{0xC9F8, 0xC9F8, graphemeLV},
+	{0xC9F9, 0xCA13, graphemeLVT},
+	{0xCA14, 0xCA14, graphemeLV},
+	{0xCA15, 0xCA2F, graphemeLVT},
+	{0xCA30, 0xCA30, graphemeLV},
+	{0xCA31, 0xCA4B, graphemeLVT},
+	{0xCA4C, 0xCA4C, graphemeLV},
+	{0xCA4D, 0xCA67, graphemeLVT},
+	{0xCA68, 0xCA68, graphemeLV},
+	{0xCA69, 0xCA83, graphemeLVT},
+	{0xCA84, 0xCA84, graphemeLV},
+	{0xCA85, 0xCA9F, graphemeLVT},
+	{0xCAA0, 0xCAA0, graphemeLV},
+	{0xCAA1, 0xCABB, graphemeLVT},
+	{0xCABC, 0xCABC, graphemeLV},
+	{0xCABD, 0xCAD7, graphemeLVT},
+	{0xCAD8, 0xCAD8, graphemeLV},
+	{0xCAD9, 0xCAF3, graphemeLVT},
+	{0xCAF4, 0xCAF4, graphemeLV},
+	{0xCAF5, 0xCB0F, graphemeLVT},
+	{0xCB10, 0xCB10, graphemeLV},
+	{0xCB11, 0xCB2B, graphemeLVT},
+	{0xCB2C, 0xCB2C, graphemeLV},
+	{0xCB2D, 0xCB47, graphemeLVT},
+	{0xCB48, 0xCB48, graphemeLV},
+	{0xCB49, 0xCB63, graphemeLVT},
+	{0xCB64, 0xCB64, graphemeLV},
+	{0xCB65, 0xCB7F, graphemeLVT},
+	{0xCB80, 0xCB80, graphemeLV},
+	{0xCB81, 0xCB9B, graphemeLVT},
+	{0xCB9C, 0xCB9C, graphemeLV},
+	{0xCB9D, 0xCBB7, graphemeLVT},
+	{0xCBB8, 0xCBB8, graphemeLV},
+	{0xCBB9, 0xCBD3, graphemeLVT},
+	{0xCBD4, 0xCBD4, graphemeLV},
+	{0xCBD5, 0xCBEF, graphemeLVT},
+	{0xCBF0, 0xCBF0, graphemeLV},
+	{0xCBF1, 0xCC0B, graphemeLVT},
+	{0xCC0C, 0xCC0C, graphemeLV},
+	{0xCC0D, 0xCC27, graphemeLVT},
+	{0xCC28, 0xCC28, graphemeLV},
+	{0xCC29, 0xCC43, graphemeLVT},
+	{0xCC44, 0xCC44, graphemeLV},
+	{0xCC45, 0xCC5F, graphemeLVT},
+	{0xCC60, 0xCC60, graphemeLV},
+	{0xCC61, 0xCC7B, graphemeLVT},
+	{0xCC7C, 0xCC7C, graphemeLV},
+	{0xCC7D, 0xCC97, graphemeLVT},
+	{0xCC98, 0xCC98, graphemeLV},
+	{0xCC99, 0xCCB3, graphemeLVT},
+	{0xCCB4, 0xCCB4, graphemeLV},
+	{0xCCB5, 0xCCCF, graphemeLVT},
+	{0xCCD0, 0xCCD0, graphemeLV},
+	{0xCCD1, 0xCCEB, graphemeLVT},
+	{0xCCEC, 0xCCEC, graphemeLV},
+	{0xCCED, 0xCD07, graphemeLVT},
+	{0xCD08, 0xCD08, graphemeLV},
+	{0xCD09, 0xCD23, graphemeLVT},
+	{0xCD24, 0xCD24, graphemeLV},
+	{0xCD25, 0xCD3F, graphemeLVT},
+	{0xCD40, 0xCD40, graphemeLV},
+	{0xCD41, 0xCD5B, graphemeLVT},
+	{0xCD5C, 0xCD5C, graphemeLV},
+	{0xCD5D, 0xCD77, graphemeLVT},
+	{0xCD78, 0xCD78, graphemeLV},
+	{0xCD79, 0xCD93, graphemeLVT},
+	{0xCD94, 0xCD94, graphemeLV},
+	{0xCD95, 0xCDAF, graphemeLVT},
+	{0xCDB0, 0xCDB0, graphemeLV},
+	{0xCDB1, 0xCDCB, graphemeLVT},
+	{0xCDCC, 0xCDCC, graphemeLV},
+	{0xCDCD, 0xCDE7, graphemeLVT},
+	{0xCDE8, 0xCDE8, graphemeLV},
+	{0xCDE9, 0xCE03, graphemeLVT},
+	{0xCE04, 0xCE04, graphemeLV},
+	{0xCE05, 0xCE1F, graphemeLVT},
+	{0xCE20, 0xCE20, graphemeLV},
+	{0xCE21, 0xCE3B, graphemeLVT},
+	{0xCE3C, 0xCE3C, graphemeLV},
+	{0xCE3D, 0xCE57, graphemeLVT},
+	{0xCE58, 0xCE58, graphemeLV},
+	{0xCE59, 0xCE73, graphemeLVT},
+	{0xCE74, 0xCE74, graphemeLV},
+	{0xCE75, 0xCE8F, graphemeLVT},
+	{0xCE90, 0xCE90, graphemeLV},
+	{0xCE91, 0xCEAB, graphemeLVT},
+	{0xCEAC, 0xCEAC, graphemeLV},
+	{0xCEAD, 0xCEC7, graphemeLVT},
+	{0xCEC8, 0xCEC8, graphemeLV},
+	{0xCEC9, 0xCEE3, graphemeLVT},
+	{0xCEE4, 0xCEE4, graphemeLV},
+	{0xCEE5, 0xCEFF, graphemeLVT},
+	{0xCF00, 0xCF00, graphemeLV},
+	{0xCF01, 0xCF1B, graphemeLVT},
+	{0xCF1C, 0xCF1C, graphemeLV},
+	{0xCF1D, 0xCF37, graphemeLVT},
+	{0xCF38, 0xCF38, graphemeLV},
+	{0xCF39, 0xCF53, graphemeLVT},
+	{0xCF54, 0xCF54, graphemeLV},
+	{0xCF55, 0xCF6F, graphemeLVT},
+	{0xCF70, 0xCF70, graphemeLV},
+	{0xCF71, 0xCF8B, graphemeLVT},
+	{0xCF8C, 0xCF8C, graphemeLV},
+	{0xCF8D, 0xCFA7, graphemeLVT},
+	{0xCFA8, 0xCFA8, graphemeLV},
+	{0xCFA9, 0xCFC3, graphemeLVT},
+	{0xCFC4, 0xCFC4, graphemeLV},
+	{0xCFC5, 0xCFDF, graphemeLVT},
+	{0xCFE0, 0xCFE0, graphemeLV},
+	{0xCFE1, 0xCFFB, graphemeLVT},
+	{0xCFFC, 0xCFFC, graphemeLV},
+	{0xCFFD, 0xD017, graphemeLVT},
+	{0xD018, 0xD018, graphemeLV},
+	{0xD019, 0xD033, graphemeLVT},
+	{0xD034, 0xD034, graphemeLV},
+	{0xD035, 0xD04F, graphemeLVT},
+	{0xD050, 0xD050, graphemeLV},
+	{0xD051, 0xD06B, graphemeLVT},
+	{0xD06C, 0xD06C, graphemeLV},
+	{0xD06D, 0xD087, graphemeLVT},
+	{0xD088, 0xD088, graphemeLV},
+	{0xD089, 0xD0A3, graphemeLVT},
+	{0xD0A4, 0xD0A4, graphemeLV},
+	{0xD0A5, 0xD0BF, graphemeLVT},
+	{0xD0C0, 0xD0C0, graphemeLV},
+	{0xD0C1, 0xD0DB, graphemeLVT},
+	{0xD0DC, 0xD0DC, graphemeLV},
+	{0xD0DD, 0xD0F7, graphemeLVT},
+	{0xD0F8, 0xD0F8, graphemeLV},
+	{0xD0F9, 0xD113, graphemeLVT},
+	{0xD114, 0xD114, graphemeLV},
+	{0xD115, 0xD12F, graphemeLVT},
+	{0xD130, 0xD130, graphemeLV},
+	{0xD131, 0xD14B, graphemeLVT},
+	{0xD14C, 0xD14C, graphemeLV},
+	{0xD14D, 0xD167, graphemeLVT},
+	{0xD168, 0xD168, graphemeLV},
+	{0xD169, 0xD183, graphemeLVT},
+	{0xD184, 0xD184, graphemeLV},
+	{0xD185, 0xD19F, graphemeLVT},
+	{0xD1A0, 0xD1A0, graphemeLV},
+	{0xD1A1, 0xD1BB, graphemeLVT},
+	{0xD1BC, 0xD1BC, graphemeLV},
+	{0xD1BD, 0xD1D7, graphemeLVT},
+	{0xD1D8, 0xD1D8, graphemeLV},
+	{0xD1D9, 0xD1F3, graphemeLVT},
+	{0xD1F4, 0xD1F4, graphemeLV},
+	{0xD1F5, 0xD20F, graphemeLVT},
+	{0xD210, 0xD210, graphemeLV},
+	{0xD211, 0xD22B, graphemeLVT},
+	{0xD22C, 0xD22C, graphemeLV},
+	{0xD22D, 0xD247, graphemeLVT},
+	{0xD248, 0xD248, graphemeLV},
+	{0xD249, 0xD263, graphemeLVT},
+	{0xD264, 0xD264, graphemeLV},
+	{0xD265, 0xD27F, graphemeLVT},
+	{0xD280, 0xD280, graphemeLV},
+	{0xD281, 0xD29B, graphemeLVT},
+	{0xD29C, 0xD29C, graphemeLV},
+	{0xD29D, 0xD2B7, graphemeLVT},
+	{0xD2B8, 0xD2B8, graphemeLV},
+	{0xD2B9, 0xD2D3, graphemeLVT},
+	{0xD2D4, 0xD2D4, graphemeLV},
+	{0xD2D5, 0xD2EF, graphemeLVT},
+	{0xD2F0, 0xD2F0, graphemeLV},
+	{0xD2F1, 0xD30B, graphemeLVT},
+	{0xD30C, 0xD30C, graphemeLV},
+	{0xD30D, 0xD327, graphemeLVT},
+	{0xD328, 0xD328, graphemeLV},
+	{0xD329, 0xD343, graphemeLVT},
+	{0xD344, 0xD344, graphemeLV},
+	{0xD345, 0xD35F, graphemeLVT},
+	{0xD360, 0xD360, graphemeLV},
+	{0xD361, 0xD37B, graphemeLVT},
+	{0xD37C, 0xD37C, graphemeLV},
+	{0xD37D, 0xD397, graphemeLVT},
+	{0xD398, 0xD398, graphemeLV},
+	{0xD399, 0xD3B3, graphemeLVT},
+	{0xD3B4, 0xD3B4, graphemeLV},
+	{0xD3B5, 0xD3CF, graphemeLVT},
+	{0xD3D0, 0xD3D0, graphemeLV},
+	{0xD3D1, 0xD3EB, graphemeLVT},
+	{0xD3EC, 0xD3EC, graphemeLV},
+	{0xD3ED, 0xD407, graphemeLVT},
+	{0xD408, 0xD408, graphemeLV},
+	{0xD409, 0xD423, graphemeLVT},
+	{0xD424, 0xD424, graphemeLV},
+	{0xD425, 0xD43F, graphemeLVT},
+	{0xD440, 0xD440, graphemeLV},
+	{0xD441, 0xD45B, graphemeLVT},
+	{0xD45C, 0xD45C, graphemeLV},
+	{0xD45D, 0xD477, graphemeLVT},
+	{0xD478, 0xD478, graphemeLV},
+	{0xD479, 0xD493, graphemeLVT},
+	{0xD494, 0xD494, graphemeLV},
+	{0xD495, 0xD4AF, graphemeLVT},
+	{0xD4B0, 0xD4B0, graphemeLV},
+	{0xD4B1, 0xD4CB, graphemeLVT},
+	{0xD4CC, 0xD4CC, graphemeLV},
+	{0xD4CD, 0xD4E7, graphemeLVT},
+	{0xD4E8, 0xD4E8, graphemeLV},
+	{0xD4E9, 0xD503, graphemeLVT},
+	{0xD504, 0xD504, graphemeLV},
+	{0xD505, 0xD51F, graphemeLVT},
+	{0xD520, 0xD520, graphemeLV},
+	{0xD521, 0xD53B, graphemeLVT},
+	{0xD53C, 0xD53C, graphemeLV},
+	{0xD53D, 0xD557, graphemeLVT},
+	{0xD558, 0xD558, graphemeLV},
+	{0xD559, 0xD573, graphemeLVT},
+	{0xD574, 0xD574, graphemeLV},
+	{0xD575, 0xD58F, graphemeLVT},
+	{0xD590, 0xD590, graphemeLV},
+	{0xD591, 0xD5AB, graphemeLVT},
+	{0xD5AC, 0xD5AC, graphemeLV},
+	{0xD5AD, 0xD5C7, graphemeLVT},
+	{0xD5C8, 0xD5C8, graphemeLV},
+	{0xD5C9, 0xD5E3, graphemeLVT},
+	{0xD5E4, 0xD5E4, graphemeLV},
+	{0xD5E5, 0xD5FF, graphemeLVT},
+	{0xD600, 0xD600, graphemeLV},
+	{0xD601, 0xD61B, graphemeLVT},
+	{0xD61C, 0xD61C, graphemeLV},
+	{0xD61D, 0xD637, graphemeLVT},
+	{0xD638, 0xD638, graphemeLV},
+	{0xD639, 0xD653, graphemeLVT},
+	{0xD654, 0xD654, graphemeLV},
+	{0xD655, 0xD66F, graphemeLVT},
+	{0xD670, 0xD670, graphemeLV},
+	{0xD671, 0xD68B, graphemeLVT},
+	{0xD68C, 0xD68C, graphemeLV},
+	{0xD68D, 0xD6A7, graphemeLVT},
+	{0xD6A8, 0xD6A8, graphemeLV},
+	{0xD6A9, 0xD6C3, graphemeLVT},
+	{0xD6C4, 0xD6C4, graphemeLV},
+	{0xD6C5, 0xD6DF, graphemeLVT},
+	{0xD6E0, 0xD6E0, graphemeLV},
+	{0xD6E1, 0xD6FB, graphemeLVT},
+	{0xD6FC, 0xD6FC, graphemeLV},
+	{0xD6FD, 0xD717, graphemeLVT},
+	{0xD718, 0xD718, graphemeLV},
+	{0xD719, 0xD733, graphemeLVT},
+	{0xD734, 0xD734, graphemeLV},
+	{0xD735, 0xD74F, graphemeLVT},
+	{0xD750, 0xD750, graphemeLV},
+	{0xD751, 0xD76B, graphemeLVT},
+	{0xD76C, 0xD76C, graphemeLV},
+	{0xD76D, 0xD787, graphemeLVT},
+	{0xD788, 0xD788, graphemeLV},
+	{0xD789, 0xD7A3, graphemeLVT},
+	{0xD7B0, 0xD7C6, graphemeV},
+	{0xD7CB, 0xD7FB, graphemeT},
+	{0xFB1E, 0xFB1E, graphemeExtend},
+	{0xFE00, 0xFE0F, graphemeExtend},
+	{0xFE20, 0xFE2F, graphemeExtend},
+	{0xFEFF, 0xFEFF, graphemeControl},
+	{0xFF9E, 0xFF9F, graphemeExtend},
+	{0xFFF0, 0xFFFB, graphemeControl},
+	{0x101FD, 0x101FD, graphemeExtend},
+	{0x102E0, 0x102E0, graphemeExtend},
+	{0x10376, 0x1037A, graphemeExtend},
+	{0x10A01, 0x10A03, graphemeExtend},
+	{0x10A05, 0x10A06, graphemeExtend},
+	{0x10A0C, 0x10A0F, graphemeExtend},
+	{0x10A38, 0x10A3A, graphemeExtend},
+	{0x10A3F, 0x10A3F, graphemeExtend},
+	{0x10AE5, 0x10AE6, graphemeExtend},
+	{0x10D24, 0x10D27, graphemeExtend},
+	{0x10D69, 0x10D6D, graphemeExtend},
+	{0x10EAB, 0x10EAC, graphemeExtend},
+	{0x10EFC, 0x10EFF, graphemeExtend},
+	{0x10F46, 0x10F50, graphemeExtend},
+	{0x10F82, 0x10F85, graphemeExtend},
+	{0x11000, 0x11000, graphemeSpacingMark},
+	{0x11001, 0x11001, graphemeExtend},
+	{0x11002, 0x11002, graphemeSpacingMark},
+	{0x11038, 0x11046, graphemeExtend},
+	{0x11070, 0x11070, graphemeExtend},
+	{0x11073, 0x11074, graphemeExtend},
+	{0x1107F, 0x11081, graphemeExtend},
+	{0x11082, 0x11082, graphemeSpacingMark},
+	{0x110B0, 0x110B2, graphemeSpacingMark},
+	{0x110B3, 0x110B6, graphemeExtend},
+	{0x110B7, 0x110B8, graphemeSpacingMark},
+	{0x110B9, 0x110BA, graphemeExtend},
+	{0x110BD, 0x110BD, graphemePrepend},
+	{0x110C2, 0x110C2, graphemeExtend},
+	{0x110CD, 0x110CD, graphemePrepend},
+	{0x11100, 0x11102, graphemeExtend},
+	{0x11127, 0x1112B, graphemeExtend},
+	{0x1112C, 0x1112C, graphemeSpacingMark},
+	{0x1112D, 0x11134, graphemeExtend},
+	{0x11145, 0x11146, graphemeSpacingMark},
+	{0x11173, 0x11173, graphemeExtend},
+	{0x11180, 0x11181, graphemeExtend},
+	{0x11182, 0x11182, graphemeSpacingMark},
+	{0x111B3, 0x111B5, graphemeSpacingMark},
+	{0x111B6, 0x111BE, graphemeExtend},
+	{0x111BF, 0x111BF, graphemeSpacingMark},
+	{0x111C0, 0x111C0, graphemeExtend},
+	{0x111C2, 0x111C3, graphemePrepend},
+	{0x111C9, 0x111CC, graphemeExtend},
+	{0x111CE, 0x111CE, graphemeSpacingMark},
+	{0x111CF, 0x111CF, graphemeExtend},
+	{0x1122C, 0x1122E, graphemeSpacingMark},
+	{0x1122F, 0x11231, graphemeExtend},
+	{0x11232, 0x11233, graphemeSpacingMark},
+	{0x11234, 0x11237, graphemeExtend},
+	{0x1123E, 0x1123E, graphemeExtend},
+	{0x11241, 0x11241, graphemeExtend},
+	{0x112DF, 0x112DF, graphemeExtend},
+	{0x112E0, 0x112E2, graphemeSpacingMark},
+	{0x112E3, 0x112EA, graphemeExtend},
+	{0x11300, 0x11301, graphemeExtend},
+	{0x11302, 0x11303, graphemeSpacingMark},
+	{0x1133B, 0x1133C, graphemeExtend},
+	{0x1133E, 0x1133E, graphemeExtend},
+	{0x1133F, 0x1133F, graphemeSpacingMark},
+	{0x11340, 0x11340, graphemeExtend},
+	{0x11341, 0x11344, graphemeSpacingMark},
+	{0x11347, 0x11348, graphemeSpacingMark},
+	{0x1134B, 0x1134C, graphemeSpacingMark},
+	{0x1134D, 0x1134D, graphemeExtend},
+	{0x11357, 0x11357, graphemeExtend},
+	{0x11362, 0x11363, graphemeSpacingMark},
+	{0x11366, 0x1136C, graphemeExtend},
+	{0x11370, 0x11374, graphemeExtend},
+	{0x113B8, 0x113B8, graphemeExtend},
+	{0x113B9, 0x113BA, graphemeSpacingMark},
+	{0x113BB, 0x113C0, graphemeExtend},
+	{0x113C2, 0x113C2, graphemeExtend},
+	{0x113C5, 0x113C5, graphemeExtend},
+	{0x113C7, 0x113C9, graphemeExtend},
+	{0x113CA, 0x113CA, graphemeSpacingMark},
+	{0x113CC, 0x113CD, graphemeSpacingMark},
+	{0x113CE, 0x113D0, graphemeExtend},
+	{0x113D1, 0x113D1, graphemePrepend},
+	{0x113D2, 0x113D2, graphemeExtend},
+	{0x113E1, 0x113E2, graphemeExtend},
+	{0x11435, 0x11437, graphemeSpacingMark},
+	{0x11438, 0x1143F, graphemeExtend},
+	{0x11440, 0x11441, graphemeSpacingMark},
+	{0x11442, 0x11444, graphemeExtend},
+	{0x11445, 0x11445, graphemeSpacingMark},
+	{0x11446, 0x11446, graphemeExtend},
+	{0x1145E, 0x1145E, graphemeExtend},
+	{0x114B0, 0x114B0, graphemeExtend},
+	{0x114B1, 0x114B2, graphemeSpacingMark},
+	{0x114B3, 0x114B8, graphemeExtend},
+	{0x114B9, 0x114B9, graphemeSpacingMark},
+	{0x114BA, 0x114BA, graphemeExtend},
+	{0x114BB, 0x114BC, graphemeSpacingMark},
+	{0x114BD, 0x114BD, graphemeExtend},
+	{0x114BE, 0x114BE, graphemeSpacingMark},
+	{0x114BF, 0x114C0, graphemeExtend},
+	{0x114C1, 0x114C1, graphemeSpacingMark},
+	{0x114C2, 0x114C3, graphemeExtend},
+	{0x115AF, 0x115AF, graphemeExtend},
+	{0x115B0, 0x115B1, graphemeSpacingMark},
+	{0x115B2, 0x115B5, graphemeExtend},
+	{0x115B8, 0x115BB, graphemeSpacingMark},
+	{0x115BC, 0x115BD, graphemeExtend},
+	{0x115BE, 0x115BE, graphemeSpacingMark},
+	{0x115BF, 0x115C0, graphemeExtend},
+	{0x115DC, 0x115DD, graphemeExtend},
+	{0x11630, 0x11632, graphemeSpacingMark},
+	{0x11633, 0x1163A, graphemeExtend},
+	{0x1163B, 0x1163C, graphemeSpacingMark},
+	{0x1163D, 0x1163D, graphemeExtend},
+	{0x1163E, 0x1163E, graphemeSpacingMark},
+	{0x1163F, 0x11640, graphemeExtend},
+	{0x116AB, 0x116AB, graphemeExtend},
+	{0x116AC, 0x116AC, graphemeSpacingMark},
+	{0x116AD, 0x116AD, graphemeExtend},
+	{0x116AE, 0x116AF, graphemeSpacingMark},
+	{0x116B0, 0x116B7, graphemeExtend},
+	{0x1171D, 0x1171D, graphemeExtend},
+	{0x1171E, 0x1171E, graphemeSpacingMark},
+	{0x1171F, 0x1171F, graphemeExtend},
+	{0x11722, 0x11725, graphemeExtend},
+	{0x11726, 0x11726, graphemeSpacingMark},
+	{0x11727, 0x1172B, graphemeExtend},
+	{0x1182C, 0x1182E, graphemeSpacingMark},
+	{0x1182F, 0x11837, graphemeExtend},
+	{0x11838, 0x11838, graphemeSpacingMark},
+	{0x11839, 0x1183A, graphemeExtend},
+	{0x11930, 0x11930, graphemeExtend},
+	{0x11931, 0x11935, graphemeSpacingMark},
+	{0x11937, 0x11938, graphemeSpacingMark},
+	{0x1193B, 0x1193E, graphemeExtend},
+	{0x1193F, 0x1193F, graphemePrepend},
+	{0x11940, 0x11940, graphemeSpacingMark},
+	{0x11941, 0x11941, graphemePrepend},
+	{0x11942, 0x11942, graphemeSpacingMark},
+	{0x11943, 0x11943, graphemeExtend},
+	{0x119D1, 0x119D3, graphemeSpacingMark},
+	{0x119D4, 0x119D7, graphemeExtend},
+	{0x119DA, 0x119DB, graphemeExtend},
+	{0x119DC, 0x119DF, graphemeSpacingMark},
+	{0x119E0, 0x119E0, graphemeExtend},
+	{0x119E4, 0x119E4, graphemeSpacingMark},
+	{0x11A01, 0x11A0A, graphemeExtend},
+	{0x11A33, 0x11A38, graphemeExtend},
+	{0x11A39, 0x11A39, graphemeSpacingMark},
+	{0x11A3A, 0x11A3A, graphemePrepend},
+	{0x11A3B, 0x11A3E, graphemeExtend},
+	{0x11A47, 0x11A47, graphemeExtend},
+	{0x11A51, 0x11A56, graphemeExtend},
+	{0x11A57, 0x11A58, graphemeSpacingMark},
+	{0x11A59, 0x11A5B, graphemeExtend},
+	{0x11A84, 0x11A89, graphemePrepend},
+	{0x11A8A, 0x11A96, graphemeExtend},
+	{0x11A97, 0x11A97, graphemeSpacingMark},
+	{0x11A98, 0x11A99, graphemeExtend},
+	{0x11C2F, 0x11C2F, graphemeSpacingMark},
+	{0x11C30, 0x11C36, graphemeExtend},
+	{0x11C38, 0x11C3D, graphemeExtend},
+	{0x11C3E, 0x11C3E, graphemeSpacingMark},
+	{0x11C3F, 0x11C3F, graphemeExtend},
+	{0x11C92, 0x11CA7, graphemeExtend},
+	{0x11CA9, 0x11CA9, graphemeSpacingMark},
+	{0x11CAA, 0x11CB0, graphemeExtend},
+	{0x11CB1, 0x11CB1, graphemeSpacingMark},
+	{0x11CB2, 0x11CB3, graphemeExtend},
+	{0x11CB4, 0x11CB4, graphemeSpacingMark},
+	{0x11CB5, 0x11CB6, graphemeExtend},
+	{0x11D31, 0x11D36, graphemeExtend},
+	{0x11D3A, 0x11D3A, graphemeExtend},
+	{0x11D3C, 0x11D3D, graphemeExtend},
+	{0x11D3F, 0x11D45, graphemeExtend},
+	{0x11D46, 0x11D46, graphemePrepend},
+	{0x11D47, 0x11D47, graphemeExtend},
+	{0x11D8A, 0x11D8E, graphemeSpacingMark},
+	{0x11D90, 0x11D91, graphemeExtend},
+	{0x11D93, 0x11D94, graphemeSpacingMark},
+	{0x11D95, 0x11D95, graphemeExtend},
+	{0x11D96, 0x11D96, graphemeSpacingMark},
+	{0x11D97, 0x11D97, graphemeExtend},
+	{0x11EF3, 0x11EF4, graphemeExtend},
+	{0x11EF5, 0x11EF6, graphemeSpacingMark},
+	{0x11F00, 0x11F01, graphemeExtend},
+	{0x11F02, 0x11F02, graphemePrepend},
+	{0x11F03, 0x11F03, graphemeSpacingMark},
+	{0x11F34, 0x11F35, graphemeSpacingMark},
+	{0x11F36, 0x11F3A, graphemeExtend},
+	{0x11F3E, 0x11F3F, graphemeSpacingMark},
+	{0x11F40, 0x11F42, graphemeExtend},
+	{0x11F5A, 0x11F5A, graphemeExtend},
+	{0x13430, 0x1343F, graphemeControl},
+	{0x13440, 0x13440, graphemeExtend},
+	{0x13447, 0x13455, graphemeExtend},
+	{0x1611E, 0x16129, graphemeExtend},
+	{0x1612A, 0x1612C, graphemeSpacingMark},
+	{0x1612D, 0x1612F, graphemeExtend},
+	{0x16AF0, 0x16AF4, graphemeExtend},
+	{0x16B30, 0x16B36, graphemeExtend},
+	{0x16D63, 0x16D63, graphemeV},
+	{0x16D67, 0x16D6A, graphemeV},
+	{0x16F4F, 0x16F4F, graphemeExtend},
+	{0x16F51, 0x16F87, graphemeSpacingMark},
+	{0x16F8F, 0x16F92, graphemeExtend},
+	{0x16FE4, 0x16FE4, graphemeExtend},
+	{0x16FF0, 0x16FF1, graphemeExtend},
+	{0x1BC9D, 0x1BC9E, graphemeExtend},
+	{0x1BCA0, 0x1BCA3, graphemeControl},
+	{0x1CF00, 0x1CF2D, graphemeExtend},
+	{0x1CF30, 0x1CF46, graphemeExtend},
+	{0x1D165, 0x1D169, graphemeExtend},
+	{0x1D16D, 0x1D172, graphemeExtend},
+	{0x1D173, 0x1D17A, graphemeControl},
+	{0x1D17B, 0x1D182, graphemeExtend},
+	{0x1D185, 0x1D18B, graphemeExtend},
+	{0x1D1AA, 0x1D1AD, graphemeExtend},
+	{0x1D242, 0x1D244, graphemeExtend},
+	{0x1DA00, 0x1DA36, graphemeExtend},
+	{0x1DA3B, 0x1DA6C, graphemeExtend},
+	{0x1DA75, 0x1DA75, graphemeExtend},
+	{0x1DA84, 0x1DA84, graphemeExtend},
+	{0x1DA9B, 0x1DA9F, graphemeExtend},
+	{0x1DAA1, 0x1DAAF, graphemeExtend},
+	{0x1E000, 0x1E006, graphemeExtend},
+	{0x1E008, 0x1E018, graphemeExtend},
+	{0x1E01B, 0x1E021, graphemeExtend},
+	{0x1E023, 0x1E024, graphemeExtend},
+	{0x1E026, 0x1E02A, graphemeExtend},
+	{0x1E08F, 0x1E08F, graphemeExtend},
+	{0x1E130, 0x1E136, graphemeExtend},
+	{0x1E2AE, 0x1E2AE, graphemeExtend},
+	{0x1E2EC, 0x1E2EF, graphemeExtend},
+	{0x1E4EC, 0x1E4EF, graphemeExtend},
+	{0x1E5EE, 0x1E5EF, graphemeExtend},
+	{0x1E8D0, 0x1E8D6, graphemeExtend},
+	{0x1E944, 0x1E94A, graphemeExtend},
+	{0x1F000, 0x1F0FF, graphemeExtPict},
+	{0x1F10D, 0x1F10F, graphemeExtPict},
+	{0x1F12F, 0x1F12F, graphemeExtPict},
+	{0x1F16C, 0x1F171, graphemeExtPict},
+	{0x1F17E, 0x1F17F, graphemeExtPict},
+	{0x1F18E, 0x1F18E, graphemeExtPict},
+	{0x1F191, 0x1F19A, graphemeExtPict},
+	{0x1F1AD, 0x1F1E5, graphemeExtPict},
+	{0x1F1E6, 0x1F1FF, graphemeRI},
+	{0x1F201, 0x1F20F, graphemeExtPict},
+	{0x1F21A, 0x1F21A, graphemeExtPict},
+	{0x1F22F, 0x1F22F, graphemeExtPict},
+	{0x1F232, 0x1F23A, graphemeExtPict},
+	{0x1F23C, 0x1F23F, graphemeExtPict},
+	{0x1F249, 0x1F3FA, graphemeExtPict},
+	{0x1F3FB, 0x1F3FF, graphemeExtend},
+	{0x1F400, 0x1F53D, graphemeExtPict},
+	{0x1F546, 0x1F64F, graphemeExtPict},
+	{0x1F680, 0x1F6FF, graphemeExtPict},
+	{0x1F774, 0x1F77F, graphemeExtPict},
+	{0x1F7D5, 0x1F7FF, graphemeExtPict},
+	{0x1F80C, 0x1F80F, graphemeExtPict},
+	{0x1F848, 0x1F84F, graphemeExtPict},
+	{0x1F85A, 0x1F85F, graphemeExtPict},
+	{0x1F888, 0x1F88F, graphemeExtPict},
+	{0x1F8AE, 0x1F8FF, graphemeExtPict},
+	{0x1F90C, 0x1F93A, graphemeExtPict},
+	{0x1F93C, 0x1F945, graphemeExtPict},
+	{0x1F947, 0x1FAFF, graphemeExtPict},
+	{0x1FC00, 0x1FFFD, graphemeExtPict},
+	{0xE0000, 0xE001F, graphemeControl},
+	{0xE0020, 0xE007F, graphemeExtend},
+	{0xE0080, 0xE00FF, graphemeControl},
+	{0xE0100, 0xE01EF, graphemeExtend},
+	{0xE01F0, 0xE0FFF, graphemeControl},
+}
+
+// ranges of InCB=Extend (Indic_Conjunct_Break), sorted
+var graphemeInCBExtendTable = []graphemeRange{
+	{0x300, 0x36F, graphemeExtend},
+	{0x483, 0x489, graphemeExtend},
+	{0x591, 0x5BD, graphemeExtend},
+	{0x5BF, 0x5BF, graphemeExtend},
+	{0x5C1, 0x5C2, graphemeExtend},
+	{0x5C4, 0x5C5, graphemeExtend},
+	{0x5C7, 0x5C7, graphemeExtend},
+	{0x610, 0x61A, graphemeExtend},
+	{0x64B, 0x65F, graphemeExtend},
+	{0x670, 0x670, graphemeExtend},
+	{0x6D6, 0x6DC, graphemeExtend},
+	{0x6DF, 0x6E4, graphemeExtend},
+	{0x6E7, 0x6E8, graphemeExtend},
+	{0x6EA, 0x6ED, graphemeExtend},
+	{0x711, 0x711, graphemeExtend},
+	{0x730, 0x74A, graphemeExtend},
+	{0x7A6, 0x7B0, graphemeExtend},
+	{0x7EB, 0x7F3, graphemeExtend},
+	{0x7FD, 0x7FD, graphemeExtend},
+	{0x816, 0x819, graphemeExtend},
+	{0x81B, 0x823, graphemeExtend},
+	{0x825, 0x827, graphemeExtend},
+	{0x829, 0x82D, graphemeExtend},
+	{0x859, 0x85B, graphemeExtend},
+	{0x897, 0x89F, graphemeExtend},
+	{0x8CA, 0x8E1, graphemeExtend},
+	{0x8E3, 0x902, graphemeExtend},
+	{0x93A, 0x93A, graphemeExtend},
+	{0x93C, 0x93C, graphemeExtend},
+	{0x941, 0x948, graphemeExtend},
+	{0x951, 0x957, graphemeExtend},
+	{0x962, 0x963, graphemeExtend},
+	{0x981, 0x981, graphemeExtend},
+	{0x9BC, 0x9BC, graphemeExtend},
+	{0x9BE, 0x9BE, graphemeExtend},
+	{0x9C1, 0x9C4, graphemeExtend},
+	{0x9D7, 0x9D7, graphemeExtend},
+	{0x9E2, 0x9E3, graphemeExtend},
+	{0x9FE, 0x9FE, graphemeExtend},
+	{0xA01, 0xA02, graphemeExtend},
+	{0xA3C, 0xA3C, graphemeExtend},
+	{0xA41, 0xA42, graphemeExtend},
+	{0xA47, 0xA48, graphemeExtend},
+	{0xA4B, 0xA4D, graphemeExtend},
+	{0xA51, 0xA51, graphemeExtend},
+	{0xA70, 0xA71, graphemeExtend},
+	{0xA75, 0xA75, graphemeExtend},
+	{0xA81, 0xA82, graphemeExtend},
+	{0xABC, 0xABC, graphemeExtend},
+	{0xAC1, 0xAC5, graphemeExtend},
+	{0xAC7, 0xAC8, graphemeExtend},
+	{0xAE2, 0xAE3, graphemeExtend},
+	{0xAFA, 0xAFF, graphemeExtend},
+	{0xB01, 0xB01, graphemeExtend},
+	{0xB3C, 0xB3C, graphemeExtend},
+	{0xB3E, 0xB3F, graphemeExtend},
+	{0xB41, 0xB44, graphemeExtend},
+	{0xB55, 0xB57, graphemeExtend},
+	{0xB62, 0xB63, graphemeExtend},
+	{0xB82, 0xB82, graphemeExtend},
+	{0xBBE, 0xBBE, graphemeExtend},
+	{0xBC0, 0xBC0, graphemeExtend},
+	{0xBCD, 0xBCD, graphemeExtend},
+	{0xBD7, 0xBD7, graphemeExtend},
+	{0xC00, 0xC00, graphemeExtend},
+	{0xC04, 0xC04, graphemeExtend},
+	{0xC3C, 0xC3C, graphemeExtend},
+	{0xC3E, 0xC40, graphemeExtend},
+	{0xC46, 0xC48, graphemeExtend},
+	{0xC4A, 0xC4C, graphemeExtend},
+	{0xC55, 0xC56, graphemeExtend},
+	{0xC62, 0xC63, graphemeExtend},
+	{0xC81, 0xC81, graphemeExtend},
+	{0xCBC, 0xCBC, graphemeExtend},
+	{0xCBF, 0xCC0, graphemeExtend},
+	{0xCC2, 0xCC2, graphemeExtend},
+	{0xCC6, 0xCC8, graphemeExtend},
+	{0xCCA, 0xCCD, graphemeExtend},
+	{0xCD5, 0xCD6, graphemeExtend},
+	{0xCE2, 0xCE3, graphemeExtend},
+	{0xD00, 0xD01, graphemeExtend},
+	{0xD3B, 0xD3C, graphemeExtend},
+	{0xD3E, 0xD3E, graphemeExtend},
+	{0xD41, 0xD44, graphemeExtend},
+	{0xD57, 0xD57, graphemeExtend},
+	{0xD62, 0xD63, graphemeExtend},
+	{0xD81, 0xD81, graphemeExtend},
+	{0xDCA, 0xDCA, graphemeExtend},
+	{0xDCF, 0xDCF, graphemeExtend},
+	{0xDD2, 0xDD4, graphemeExtend},
+	{0xDD6, 0xDD6, graphemeExtend},
+	{0xDDF, 0xDDF, graphemeExtend},
+	{0xE31, 0xE31, graphemeExtend},
+	{0xE34, 0xE3A, graphemeExtend},
+	{0xE47, 0xE4E, graphemeExtend},
+	{0xEB1, 0xEB1, graphemeExtend},
+	{0xEB4, 0xEBC, graphemeExtend},
+	{0xEC8, 0xECE, graphemeExtend},
+	{0xF18, 0xF19, graphemeExtend},
+	{0xF35, 0xF35, graphemeExtend},
+	{0xF37, 0xF37, graphemeExtend},
+	{0xF39, 0xF39, graphemeExtend},
+	{0xF71, 0xF7E, graphemeExtend},
+	{0xF80, 0xF84, graphemeExtend},
+	{0xF86, 0xF87, graphemeExtend},
+	{0xF8D, 0xF97, graphemeExtend},
+	{0xF99, 0xFBC, graphemeExtend},
+	{0xFC6, 0xFC6, graphemeExtend},
+	{0x102D, 0x1030, graphemeExtend},
+	{0x1032, 0x1037, graphemeExtend},
+	{0x1039, 0x103A, graphemeExtend},
+	{0x103D, 0x103E, graphemeExtend},
+	{0x1058, 0x1059, graphemeExtend},
+	{0x105E, 0x1060, graphemeExtend},
+	{0x1071, 0x1074, graphemeExtend},
+	{0x1082, 0x1082, graphemeExtend},
+	{0x1085, 0x1086, graphemeExtend},
+	{0x108D, 0x108D, graphemeExtend},
+	{0x109D, 0x109D, graphemeExtend},
+	{0x135D, 0x135F, graphemeExtend},
+	{0x1712, 0x1715, graphemeExtend},
+	{0x1732, 0x1734, graphemeExtend},
+	{0x1752, 0x1753, graphemeExtend},
+	{0x1772, 0x1773, graphemeExtend},
+	{0x17B4, 0x17B5, graphemeExtend},
+	{0x17B7, 0x17BD, graphemeExtend},
+	{0x17C6, 0x17C6, graphemeExtend},
+	{0x17C9, 0x17D3, graphemeExtend},
+	{0x17DD, 0x17DD, graphemeExtend},
+	{0x180B, 0x180D, graphemeExtend},
+	{0x180F, 0x180F, graphemeExtend},
+	{0x1885, 0x1886, graphemeExtend},
+	{0x18A9, 0x18A9, graphemeExtend},
+	{0x1920, 0x1922, graphemeExtend},
+	{0x1927, 0x1928, graphemeExtend},
+	{0x1932, 0x1932, graphemeExtend},
+	{0x1939, 0x193B, graphemeExtend},
+	{0x1A17, 0x1A18, graphemeExtend},
+	{0x1A1B, 0x1A1B, graphemeExtend},
+	{0x1A56, 0x1A56, graphemeExtend},
+	{0x1A58, 0x1A5E, graphemeExtend},
+	{0x1A60, 0x1A60, graphemeExtend},
+	{0x1A62, 0x1A62, graphemeExtend},
+	{0x1A65, 0x1A6C, graphemeExtend},
+	{0x1A73, 0x1A7C, graphemeExtend},
+	{0x1A7F, 0x1A7F, graphemeExtend},
+	{0x1AB0, 0x1ACE, graphemeExtend},
+	{0x1B00, 0x1B03, graphemeExtend},
+	{0x1B34, 0x1B3D, graphemeExtend},
+	{0x1B42, 0x1B44, graphemeExtend},
+	{0x1B6B, 0x1B73, graphemeExtend},
+	{0x1B80, 0x1B81, graphemeExtend},
+	{0x1BA2, 0x1BA5, graphemeExtend},
+	{0x1BA8, 0x1BAD, graphemeExtend},
+	{0x1BE6, 0x1BE6, graphemeExtend},
+	{0x1BE8, 0x1BE9, graphemeExtend},
+	{0x1BED, 0x1BED, graphemeExtend},
+	{0x1BEF, 0x1BF3, graphemeExtend},
+	{0x1C2C, 0x1C33, graphemeExtend},
+	{0x1C36, 0x1C37, graphemeExtend},
+	{0x1CD0, 0x1CD2, graphemeExtend},
+	{0x1CD4, 0x1CE0, graphemeExtend},
+	{0x1CE2, 0x1CE8, graphemeExtend},
+	{0x1CED, 0x1CED, graphemeExtend},
+	{0x1CF4, 0x1CF4, graphemeExtend},
+	{0x1CF8, 0x1CF9, graphemeExtend},
+	{0x1DC0, 0x1DFF, graphemeExtend},
+	{0x200D, 0x200D, graphemeExtend},
+	{0x20D0, 0x20F0, graphemeExtend},
+	{0x2CEF, 0x2CF1, graphemeExtend},
+	{0x2D7F, 0x2D7F, graphemeExtend},
+	{0x2DE0, 0x2DFF, graphemeExtend},
+	{0x302A, 0x302F, graphemeExtend},
+	{0x3099, 0x309A, graphemeExtend},
+	{0xA66F, 0xA672, graphemeExtend},
+	{0xA674, 0xA67D, graphemeExtend},
+	{0xA69E, 0xA69F, graphemeExtend},
+	{0xA6F0, 0xA6F1, graphemeExtend},
+	{0xA802, 0xA802, graphemeExtend},
+	{0xA806, 0xA806, graphemeExtend},
+	{0xA80B, 0xA80B, graphemeExtend},
+	{0xA825, 0xA826, graphemeExtend},
+	{0xA82C, 0xA82C, graphemeExtend},
+	{0xA8C4, 0xA8C5, graphemeExtend},
+	{0xA8E0, 0xA8F1, graphemeExtend},
+	{0xA8FF, 0xA8FF, graphemeExtend},
+	{0xA926, 0xA92D, graphemeExtend},
+	{0xA947, 0xA951, graphemeExtend},
+	{0xA953, 0xA953, graphemeExtend},
+	{0xA980, 0xA982, graphemeExtend},
+	{0xA9B3, 0xA9B3, graphemeExtend},
+	{0xA9B6, 0xA9B9, graphemeExtend},
+	{0xA9BC, 0xA9BD, graphemeExtend},
+	{0xA9C0, 0xA9C0, graphemeExtend},
+	{0xA9E5, 0xA9E5, graphemeExtend},
+	{0xAA29, 0xAA2E, graphemeExtend},
+	{0xAA31, 0xAA32, graphemeExtend},
+	{0xAA35, 0xAA36, graphemeExtend},
+	{0xAA43, 0xAA43, graphemeExtend},
+	{0xAA4C, 0xAA4C, graphemeExtend},
+	{0xAA7C, 0xAA7C, graphemeExtend},
+	{0xAAB0, 0xAAB0, graphemeExtend},
+	{0xAAB2, 0xAAB4, graphemeExtend},
+	{0xAAB7, 0xAAB8, graphemeExtend},
+	{0xAABE, 0xAABF, graphemeExtend},
+	{0xAAC1, 0xAAC1, graphemeExtend},
+	{0xAAEC, 0xAAED, graphemeExtend},
+	{0xAAF6, 0xAAF6, graphemeExtend},
+	{0xABE5, 0xABE5, graphemeExtend},
+	{0xABE8, 0xABE8, graphemeExtend},
+	{0xABED, 0xABED, graphemeExtend},
+	{0xFB1E, 0xFB1E, graphemeExtend},
+	{0xFE00, 0xFE0F, graphemeExtend},
+	{0xFE20, 0xFE2F, graphemeExtend},
+	{0xFF9E, 0xFF9F, graphemeExtend},
+	{0x101FD, 0x101FD, graphemeExtend},
+	{0x102E0, 0x102E0, graphemeExtend},
+	{0x10376, 0x1037A, graphemeExtend},
+	{0x10A01, 0x10A03, graphemeExtend},
+	{0x10A05, 0x10A06, graphemeExtend},
+	{0x10A0C, 0x10A0F, graphemeExtend},
+	{0x10A38, 0x10A3A, graphemeExtend},
+	{0x10A3F, 0x10A3F, graphemeExtend},
+	{0x10AE5, 0x10AE6, graphemeExtend},
+	{0x10D24, 0x10D27, graphemeExtend},
+	{0x10D69, 0x10D6D, graphemeExtend},
+	{0x10EAB, 0x10EAC, graphemeExtend},
+	{0x10EFC, 0x10EFF, graphemeExtend},
+	{0x10F46, 0x10F50, graphemeExtend},
+	{0x10F82, 0x10F85, graphemeExtend},
+	{0x11001, 0x11001, graphemeExtend},
+	{0x11038, 0x11046, graphemeExtend},
+	{0x11070, 0x11070, graphemeExtend},
+	{0x11073, 0x11074, graphemeExtend},
+	{0x1107F, 0x11081, graphemeExtend},
+	{0x110B3, 0x110B6, graphemeExtend},
+	{0x110B9, 0x110BA, graphemeExtend},
+	{0x110C2, 0x110C2, graphemeExtend},
+	{0x11100, 0x11102, graphemeExtend},
+	{0x11127, 0x1112B, graphemeExtend},
+	{0x1112D, 0x11134, graphemeExtend},
+	{0x11173, 0x11173, graphemeExtend},
+	{0x11180, 0x11181, graphemeExtend},
+	{0x111B6, 0x111BE, graphemeExtend},
+	{0x111C0, 0x111C0, graphemeExtend},
+	{0x111C9, 0x111CC, graphemeExtend},
+	{0x111CF, 0x111CF, graphemeExtend},
+	{0x1122F, 0x11231, graphemeExtend},
+	{0x11234, 0x11237, graphemeExtend},
+	{0x1123E, 0x1123E, graphemeExtend},
+	{0x11241, 0x11241, graphemeExtend},
+	{0x112DF, 0x112DF, graphemeExtend},
+	{0x112E3, 0x112EA, graphemeExtend},
+	{0x11300, 0x11301, graphemeExtend},
+	{0x1133B, 0x1133C, graphemeExtend},
+	{0x1133E, 0x1133E, graphemeExtend},
+	{0x11340, 0x11340, graphemeExtend},
+	{0x1134D, 0x1134D, graphemeExtend},
+	{0x11357, 0x11357, graphemeExtend},
+	{0x11366, 0x1136C, graphemeExtend},
+	{0x11370, 0x11374, graphemeExtend},
+	{0x113B8, 0x113B8, graphemeExtend},
+	{0x113BB, 0x113C0, graphemeExtend},
+	{0x113C2, 0x113C2, graphemeExtend},
+	{0x113C5, 0x113C5, graphemeExtend},
+	{0x113C7, 0x113C9, graphemeExtend},
+	{0x113CE, 0x113D0, graphemeExtend},
+	{0x113D2, 0x113D2, graphemeExtend},
+	{0x113E1, 0x113E2, graphemeExtend},
+	{0x11438, 0x1143F, graphemeExtend},
+	{0x11442, 0x11444, graphemeExtend},
+	{0x11446, 0x11446, graphemeExtend},
+	{0x1145E, 0x1145E, graphemeExtend},
+	{0x114B0, 0x114B0, graphemeExtend},
+	{0x114B3, 0x114B8, graphemeExtend},
+	{0x114BA, 0x114BA, graphemeExtend},
+	{0x114BD, 0x114BD, graphemeExtend},
+	{0x114BF, 0x114C0, graphemeExtend},
+	{0x114C2, 0x114C3, graphemeExtend},
+	{0x115AF, 0x115AF, graphemeExtend},
+	{0x115B2, 0x115B5, graphemeExtend},
+	{0x115BC, 0x115BD, graphemeExtend},
+	{0x115BF, 0x115C0, graphemeExtend},
+	{0x115DC, 0x115DD, graphemeExtend},
+	{0x11633, 0x1163A, graphemeExtend},
+	{0x1163D, 0x1163D, graphemeExtend},
+	{0x1163F, 0x11640, graphemeExtend},
+	{0x116AB, 0x116AB, graphemeExtend},
+	{0x116AD, 0x116AD, graphemeExtend},
+	{0x116B0, 0x116B7, graphemeExtend},
+	{0x1171D, 0x1171D, graphemeExtend},
+	{0x1171F, 0x1171F, graphemeExtend},
+	{0x11722, 0x11725, graphemeExtend},
+	{0x11727, 0x1172B, graphemeExtend},
+	{0x1182F, 0x11837, graphemeExtend},
+	{0x11839, 0x1183A, graphemeExtend},
+	{0x11930, 0x11930, graphemeExtend},
+	{0x1193B, 0x1193E, graphemeExtend},
+	{0x11943, 0x11943, graphemeExtend},
+	{0x119D4, 0x119D7, graphemeExtend},
+	{0x119DA, 0x119DB, graphemeExtend},
+	{0x119E0, 0x119E0, graphemeExtend},
+	{0x11A01, 0x11A0A, graphemeExtend},
+	{0x11A33, 0x11A38, graphemeExtend},
+	{0x11A3B, 0x11A3E, graphemeExtend},
+	{0x11A47, 0x11A47, graphemeExtend},
+	{0x11A51, 0x11A56, graphemeExtend},
+	{0x11A59, 0x11A5B, graphemeExtend},
+	{0x11A8A, 0x11A96, graphemeExtend},
+	{0x11A98, 0x11A99, graphemeExtend},
+	{0x11C30, 0x11C36, graphemeExtend},
+	{0x11C38, 0x11C3D, graphemeExtend},
+	{0x11C3F, 0x11C3F, graphemeExtend},
+	{0x11C92, 0x11CA7, graphemeExtend},
+	{0x11CAA, 0x11CB0, graphemeExtend},
+	{0x11CB2, 0x11CB3, graphemeExtend},
+	{0x11CB5, 0x11CB6, graphemeExtend},
+	{0x11D31, 0x11D36, graphemeExtend},
+	{0x11D3A, 0x11D3A, graphemeExtend},
+	{0x11D3C, 0x11D3D, graphemeExtend},
+	{0x11D3F, 0x11D45, graphemeExtend},
+	{0x11D47, 0x11D47, graphemeExtend},
+	{0x11D90, 0x11D91, graphemeExtend},
+	{0x11D95, 0x11D95, graphemeExtend},
+	{0x11D97, 0x11D97, graphemeExtend},
+	{0x11EF3, 0x11EF4, graphemeExtend},
+	{0x11F00, 0x11F01, graphemeExtend},
+	{0x11F36, 0x11F3A, graphemeExtend},
+	{0x11F40, 0x11F42, graphemeExtend},
+	{0x11F5A, 0x11F5A, graphemeExtend},
+	{0x13440, 0x13440, graphemeExtend},
+	{0x13447, 0x13455, graphemeExtend},
+	{0x1611E, 0x16129, graphemeExtend},
+	{0x1612D, 0x1612F, graphemeExtend},
+	{0x16AF0, 0x16AF4, graphemeExtend},
+	{0x16B30, 0x16B36, graphemeExtend},
+	{0x16F4F, 0x16F4F, graphemeExtend},
+	{0x16F8F, 0x16F92, graphemeExtend},
+	{0x16FE4, 0x16FE4, graphemeExtend},
+	{0x16FF0, 0x16FF1, graphemeExtend},
+	{0x1BC9D, 0x1BC9E, graphemeExtend},
+	{0x1CF00, 0x1CF2D, graphemeExtend},
+	{0x1CF30, 0x1CF46, graphemeExtend},
+	{0x1D165, 0x1D169, graphemeExtend},
+	{0x1D16D, 0x1D172, graphemeExtend},
+	{0x1D17B, 0x1D182, graphemeExtend},
+	{0x1D185, 0x1D18B, graphemeExtend},
+	{0x1D1AA, 0x1D1AD, graphemeExtend},
+	{0x1D242, 0x1D244, graphemeExtend},
+	{0x1DA00, 0x1DA36, graphemeExtend},
+	{0x1DA3B, 0x1DA6C, graphemeExtend},
+	{0x1DA75, 0x1DA75, graphemeExtend},
+	{0x1DA84, 0x1DA84, graphemeExtend},
+	{0x1DA9B, 0x1DA9F, graphemeExtend},
+	{0x1DAA1, 0x1DAAF, graphemeExtend},
+	{0x1E000, 0x1E006, graphemeExtend},
+	{0x1E008, 0x1E018, graphemeExtend},
+	{0x1E01B, 0x1E021, graphemeExtend},
+	{0x1E023, 0x1E024, graphemeExtend},
+	{0x1E026, 0x1E02A, graphemeExtend},
+	{0x1E08F, 0x1E08F, graphemeExtend},
+	{0x1E130, 0x1E136, graphemeExtend},
+	{0x1E2AE, 0x1E2AE, graphemeExtend},
+	{0x1E2EC, 0x1E2EF, graphemeExtend},
+	{0x1E4EC, 0x1E4EF, graphemeExtend},
+	{0x1E5EE, 0x1E5EF, graphemeExtend},
+	{0x1E8D0, 0x1E8D6, graphemeExtend},
+	{0x1E944, 0x1E94A, graphemeExtend},
+	{0x1F3FB, 0x1F3FF, graphemeExtend},
+	{0xE0020, 0xE007F, graphemeExtend},
+	{0xE0100, 0xE01EF, graphemeExtend},
+}
+
+// ranges of InCB=Linker (Indic_Conjunct_Break), sorted
+var graphemeInCBLinkerTable = []graphemeRange{
+	{0x94D, 0x94D, graphemeExtend},
+	{0x9CD, 0x9CD, graphemeExtend},
+	{0xACD, 0xACD, graphemeExtend},
+	{0xB4D, 0xB4D, graphemeExtend},
+	{0xC4D, 0xC4D, graphemeExtend},
+	{0xD4D, 0xD4D, graphemeExtend},
+}
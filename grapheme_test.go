@@ -0,0 +1,192 @@
+package strutils_test
+
+import (
+	"bufio"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+type graphemeTestVal struct {
+	str       string
+	graphemes []string
+}
+
+func Test_strutils_Graphemes(t *testing.T) {
+	t.Parallel()
+
+	dataset := []graphemeTestVal{
+		{"", []string{}},
+		{"abc", []string{"a", "b", "c"}},
+		{"a\r\nb\n\r", []string{"a", "\r\n", "b", "\n", "\r"}},
+		{"e\u0301\u0302x", []string{"e\u0301\u0302", "x"}},
+		{"\u0301a", []string{"\u0301", "a"}},
+		{"한국", []string{"한", "국"}},
+		{"각ᄀ", []string{"각", "ᄀ"}},
+		{"\U0001F1F0\U0001F1F7\U0001F1EF\U0001F1F5\U0001F1FA", []string{"\U0001F1F0\U0001F1F7", "\U0001F1EF\U0001F1F5", "\U0001F1FA"}},
+		{"\U0001F468\u200d\U0001F469\u200d\U0001F467\u200d\U0001F466!", []string{"\U0001F468\u200d\U0001F469\u200d\U0001F467\u200d\U0001F466", "!"}},
+		{"\U0001F44D\U0001F3FD\U0001F44D", []string{"\U0001F44D\U0001F3FD", "\U0001F44D"}},
+		{"a\u200d\U0001F469", []string{"a\u200d", "\U0001F469"}},
+		{"❤\ufe0f", []string{"❤\ufe0f"}},
+		{"क\u094dष\u093f", []string{"क\u094dष\u093f"}},
+		{"क\u093fष", []string{"क\u093f", "ष"}},
+		{"ক\u09cdষ", []string{"ক\u09cdষ"}},
+		{"ക\u0d4d\u200dക", []string{"ക\u0d4d\u200dക"}},
+		{"క\u0c4d\u0c55క", []string{"క\u0c4d\u0c55క"}},
+		{"\u0600١", []string{"\u0600١"}},
+		{"กำ", []string{"กำ"}},
+		{"a\x00b", []string{"a", "\x00", "b"}},
+	}
+
+	// check : common
+	for _, v := range dataset {
+		retval := strproc.Graphemes(v.str)
+		assert.AssertEquals(t, retval, v.graphemes, "Return Value mismatch (%q).\nExpected: %q\nActual: %q", v.str, v.graphemes, retval)
+
+		cnt := strproc.GraphemeCount(v.str)
+		assert.AssertEquals(t, cnt, len(v.graphemes), "Return Value mismatch (%q).\nExpected: %v\nActual: %v", v.str, len(v.graphemes), cnt)
+	}
+}
+
+func Test_strutils_GraphemeAt(t *testing.T) {
+	t.Parallel()
+
+	str := "cafe\u0301 \U0001F1F0\U0001F1F7"
+	dataset := map[int]string{
+		0:  "c",
+		3:  "e\u0301",
+		5:  "\U0001F1F0\U0001F1F7",
+		-1: "\U0001F1F0\U0001F1F7",
+		-3: "e\u0301",
+	}
+
+	// check : common
+	for k, v := range dataset {
+		retval, err := strproc.GraphemeAt(str, k)
+		assert.AssertNil(t, err, "Error : %v", err)
+		assert.AssertEquals(t, retval, v, "Return Value mismatch (%v).\nExpected: %q\nActual: %q", k, v, retval)
+	}
+
+	// check : out of range
+	for _, v := range []int{6, 100, -7} {
+		_, err := strproc.GraphemeAt(str, v)
+		assert.AssertNotNil(t, err, "Couldn't check the `out of range` (%v)\nError : %v", v, err)
+	}
+}
+
+func Test_strutils_GraphemeTruncate(t *testing.T) {
+	t.Parallel()
+
+	dataset := []struct {
+		str    string
+		mx     int
+		tail   string
+		retval string
+	}{
+		{"abcdefg", 10, "...", "abcdefg"},
+		{"abcdefg", 7, "...", "abcdefg"},
+		{"abcdefg", 5, "...", "ab..."},
+		{"abcdefg", 3, "...", "..."},
+		{"abcdefg", 0, "", ""},
+		{"cafe\u0301s", 4, "", "cafe\u0301"},
+		{"cafe\u0301s", 4, "…", "caf…"},
+		{"\U0001F468\u200d\U0001F469\u200d\U0001F467\U0001F1F0\U0001F1F7", 1, "", "\U0001F468\u200d\U0001F469\u200d\U0001F467"},
+	}
+
+	// check : common
+	for _, v := range dataset {
+		retval, err := strproc.GraphemeTruncate(v.str, v.mx, v.tail)
+		assert.AssertNil(t, err, "Error : %v", err)
+		assert.AssertEquals(t, retval, v.retval, "Return Value mismatch (%q, %v).\nExpected: %q\nActual: %q", v.str, v.mx, v.retval, retval)
+	}
+
+	// check : not allow mx, tail
+	_, err := strproc.GraphemeTruncate("abcdefg", -1, "")
+	assert.AssertNotNil(t, err, "Couldn't check the `not allow mx`\nError : %v", err)
+
+	_, err = strproc.GraphemeTruncate("abcdefg", 2, "...")
+	assert.AssertNotNil(t, err, "Couldn't check the `not allow tail`\nError : %v", err)
+}
+
+func Test_strutils_ReverseGraphemes(t *testing.T) {
+	t.Parallel()
+
+	dataset := map[string]string{
+		"":            "",
+		"abc":         "cba",
+		"ñandu\u0301": "u\u0301dnañ",
+		"no\u0308el":  "leo\u0308n",
+		"\U0001F1FA\U0001F1F8\U0001F1EC\U0001F1E7": "\U0001F1EC\U0001F1E7\U0001F1FA\U0001F1F8",
+		"x\U0001F469\u200d\U0001F4BBy":             "y\U0001F469\u200d\U0001F4BBx",
+	}
+
+	// check : common
+	for k, v := range dataset {
+		retval := strproc.ReverseGraphemes(k)
+		assert.AssertEquals(t, retval, v, "Return Value mismatch (%q).\nExpected: %q\nActual: %q", k, v, retval)
+
+		// reverse twice
+		retval = strproc.ReverseGraphemes(retval)
+		assert.AssertEquals(t, retval, k, "Return Value mismatch (%q).\nExpected: %q\nActual: %q", v, k, retval)
+	}
+}
+
+func Test_strutils_Graphemes_Conformance(t *testing.T) {
+	t.Parallel()
+
+	// GraphemeBreakTest.txt of the Unicode character database, ÷ 0020 × 0308 ÷ 0020 ÷ # comment
+	fd, err := os.Open("testdata/GraphemeBreakTest.txt")
+	assert.AssertNil(t, err, "Error : %v", err)
+	defer fd.Close()
+
+	var cnt int
+	scanner := bufio.NewScanner(fd)
+	for no := 1; scanner.Scan(); no++ {
+		line := scanner.Text()
+		if i := strings.IndexByte(line, 35); i >= 0 { // #
+			line = line[:i]
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		var str strings.Builder
+		var cluster strings.Builder
+		var surrogate bool
+		expected := []string{}
+		for i, v := range fields {
+			switch v {
+			case "÷":
+				if i > 0 {
+					expected = append(expected, cluster.String())
+					cluster.Reset()
+				}
+			case "×":
+			default:
+				r, err := strconv.ParseUint(v, 16, 32)
+				assert.AssertNil(t, err, "Error : %v at line %v", err, no)
+
+				if r >= 0xd800 && r <= 0xdfff { // the surrogates are not the rune of the Go string
+					surrogate = true
+				}
+
+				str.WriteRune(rune(r))
+				cluster.WriteRune(rune(r))
+			}
+		}
+
+		if surrogate {
+			continue
+		}
+
+		retval := strproc.Graphemes(str.String())
+		assert.AssertEquals(t, retval, expected, "Return Value mismatch at line %v (%q).\nExpected: %q\nActual: %q", no, str.String(), expected, retval)
+		cnt++
+	}
+
+	assert.AssertNil(t, scanner.Err(), "Error : %v", scanner.Err())
+	assert.AssertTrue(t, cnt > 0, "Failure : Couldn't read the test cases")
+}
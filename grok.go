@@ -0,0 +1,234 @@
+package strutils
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// %{NAME} , %{NAME:field} , %{NAME:field:type}
+var grokReferencePattern = regexp.MustCompile(`%\{(\w+)(?::([^:{}]*))?(?::(\w+))?\}`)
+
+var grokNamePattern = regexp.MustCompile(`^\w+$`)
+
+var grokFieldPattern = regexp.MustCompile(`^[A-Za-z_]\w*$`)
+
+// grokEmbed is return the expression of the anchored regex without the anchors, for embedding
+func grokEmbed(regex *regexp.Regexp) string {
+	return strings.TrimSuffix(strings.TrimPrefix(regex.String(), "^"), "$")
+}
+
+// built-in patterns of Grok, like logstash-patterns-core (RE2 syntax, no look-around)
+var grokBuiltins = map[string]string{
+	// base
+	"USERNAME":     `[a-zA-Z0-9._-]+`,
+	"USER":         `%{USERNAME}`,
+	"INT":          `[+-]?[0-9]+`,
+	"BASE10NUM":    `[+-]?(?:[0-9]+(?:\.[0-9]+)?|\.[0-9]+)`,
+	"NUMBER":       `%{BASE10NUM}`,
+	"BASE16NUM":    `[+-]?(?:0[xX])?[0-9A-Fa-f]+`,
+	"POSINT":       `\b[1-9][0-9]*\b`,
+	"NONNEGINT":    `\b[0-9]+\b`,
+	"WORD":         `\b\w+\b`,
+	"NOTSPACE":     `\S+`,
+	"SPACE":        `\s*`,
+	"DATA":         `.*?`,
+	"GREEDYDATA":   `.*`,
+	"QUOTEDSTRING": `"(?:[^"\\]|\\.)*"|'(?:[^'\\]|\\.)*'|` + "`(?:[^`\\\\]|\\\\.)*`",
+	"QS":           `%{QUOTEDSTRING}`,
+	"UUID":         `[A-Fa-f0-9]{8}-(?:[A-Fa-f0-9]{4}-){3}[A-Fa-f0-9]{12}`,
+
+	// network
+	"MAC":      `(?:[A-Fa-f0-9]{2}[:-]){5}[A-Fa-f0-9]{2}|(?:[A-Fa-f0-9]{4}\.){2}[A-Fa-f0-9]{4}`,
+	"IPV4":     `(?:(?:25[0-5]|2[0-4][0-9]|1[0-9]{2}|[1-9]?[0-9])\.){3}(?:25[0-5]|2[0-4][0-9]|1[0-9]{2}|[1-9]?[0-9])`,
+	"IPV6":     `(?:(?:[0-9A-Fa-f]{1,4}:){6}%{IPV4}|::(?:[fF]{4}(?::0{1,4})?:)?%{IPV4}|(?:[0-9A-Fa-f]{1,4}:){1,4}:%{IPV4}|(?:[0-9A-Fa-f]{1,4}:){7}[0-9A-Fa-f]{1,4}|[0-9A-Fa-f]{1,4}:(?::[0-9A-Fa-f]{1,4}){1,6}|(?:[0-9A-Fa-f]{1,4}:){1,2}(?::[0-9A-Fa-f]{1,4}){1,5}|(?:[0-9A-Fa-f]{1,4}:){1,3}(?::[0-9A-Fa-f]{1,4}){1,4}|(?:[0-9A-Fa-f]{1,4}:){1,4}(?::[0-9A-Fa-f]{1,4}){1,3}|(?:[0-9A-Fa-f]{1,4}:){1,5}(?::[0-9A-Fa-f]{1,4}){1,2}|(?:[0-9A-Fa-f]{1,4}:){1,6}:[0-9A-Fa-f]{1,4}|(?:[0-9A-Fa-f]{1,4}:){1,7}:|:(?:(?::[0-9A-Fa-f]{1,4}){1,7}|:))(?:%[0-9A-Za-z]+)?`,
+	"IP":       `%{IPV6}|%{IPV4}`,
+	"HOSTNAME": `\b[0-9A-Za-z][0-9A-Za-z-]{0,62}(?:\.[0-9A-Za-z][0-9A-Za-z-]{0,62})*\b`,
+	"DOMAIN":   grokEmbed(domainPattern),
+	"IPORHOST": `%{IP}|%{HOSTNAME}`,
+	"HOSTPORT": `%{IPORHOST}:%{POSINT}`,
+
+	// e-mail, url
+	"EMAILADDRESS": grokEmbed(emailPattern),
+	"URI":          grokEmbed(urlPattern),
+	"URIPATH":      `(?:/[A-Za-z0-9$.+!*'(){},~:;=@#%&_\-]*)+`,
+	"URIPARAM":     `\?[A-Za-z0-9$.+!*'|(){},~@#%&/=:;_?\-\[\]<>]*`,
+	"URIPATHPARAM": `%{URIPATH}(?:%{URIPARAM})?`,
+
+	// date, time
+	"MONTH":             `\b(?:Jan(?:uary)?|Feb(?:ruary)?|Mar(?:ch)?|Apr(?:il)?|May|June?|July?|Aug(?:ust)?|Sep(?:tember)?|Oct(?:ober)?|Nov(?:ember)?|Dec(?:ember)?)\b`,
+	"MONTHNUM":          `0?[1-9]|1[0-2]`,
+	"MONTHDAY":          `0[1-9]|[12][0-9]|3[01]|[1-9]`,
+	"DAY":               `\b(?:Mon(?:day)?|Tue(?:sday)?|Wed(?:nesday)?|Thu(?:rsday)?|Fri(?:day)?|Sat(?:urday)?|Sun(?:day)?)\b`,
+	"YEAR":              `(?:\d\d){1,2}`,
+	"HOUR":              `2[0123]|[01]?[0-9]`,
+	"MINUTE":            `[0-5][0-9]`,
+	"SECOND":            `(?:[0-5]?[0-9]|60)(?:[:.,][0-9]+)?`,
+	"TIME":              `%{HOUR}:%{MINUTE}(?::%{SECOND})?`,
+	"ISO8601_TIMEZONE":  `Z|[+-]%{HOUR}(?::?%{MINUTE})`,
+	"TIMESTAMP_ISO8601": `%{YEAR}-%{MONTHNUM}-%{MONTHDAY}[T ]%{HOUR}:?%{MINUTE}(?::?%{SECOND})?(?:%{ISO8601_TIMEZONE})?`,
+	"HTTPDATE":          `%{MONTHDAY}/%{MONTH}/%{YEAR}:%{TIME} %{INT}`,
+	"SYSLOGTIMESTAMP":   `%{MONTH} +%{MONTHDAY} %{TIME}`,
+
+	// log
+	"LOGLEVEL":          `\b(?:[Aa]lert|ALERT|[Tt]race|TRACE|[Dd]ebug|DEBUG|[Nn]otice|NOTICE|[Ii]nfo(?:rmation)?|INFO(?:RMATION)?|[Ww]arn(?:ing)?|WARN(?:ING)?|[Ee]rr(?:or)?|ERR(?:OR)?|[Cc]rit(?:ical)?|CRIT(?:ICAL)?|[Ff]atal|FATAL|[Ss]evere|SEVERE|[Ee]merg(?:ency)?|EMERG(?:ENCY)?)\b`,
+	"PROG":              `[\x21-\x5a\x5c\x5e-\x7e]+`,
+	"SYSLOGPROG":        `%{PROG}(?:\[%{POSINT}\])?`,
+	"SYSLOGLINE":        `%{SYSLOGTIMESTAMP:timestamp} %{IPORHOST:logsource} %{PROG:program}(?:\[%{POSINT:pid}\])?: %{GREEDYDATA:message}`,
+	"HTTPDUSER":         `%{EMAILADDRESS}|%{USER}`,
+	"COMMONAPACHELOG":   `%{IPORHOST:clientip} %{HTTPDUSER:ident} %{HTTPDUSER:auth} \[%{HTTPDATE:timestamp}\] "(?:%{WORD:verb} %{NOTSPACE:request}(?: HTTP/%{NUMBER:httpversion})?|%{DATA:rawrequest})" %{NUMBER:response} (?:%{NUMBER:bytes}|-)`,
+	"COMBINEDAPACHELOG": `%{COMMONAPACHELOG} %{QS:referrer} %{QS:agent}`,
+}
+
+// Grok is a registry of the reusable patterns in the Logstash grok style
+// %{NAME} is expanded to the pattern, %{NAME:field} is expanded to the named group (?P<field>...)
+// %{NAME:field:type} is allowed, but the type is ignored (use RegExpUnmarshal for the typed fields)
+type Grok struct {
+	sync.RWMutex
+	patterns map[string]string
+}
+
+// NewGrok Creates and returns a Grok's pointer with the built-in patterns
+func NewGrok() *Grok {
+	g := &Grok{patterns: make(map[string]string, len(grokBuiltins))}
+	for k, v := range grokBuiltins {
+		g.patterns[k] = v
+	}
+
+	return g
+}
+
+// expand is replace the references recursively, the stack is the names in expanding
+// the not exists references are kept as is if not strict
+func (g *Grok) expand(pattern string, stack []string, strict bool) (string, error) {
+	var err error
+
+	retval := grokReferencePattern.ReplaceAllStringFunc(pattern, func(ref string) string {
+		if err != nil {
+			return ""
+		}
+
+		m := grokReferencePattern.FindStringSubmatch(ref)
+		name, field := m[1], m[2]
+
+		for no, v := range stack {
+			if v == name {
+				err = fmt.Errorf("Not allow recursive grok pattern : %v", strings.Join(append(stack[no:], name), " -> "))
+				return ""
+			}
+		}
+
+		def, ok := g.patterns[name]
+		if !ok {
+			if strict {
+				err = fmt.Errorf("Not exists grok pattern : %v", name)
+			}
+			return ref
+		}
+
+		var expanded string
+		expanded, err = g.expand(def, append(stack, name), strict)
+		if err != nil {
+			return ""
+		}
+
+		if len(field) == 0 {
+			return "(?:" + expanded + ")"
+		}
+
+		if !grokFieldPattern.MatchString(field) {
+			err = fmt.Errorf("Not allow grok field name : %v", field)
+			return ""
+		}
+
+		return "(?P<" + field + ">" + expanded + ")"
+	})
+
+	if err != nil {
+		return "", err
+	}
+
+	return retval, nil
+}
+
+// Add is define (or redefine) a pattern, the references to the not defined patterns are allowed
+// it returns an error if the name is not allowed, or the definition is recursive
+func (g *Grok) Add(name string, pattern string) error {
+	if !grokNamePattern.MatchString(name) {
+		return fmt.Errorf("Not allow grok pattern name : %v", name)
+	}
+
+	g.Lock()
+	defer g.Unlock()
+
+	old, exists := g.patterns[name]
+	g.patterns[name] = pattern
+
+	if _, err := g.expand(pattern, []string{name}, false); err != nil {
+		if exists {
+			g.patterns[name] = old
+		} else {
+			delete(g.patterns, name)
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// AddPatterns is define the patterns of the text, a definition per line (NAME pattern), like the pattern files of Logstash
+// the empty lines and the comments (#) are skipped
+func (g *Grok) AddPatterns(str string) error {
+	for no, line := range strings.Split(str, "\n") {
+		line = strings.TrimSpace(line)
+		if len(line) == 0 || line[0] == 35 { // #
+			continue
+		}
+
+		idx := strings.IndexAny(line, " \t")
+		if idx < 0 {
+			return fmt.Errorf("Not allow grok pattern definition at line %v : %q", no+1, line)
+		}
+
+		if err := g.Add(line[:idx], strings.TrimSpace(line[idx:])); err != nil {
+			return fmt.Errorf("%v at line %v", err, no+1)
+		}
+	}
+
+	return nil
+}
+
+// Names returns the names of the defined patterns, sorted
+func (g *Grok) Names() []string {
+	g.RLock()
+	defer g.RUnlock()
+
+	retval := make([]string, 0, len(g.patterns))
+	for k := range g.patterns {
+		retval = append(retval, k)
+	}
+
+	sort.Strings(retval)
+	return retval
+}
+
+// Expand is return the regular expression of the pattern with the all references expanded
+func (g *Grok) Expand(pattern string) (string, error) {
+	g.RLock()
+	defer g.RUnlock()
+
+	return g.expand(pattern, nil, true)
+}
+
+// Compile is expand and compile the pattern, the result is for RegExpNamedGroups, RegExpNamedGroupsAll and RegExpUnmarshal
+func (g *Grok) Compile(pattern string) (*regexp.Regexp, error) {
+	expr, err := g.Expand(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	return regexp.Compile(expr)
+}
@@ -0,0 +1,150 @@
+package strutils_test
+
+import (
+	"testing"
+
+	strutils "github.com/torden/go-strutil"
+)
+
+func Test_strutils_Grok(t *testing.T) {
+	t.Parallel()
+
+	grok := strutils.NewGrok()
+
+	// check : all built-in patterns
+	for _, v := range grok.Names() {
+		_, err := grok.Compile("%{" + v + "}")
+		assert.AssertNil(t, err, "Error (%v) : %v", v, err)
+	}
+
+	dataset := []struct {
+		pattern string
+		val     string
+		groups  map[string]string
+	}{
+		{`%{IP:client} %{WORD:method} %{URIPATHPARAM:path}`, "55.3.244.1 GET /index.html?a=1", map[string]string{"client": "55.3.244.1", "method": "GET", "path": "/index.html?a=1"}},
+		{`^%{IP:ip}$`, "2001:db8::ff00:42:8329", map[string]string{"ip": "2001:db8::ff00:42:8329"}},
+		{`^%{IP:ip}$`, "::ffff:192.0.2.128", map[string]string{"ip": "::ffff:192.0.2.128"}},
+		{`%{IP:ip} `, "fe80::1%eth0 x", map[string]string{"ip": "fe80::1%eth0"}},
+		{`^%{IPORHOST:host}:%{POSINT:port}$`, "www.example.com:8080", map[string]string{"host": "www.example.com", "port": "8080"}},
+		{`<%{EMAILADDRESS:email}>`, "To: <a.b+c@golang.org>", map[string]string{"email": "a.b+c@golang.org"}},
+		{`^%{DOMAIN:domain}$`, "한국.kr", map[string]string{"domain": "한국.kr"}},
+		{`%{URI:url}`, "see https://golang.org/doc", map[string]string{"url": "https://golang.org/doc"}},
+		{`id=%{UUID:id}`, "id=123e4567-e89b-12d3-a456-426614174000", map[string]string{"id": "123e4567-e89b-12d3-a456-426614174000"}},
+		{`%{NUMBER:a} %{NUMBER:b:float} %{INT:c:int}`, "-1.5 .25 +42", map[string]string{"a": "-1.5", "b": ".25", "c": "+42"}},
+		{`%{QS:q} %{MAC:mac}`, `"say \"hi\"" 00:1a:2B:3c:4d:5e`, map[string]string{"q": `"say \"hi\""`, "mac": "00:1a:2B:3c:4d:5e"}},
+		{`%{TIMESTAMP_ISO8601:ts} %{LOGLEVEL:level}`, "2020-02-25T13:55:36.123+09:00 WARN", map[string]string{"ts": "2020-02-25T13:55:36.123+09:00", "level": "WARN"}},
+		{`%{SYSLOGLINE}`, "Oct  1 12:34:56 web01 sshd[123]: Accepted publickey for git", map[string]string{"timestamp": "Oct  1 12:34:56", "logsource": "web01", "program": "sshd", "pid": "123", "message": "Accepted publickey for git"}},
+		{`%{COMBINEDAPACHELOG}`, `127.0.0.1 - frank [10/Oct/2000:13:55:36 -0700] "GET /apache_pb.gif HTTP/1.0" 200 2326 "http://www.example.com/start.html" "Mozilla/4.08"`, map[string]string{
+			"clientip": "127.0.0.1", "ident": "-", "auth": "frank", "timestamp": "10/Oct/2000:13:55:36 -0700", "verb": "GET", "request": "/apache_pb.gif",
+			"httpversion": "1.0", "response": "200", "bytes": "2326", "referrer": `"http://www.example.com/start.html"`, "agent": `"Mozilla/4.08"`,
+		}},
+	}
+
+	// check : common
+	for _, v := range dataset {
+		regex, err := grok.Compile(v.pattern)
+		assert.AssertNil(t, err, "Error (%v) : %v", v.pattern, err)
+
+		retval, err := strproc.RegExpNamedGroups(regex, v.val)
+		assert.AssertNil(t, err, "Error (%v) : %v", v.pattern, err)
+		for name, val := range v.groups {
+			assert.AssertEquals(t, retval[name], val, "Return Value mismatch (%v, %v).\nExpected: %v\nActual: %v", v.pattern, name, val, retval[name])
+		}
+	}
+
+	// check : not matched
+	for k, v := range map[string]string{
+		`^%{IPV4}$`:   "256.1.1.1",
+		`^%{IP}$`:     "1:2:3:4:5:6:7:8:9",
+		`^%{UUID}$`:   "123e4567-e89b-12d3-a456",
+		`^%{MONTH}$`:  "Foo",
+		`^%{POSINT}$`: "0",
+	} {
+		regex, err := grok.Compile(k)
+		assert.AssertNil(t, err, "Error (%v) : %v", k, err)
+		assert.AssertFalse(t, regex.MatchString(v), "Couldn't check the `not matched` (%v, %v)", k, v)
+	}
+}
+
+func Test_strutils_Grok_Add(t *testing.T) {
+	t.Parallel()
+
+	grok := strutils.NewGrok()
+
+	// check : custom, redefine, not defined yet
+	err := grok.Add("POSTFIX_QUEUEID", `[0-9A-F]{10,11}`)
+	assert.AssertNil(t, err, "Error : %v", err)
+
+	err = grok.Add("MAILLOG", `%{SYSLOGTIMESTAMP:ts} %{HOSTNAME:host} postfix/%{WORD:proc}\[%{POSINT:pid}\]: %{POSTFIX_QUEUEID:queueid}: %{POSTFIX_REST:rest}`)
+	assert.AssertNil(t, err, "Error : %v", err)
+
+	_, err = grok.Compile(`%{MAILLOG}`)
+	assert.AssertNotNil(t, err, "Couldn't check the `not exists`\nError : %v", err)
+
+	err = grok.AddPatterns("# postfix\n\nPOSTFIX_REST %{GREEDYDATA}\nWORD [a-z]+\n")
+	assert.AssertNil(t, err, "Error : %v", err)
+
+	regex, err := grok.Compile(`%{MAILLOG}`)
+	assert.AssertNil(t, err, "Error : %v", err)
+
+	retval, err := strproc.RegExpNamedGroups(regex, "Jan  2 03:04:05 mx1 postfix/smtpd[99]: 4BE6F1F8B5: client=a")
+	assert.AssertNil(t, err, "Error : %v", err)
+	assert.AssertEquals(t, retval["queueid"], "4BE6F1F8B5", "Return Value mismatch.\nExpected: %v\nActual: %v", "4BE6F1F8B5", retval["queueid"])
+	assert.AssertEquals(t, retval["rest"], "client=a", "Return Value mismatch.\nExpected: %v\nActual: %v", "client=a", retval["rest"])
+
+	// check : recursive
+	recursive := []struct {
+		name    string
+		pattern string
+	}{
+		{"SELF", `a%{SELF}`},
+		{"WORD", `%{NOTSPACE}|%{USER}x%{WORD}`},
+		{"A_1", `%{B_1:b}`},
+		{"B_1", `%{C_1}`},
+		{"C_1", `x|%{A_1}`},
+	}
+
+	for no, v := range recursive {
+		err = grok.Add(v.name, v.pattern)
+		if no < 2 || no == 4 {
+			assert.AssertNotNil(t, err, "Couldn't check the `recursive` (%v)\nError : %v", v.name, err)
+		} else {
+			assert.AssertNil(t, err, "Error : %v", err)
+		}
+	}
+
+	// the failed definition is not changed
+	expr, err := grok.Expand(`%{WORD}`)
+	assert.AssertNil(t, err, "Error : %v", err)
+	assert.AssertEquals(t, expr, "(?:[a-z]+)", "Return Value mismatch.\nExpected: %v\nActual: %v", "(?:[a-z]+)", expr)
+
+	_, err = grok.Expand(`%{SELF}`)
+	assert.AssertNotNil(t, err, "Couldn't check the `recursive`\nError : %v", err)
+
+	// check : not allow name, field, definition
+	err = grok.Add("NOT-ALLOW", `x`)
+	assert.AssertNotNil(t, err, "Couldn't check the `not allow name`\nError : %v", err)
+
+	_, err = grok.Compile(`%{WORD:not-allow}`)
+	assert.AssertNotNil(t, err, "Couldn't check the `not allow field`\nError : %v", err)
+
+	err = grok.AddPatterns("NODEFINITION")
+	assert.AssertNotNil(t, err, "Couldn't check the `not allow definition`\nError : %v", err)
+
+	_, err = grok.Compile(`%{WORD:a}(`)
+	assert.AssertNotNil(t, err, "Couldn't check the `regexp.Compile`\nError : %v", err)
+
+	// check : RegExpUnmarshal
+	var line struct {
+		Client string `re:"client"`
+		Bytes  int    `re:"bytes"`
+	}
+
+	regex, err = grok.Compile(`%{IP:client} %{INT:bytes:int}`)
+	assert.AssertNil(t, err, "Error : %v", err)
+
+	err = strproc.RegExpUnmarshal(regex, "10.0.0.1 512", &line)
+	assert.AssertNil(t, err, "Error : %v", err)
+	assert.AssertEquals(t, line.Bytes, 512, "Return Value mismatch.\nExpected: %v\nActual: %v", 512, line.Bytes)
+}
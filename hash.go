@@ -0,0 +1,267 @@
+package strutils
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"hash/adler32"
+	"hash/crc32"
+	"hash/crc64"
+	"hash/fnv"
+	"io"
+	"os"
+)
+
+// Hash algorithm control for Hash, FileHash
+const (
+	_                   = uint8(iota)
+	HashMD5             // MD5 (RFC 1321), NOTE : not for the integrity check
+	HashSHA1            // SHA-1 (FIPS 180-4), NOTE : not for the integrity check
+	HashSHA224          // SHA-224
+	HashSHA256          // SHA-256
+	HashSHA384          // SHA-384
+	HashSHA512          // SHA-512
+	HashSHA512_256      // SHA-512/256
+	HashCRC32IEEE       // CRC-32 , IEEE polynomial (same as zip, gzip, png)
+	HashCRC32Castagnoli // CRC-32C , Castagnoli polynomial (same as iSCSI, ext4)
+	HashCRC64ISO        // CRC-64 , ISO polynomial
+	HashCRC64ECMA       // CRC-64 , ECMA polynomial (same as xz)
+	HashAdler32         // Adler-32 (RFC 1950)
+	HashFNV32           // FNV-1 32-bit
+	HashFNV32a          // FNV-1a 32-bit
+	HashFNV64           // FNV-1 64-bit
+	HashFNV64a          // FNV-1a 64-bit
+	HashFNV128          // FNV-1 128-bit
+	HashFNV128a         // FNV-1a 128-bit
+)
+
+// Hash output control for Hash, FileHash
+const (
+	HashOutputHex    = uint8(iota) // lower case hex (default)
+	HashOutputBase64               // standard base64 with padding (RFC 4648)
+	HashOutputRaw                  // raw bytes of the digest
+)
+
+var (
+	crc32CastagnoliTable = crc32.MakeTable(crc32.Castagnoli)
+	crc64ISOTable        = crc64.MakeTable(crc64.ISO)
+	crc64ECMATable       = crc64.MakeTable(crc64.ECMA)
+)
+
+type hashAlgorithm struct {
+	name string
+	new  func() hash.Hash
+}
+
+// registry of the hash algorithms
+var hashAlgorithms = map[uint8]hashAlgorithm{
+	HashMD5:             {"MD5", md5.New},
+	HashSHA1:            {"SHA1", sha1.New},
+	HashSHA224:          {"SHA224", sha256.New224},
+	HashSHA256:          {"SHA256", sha256.New},
+	HashSHA384:          {"SHA384", sha512.New384},
+	HashSHA512:          {"SHA512", sha512.New},
+	HashSHA512_256:      {"SHA512/256", sha512.New512_256},
+	HashCRC32IEEE:       {"CRC32", func() hash.Hash { return crc32.NewIEEE() }},
+	HashCRC32Castagnoli: {"CRC32C", func() hash.Hash { return crc32.New(crc32CastagnoliTable) }},
+	HashCRC64ISO:        {"CRC64ISO", func() hash.Hash { return crc64.New(crc64ISOTable) }},
+	HashCRC64ECMA:       {"CRC64ECMA", func() hash.Hash { return crc64.New(crc64ECMATable) }},
+	HashAdler32:         {"ADLER32", func() hash.Hash { return adler32.New() }},
+	HashFNV32:           {"FNV32", func() hash.Hash { return fnv.New32() }},
+	HashFNV32a:          {"FNV32A", func() hash.Hash { return fnv.New32a() }},
+	HashFNV64:           {"FNV64", func() hash.Hash { return fnv.New64() }},
+	HashFNV64a:          {"FNV64A", func() hash.Hash { return fnv.New64a() }},
+	HashFNV128:          {"FNV128", fnv.New128},
+	HashFNV128a:         {"FNV128A", fnv.New128a},
+}
+
+// newHash is return a new hash.Hash of the algorithm
+func (s *StringProc) newHash(algo uint8) (hash.Hash, error) {
+	alg, ok := hashAlgorithms[algo]
+	if !ok {
+		return nil, fmt.Errorf("Not allow algo parameter : %v", algo)
+	}
+
+	return alg.new(), nil
+}
+
+// encodeHash is return the digest in the output format
+func (s *StringProc) encodeHash(sum []byte, output []uint8) (string, error) {
+	if len(output) > 1 {
+		return "", fmt.Errorf("Not allow output parameter : %v", output)
+	}
+
+	out := HashOutputHex
+	if len(output) == 1 {
+		out = output[0]
+	}
+
+	switch out {
+	case HashOutputHex:
+		return hex.EncodeToString(sum), nil
+	case HashOutputBase64:
+		return base64.StdEncoding.EncodeToString(sum), nil
+	case HashOutputRaw:
+		return string(sum), nil
+	}
+
+	return "", fmt.Errorf("Not allow output parameter : %v", out)
+}
+
+// Hash is checksum of the string with the algorithm, the output is hex if not given
+func (s *StringProc) Hash(str string, algo uint8, output ...uint8) (string, error) {
+	h, err := s.newHash(algo)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := io.WriteString(h, str); err != nil {
+		return "", err
+	}
+
+	return s.encodeHash(h.Sum(nil), output)
+}
+
+// FileHash is checksum of the file with the algorithm, the output is hex if not given
+func (s *StringProc) FileHash(filepath string, algo uint8, output ...uint8) (string, error) {
+	h, err := s.newHash(algo)
+	if err != nil {
+		return "", err
+	}
+
+	fd, err := os.Open(filepath)
+	if err != nil {
+		return "", err
+	}
+
+	defer s.closeFd(fd)
+
+	if _, err := io.Copy(h, fd); err != nil {
+		return "", err
+	}
+
+	return s.encodeHash(h.Sum(nil), output)
+}
+
+// HashOptions is the options of ReaderHashes, FileHashes
+type HashOptions struct {
+	Output   uint8                         // output format, HashOutputHex if not given
+	Size     int64                         // total bytes of the reader for Progress, 0 if unknown (FileHashes uses the file size)
+	Progress func(done int64, total int64) // called after each read, total is 0 if unknown
+}
+
+// size of the read buffer of ReaderHashes
+const hashBufferSize = 64 * 1024
+
+// ReaderHashes is checksums of the reader with the algorithms in one read pass
+// the reading is stopped when the ctx is canceled, the result map is algo => digest
+// NOTE : the algos are written through an io.MultiWriter, the same algo is computed once
+func (s *StringProc) ReaderHashes(ctx context.Context, r io.Reader, algos []uint8, opts *HashOptions) (map[uint8]string, error) {
+	if len(algos) == 0 {
+		return nil, fmt.Errorf("Not allow algos parameter : %v", algos)
+	}
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if opts == nil {
+		opts = &HashOptions{}
+	}
+
+	hashes := make(map[uint8]hash.Hash, len(algos))
+	writers := make([]io.Writer, 0, len(algos))
+	for _, v := range algos {
+		if _, ok := hashes[v]; ok {
+			continue
+		}
+
+		h, err := s.newHash(v)
+		if err != nil {
+			return nil, err
+		}
+
+		hashes[v] = h
+		writers = append(writers, h)
+	}
+
+	// check the output before reading
+	if _, err := s.encodeHash(nil, []uint8{opts.Output}); err != nil {
+		return nil, err
+	}
+
+	w := io.MultiWriter(writers...)
+	buf := make([]byte, hashBufferSize) // prealloca
+	done := int64(0)
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		n, err := r.Read(buf)
+		if n > 0 {
+			if _, werr := w.Write(buf[:n]); werr != nil {
+				return nil, werr
+			}
+
+			done += int64(n)
+			if opts.Progress != nil {
+				opts.Progress(done, opts.Size)
+			}
+		}
+
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	retval := make(map[uint8]string, len(hashes))
+	for k, h := range hashes {
+		sum, err := s.encodeHash(h.Sum(nil), []uint8{opts.Output})
+		if err != nil {
+			return nil, err
+		}
+
+		retval[k] = sum
+	}
+
+	return retval, nil
+}
+
+// FileHashes is checksums of the file with the algorithms in one read pass
+// the reading is stopped when the ctx is canceled, the result map is algo => digest
+func (s *StringProc) FileHashes(ctx context.Context, filepath string, algos []uint8, opts *HashOptions) (map[uint8]string, error) {
+	fd, err := os.Open(filepath)
+	if err != nil {
+		return nil, err
+	}
+
+	defer s.closeFd(fd)
+
+	fopts := HashOptions{}
+	if opts != nil {
+		fopts = *opts
+	}
+
+	if fopts.Size == 0 {
+		st, err := fd.Stat()
+		if err != nil {
+			return nil, err
+		}
+
+		fopts.Size = st.Size()
+	}
+
+	return s.ReaderHashes(ctx, fd, algos, &fopts)
+}
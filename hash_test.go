@@ -0,0 +1,176 @@
+package strutils_test
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	strutils "github.com/torden/go-strutil"
+)
+
+func Test_strutils_Hash(t *testing.T) {
+	t.Parallel()
+
+	dataset := map[uint8]string{
+		strutils.HashMD5:             "25f9e794323b453885f5181f1b624d0b",
+		strutils.HashSHA1:            "f7c3bc1d808e04732adf679965ccc34ca7ae3441",
+		strutils.HashSHA224:          "9b3e61bf29f17c75572fae2e86e17809a4513d07c8a18152acf34521",
+		strutils.HashSHA256:          "15e2b0d3c33891ebb0f1ef609ec419420c20e320ce94c65fbc8c3312448eb225",
+		strutils.HashSHA384:          "eb455d56d2c1a69de64e832011f3393d45f3fa31d6842f21af92d2fe469c499da5e3179847334a18479c8d1dedea1be3",
+		strutils.HashSHA512:          "d9e6762dd1c8eaf6d61b3c6192fc408d4d6d5f1176d0c29169bc24e71c3f274ad27fcd5811b313d681f7e55ec02d73d499c95455b6b5bb503acf574fba8ffe85",
+		strutils.HashSHA512_256:      "1877345237853a31ad79e14c1fcb0ddcd3df9973b61af7f906e4b4d052cc9416",
+		strutils.HashCRC32IEEE:       "cbf43926",
+		strutils.HashCRC32Castagnoli: "e3069283",
+		strutils.HashCRC64ISO:        "b90956c775a41001",
+		strutils.HashCRC64ECMA:       "995dc9bbdf1939fa",
+		strutils.HashAdler32:         "091e01de",
+		strutils.HashFNV32:           "24148816",
+		strutils.HashFNV32a:          "bb86b11c",
+		strutils.HashFNV64:           "a72ffc362bf916d6",
+		strutils.HashFNV64a:          "06d5573923c6cdfc",
+		strutils.HashFNV128:          "8bea2c73be03b30fd4142fb1ec2c2066",
+		strutils.HashFNV128a:         "da2d42a08d04e4585dd325117f71d504",
+	}
+
+	// check : common
+	for k, v := range dataset {
+		retval, err := strproc.Hash("123456789", k)
+		assert.AssertNil(t, err, "Error : %v", err)
+		assert.AssertEquals(t, retval, v, "Return Value mismatch (%v).\nExpected: %v\nActual: %v", k, v, retval)
+	}
+
+	// check : output
+	retval, err := strproc.Hash("123456789", strutils.HashSHA256, strutils.HashOutputBase64)
+	assert.AssertNil(t, err, "Error : %v", err)
+	assert.AssertEquals(t, retval, "FeKw08M4keuw8e9gnsQZQgwg4yDOlMZfvIwzEkSOsiU=", "Return Value mismatch.\nExpected: %v\nActual: %v", "FeKw08M4keuw8e9gnsQZQgwg4yDOlMZfvIwzEkSOsiU=", retval)
+
+	retval, err = strproc.Hash("123456789", strutils.HashCRC32IEEE, strutils.HashOutputRaw)
+	assert.AssertNil(t, err, "Error : %v", err)
+	assert.AssertEquals(t, retval, "\xcb\xf4\x39\x26", "Return Value mismatch.\nExpected: %q\nActual: %q", "\xcb\xf4\x39\x26", retval)
+
+	// check : not allow algo, output
+	_, err = strproc.Hash("123456789", 0)
+	assert.AssertNotNil(t, err, "Couldn't check the `not allow algo`\nError : %v", err)
+
+	_, err = strproc.Hash("123456789", strutils.HashSHA256, 9)
+	assert.AssertNotNil(t, err, "Couldn't check the `not allow output`\nError : %v", err)
+
+	_, err = strproc.Hash("123456789", strutils.HashSHA256, strutils.HashOutputHex, strutils.HashOutputRaw)
+	assert.AssertNotNil(t, err, "Couldn't check the `not allow output`\nError : %v", err)
+}
+
+func Test_strutils_FileHash(t *testing.T) {
+	t.Parallel()
+
+	dataset := map[uint8]string{
+		strutils.HashMD5:    "64e17a4e1c96bbfce57ab19cd0153e6a",
+		strutils.HashSHA1:   "5356ce931f1e8f9d33f205291821cc050ab66b7d",
+		strutils.HashSHA256: "b6c6b249baaaaac1cce2217ffdd4974d8d47b372382c464f7cee71d0d619aa8f",
+	}
+
+	// check : common
+	for k, v := range dataset {
+		retval, err := strproc.FileHash("./LICENSE", k)
+		assert.AssertNil(t, err, "Error : %v", err)
+		assert.AssertEquals(t, retval, v, "Return Value mismatch (%v).\nExpected: %v\nActual: %v", k, v, retval)
+	}
+
+	// check : os.Open
+	_, err := strproc.FileHash("./HELLO_GOLANG", strutils.HashSHA256)
+	assert.AssertNotNil(t, err, "Couldn't check the `os.Open`\nError : %v", err)
+
+	// check : not allow algo
+	_, err = strproc.FileHash("./LICENSE", 100)
+	assert.AssertNotNil(t, err, "Couldn't check the `not allow algo`\nError : %v", err)
+}
+
+func Test_strutils_ReaderHashes(t *testing.T) {
+	t.Parallel()
+
+	algos := []uint8{strutils.HashMD5, strutils.HashSHA256, strutils.HashSHA512, strutils.HashSHA256}
+
+	// check : common
+	data := strings.Repeat("0123456789", 20000)
+	var done, total int64
+	var called int
+	opts := &strutils.HashOptions{
+		Size: int64(len(data)),
+		Progress: func(d int64, t int64) {
+			done, total = d, t
+			called++
+		},
+	}
+
+	retval, err := strproc.ReaderHashes(context.Background(), strings.NewReader(data), algos, opts)
+	assert.AssertNil(t, err, "Error : %v", err)
+	assert.AssertEquals(t, len(retval), 3, "Return Value mismatch.\nExpected: %v\nActual: %v", 3, len(retval))
+	assert.AssertTrue(t, called > 1, "Couldn't check the `progress` : %v", called)
+	assert.AssertEquals(t, done, int64(len(data)), "Return Value mismatch.\nExpected: %v\nActual: %v", len(data), done)
+	assert.AssertEquals(t, total, int64(len(data)), "Return Value mismatch.\nExpected: %v\nActual: %v", len(data), total)
+
+	for k, v := range retval {
+		sum, err := strproc.Hash(data, k)
+		assert.AssertNil(t, err, "Error : %v", err)
+		assert.AssertEquals(t, v, sum, "Return Value mismatch (%v).\nExpected: %v\nActual: %v", k, sum, v)
+	}
+
+	// check : output
+	retval, err = strproc.ReaderHashes(context.TODO(), strings.NewReader("123456789"), []uint8{strutils.HashSHA256}, &strutils.HashOptions{Output: strutils.HashOutputBase64})
+	assert.AssertNil(t, err, "Error : %v", err)
+	assert.AssertEquals(t, retval[strutils.HashSHA256], "FeKw08M4keuw8e9gnsQZQgwg4yDOlMZfvIwzEkSOsiU=", "Return Value mismatch.\nExpected: %v\nActual: %v", "FeKw08M4keuw8e9gnsQZQgwg4yDOlMZfvIwzEkSOsiU=", retval)
+
+	// check : canceled
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = strproc.ReaderHashes(ctx, strings.NewReader(data), algos, nil)
+	assert.AssertEquals(t, err, context.Canceled, "Couldn't check the `canceled`\nError : %v", err)
+
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	called = 0
+	_, err = strproc.ReaderHashes(ctx, strings.NewReader(data), algos, &strutils.HashOptions{
+		Progress: func(d int64, t int64) {
+			called++
+			cancel()
+		},
+	})
+	assert.AssertEquals(t, err, context.Canceled, "Couldn't check the `canceled`\nError : %v", err)
+	assert.AssertEquals(t, called, 1, "Return Value mismatch.\nExpected: %v\nActual: %v", 1, called)
+
+	// check : not allow algos, output
+	_, err = strproc.ReaderHashes(context.Background(), strings.NewReader(data), nil, nil)
+	assert.AssertNotNil(t, err, "Couldn't check the `not allow algos`\nError : %v", err)
+
+	_, err = strproc.ReaderHashes(context.Background(), strings.NewReader(data), []uint8{strutils.HashMD5, 0}, nil)
+	assert.AssertNotNil(t, err, "Couldn't check the `not allow algos`\nError : %v", err)
+
+	_, err = strproc.ReaderHashes(context.Background(), strings.NewReader(data), algos, &strutils.HashOptions{Output: 9})
+	assert.AssertNotNil(t, err, "Couldn't check the `not allow output`\nError : %v", err)
+}
+
+func Test_strutils_FileHashes(t *testing.T) {
+	t.Parallel()
+
+	var total int64
+	opts := &strutils.HashOptions{
+		Progress: func(d int64, t int64) {
+			total = t
+		},
+	}
+
+	// check : common
+	retval, err := strproc.FileHashes(context.Background(), "./LICENSE", []uint8{strutils.HashMD5, strutils.HashSHA1, strutils.HashSHA256}, opts)
+	assert.AssertNil(t, err, "Error : %v", err)
+	assert.AssertEquals(t, retval[strutils.HashMD5], "64e17a4e1c96bbfce57ab19cd0153e6a", "Return Value mismatch.\nExpected: %v\nActual: %v", "64e17a4e1c96bbfce57ab19cd0153e6a", retval)
+	assert.AssertEquals(t, retval[strutils.HashSHA1], "5356ce931f1e8f9d33f205291821cc050ab66b7d", "Return Value mismatch.\nExpected: %v\nActual: %v", "5356ce931f1e8f9d33f205291821cc050ab66b7d", retval)
+	assert.AssertEquals(t, retval[strutils.HashSHA256], "b6c6b249baaaaac1cce2217ffdd4974d8d47b372382c464f7cee71d0d619aa8f", "Return Value mismatch.\nExpected: %v\nActual: %v", "b6c6b249baaaaac1cce2217ffdd4974d8d47b372382c464f7cee71d0d619aa8f", retval)
+
+	fi, err := os.Stat("./LICENSE")
+	assert.AssertNil(t, err, "Error : %v", err)
+	assert.AssertEquals(t, total, fi.Size(), "Return Value mismatch.\nExpected: %v\nActual: %v", fi.Size(), total)
+
+	// check : os.Open
+	_, err = strproc.FileHashes(context.Background(), "./HELLO_GOLANG", []uint8{strutils.HashSHA256}, nil)
+	assert.AssertNotNil(t, err, "Couldn't check the `os.Open`\nError : %v", err)
+}
@@ -0,0 +1,148 @@
+package strutils
+
+import (
+	"crypto/hmac"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+)
+
+// newHMAC is return a new keyed hash.Hash of the algorithm
+// NOTE : only the cryptographic hash algorithms (MD5, SHA-1, SHA-2), not the checksums (CRC, Adler-32, FNV)
+func (s *StringProc) newHMAC(algo uint8, key string) (hash.Hash, error) {
+	if algo < HashMD5 || algo > HashSHA512_256 {
+		return nil, fmt.Errorf("Not allow algo parameter : %v", algo)
+	}
+
+	return hmac.New(hashAlgorithms[algo].new, []byte(key)), nil
+}
+
+// decodeHash is reverse of encodeHash
+func (s *StringProc) decodeHash(str string, output []uint8) ([]byte, error) {
+	if len(output) > 1 {
+		return nil, fmt.Errorf("Not allow output parameter : %v", output)
+	}
+
+	out := HashOutputHex
+	if len(output) == 1 {
+		out = output[0]
+	}
+
+	switch out {
+	case HashOutputHex:
+		return hex.DecodeString(str)
+	case HashOutputBase64:
+		return base64.StdEncoding.DecodeString(str)
+	case HashOutputRaw:
+		return []byte(str), nil
+	}
+
+	return nil, fmt.Errorf("Not allow output parameter : %v", out)
+}
+
+// HMAC is keyed-hash message authentication code (RFC 2104) of the string with the algorithm, the output is hex if not given
+func (s *StringProc) HMAC(str string, key string, algo uint8, output ...uint8) (string, error) {
+	h, err := s.newHMAC(algo, key)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := io.WriteString(h, str); err != nil {
+		return "", err
+	}
+
+	return s.encodeHash(h.Sum(nil), output)
+}
+
+// ReaderHMAC is keyed-hash message authentication code (RFC 2104) of the reader with the algorithm, the output is hex if not given
+func (s *StringProc) ReaderHMAC(r io.Reader, key string, algo uint8, output ...uint8) (string, error) {
+	h, err := s.newHMAC(algo, key)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := io.Copy(h, r); err != nil {
+		return "", err
+	}
+
+	return s.encodeHash(h.Sum(nil), output)
+}
+
+// FileHMAC is keyed-hash message authentication code (RFC 2104) of the file with the algorithm, the output is hex if not given
+func (s *StringProc) FileHMAC(filepath string, key string, algo uint8, output ...uint8) (string, error) {
+	fd, err := os.Open(filepath)
+	if err != nil {
+		return "", err
+	}
+
+	defer s.closeFd(fd)
+
+	return s.ReaderHMAC(fd, key, algo, output...)
+}
+
+// VerifyHMAC is compare the mac with the HMAC of the string in constant time, the mac is in the output format (hex if not given)
+// the malformed mac is not matched (false) without an error
+func (s *StringProc) VerifyHMAC(str string, key string, algo uint8, mac string, output ...uint8) (bool, error) {
+	h, err := s.newHMAC(algo, key)
+	if err != nil {
+		return false, err
+	}
+
+	if _, err := io.WriteString(h, str); err != nil {
+		return false, err
+	}
+
+	expected, err := s.decodeHash(mac, output)
+	if err != nil {
+		if _, ok := err.(hex.InvalidByteError); ok || err == hex.ErrLength {
+			return false, nil
+		}
+
+		if _, ok := err.(base64.CorruptInputError); ok {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return hmac.Equal(h.Sum(nil), expected), nil
+}
+
+// HKDF is derive a key of the length from the secret by HMAC-based extract-and-expand key derivation function (RFC 5869)
+// the salt and the info are optional (empty), the length is up to 255 * the digest size of the algorithm
+func (s *StringProc) HKDF(secret string, salt string, info string, length int, algo uint8, output ...uint8) (string, error) {
+	h, err := s.newHMAC(algo, salt)
+	if err != nil {
+		return "", err
+	}
+
+	if length <= 0 || length > 255*h.Size() {
+		return "", fmt.Errorf("Not allow length parameter : %v", length)
+	}
+
+	// extract, PRK = HMAC-Hash(salt, IKM)
+	// NOTE : the empty salt is same as zeros of the digest size (RFC 5869 2.2), the HMAC key is padded with zeros
+	if _, err := io.WriteString(h, secret); err != nil {
+		return "", err
+	}
+
+	prk := h.Sum(nil)
+
+	// expand, T(N) = HMAC-Hash(PRK, T(N-1) | info | N)
+	retval := make([]byte, 0, length+h.Size()) // prealloca
+	var t []byte
+	for i := 1; len(retval) < length; i++ {
+		h, _ = s.newHMAC(algo, string(prk))
+		h.Write(t)
+		io.WriteString(h, info)
+		h.Write([]byte{byte(i)})
+
+		t = h.Sum(nil)
+		retval = append(retval, t...)
+	}
+
+	return s.encodeHash(retval[:length], output)
+}
@@ -0,0 +1,131 @@
+package strutils_test
+
+import (
+	"strings"
+	"testing"
+
+	strutils "github.com/torden/go-strutil"
+)
+
+func Test_strutils_HMAC(t *testing.T) {
+	t.Parallel()
+
+	// RFC 2104, RFC 2202, RFC 4231 (test case 2)
+	dataset := map[uint8]string{
+		strutils.HashMD5:    "750c783e6ab0b503eaa86e310a5db738",
+		strutils.HashSHA1:   "effcdf6ae5eb2fa2d27416d5f184df9c259a7c79",
+		strutils.HashSHA224: "a30e01098bc6dbbf45690f3a7e9e6d0f8bbea2a39e6148008fd05e44",
+		strutils.HashSHA256: "5bdcc146bf60754e6a042426089575c75a003f089d2739839dec58b964ec3843",
+		strutils.HashSHA384: "af45d2e376484031617f78d2b58a6b1b9c7ef464f5a01b47e42ec3736322445e8e2240ca5e69e2c78b3239ecfab21649",
+		strutils.HashSHA512: "164b7a7bfcf819e2e395fbe73b56e0a387bd64222e831fd610270cd7ea2505549758bf75c05a994a6d034f65f8f0e6fdcaeab1a34d4a6b4b636e070a38bce737",
+	}
+
+	// check : common
+	for k, v := range dataset {
+		retval, err := strproc.HMAC("what do ya want for nothing?", "Jefe", k)
+		assert.AssertNil(t, err, "Error : %v", err)
+		assert.AssertEquals(t, retval, v, "Return Value mismatch (%v).\nExpected: %v\nActual: %v", k, v, retval)
+
+		retval, err = strproc.ReaderHMAC(strings.NewReader("what do ya want for nothing?"), "Jefe", k)
+		assert.AssertNil(t, err, "Error : %v", err)
+		assert.AssertEquals(t, retval, v, "Return Value mismatch (%v).\nExpected: %v\nActual: %v", k, v, retval)
+	}
+
+	// check : output
+	retval, err := strproc.HMAC("what do ya want for nothing?", "Jefe", strutils.HashSHA256, strutils.HashOutputBase64)
+	assert.AssertNil(t, err, "Error : %v", err)
+	assert.AssertEquals(t, retval, "W9zBRr9gdU5qBCQmCJV1x1oAPwidJzmDnexYuWTsOEM=", "Return Value mismatch.\nExpected: %v\nActual: %v", "W9zBRr9gdU5qBCQmCJV1x1oAPwidJzmDnexYuWTsOEM=", retval)
+
+	// check : file
+	retval, err = strproc.FileHMAC("./LICENSE", "key", strutils.HashSHA256)
+	assert.AssertNil(t, err, "Error : %v", err)
+	assert.AssertEquals(t, len(retval), 64, "Return Value mismatch.\nExpected: %v\nActual: %v", 64, len(retval))
+
+	_, err = strproc.FileHMAC("./HELLO_GOLANG", "key", strutils.HashSHA256)
+	assert.AssertNotNil(t, err, "Couldn't check the `os.Open`\nError : %v", err)
+
+	// check : not allow algo (not the cryptographic hash)
+	for _, v := range []uint8{0, strutils.HashCRC32IEEE, strutils.HashFNV64a, 100} {
+		_, err = strproc.HMAC("abc", "key", v)
+		assert.AssertNotNil(t, err, "Couldn't check the `not allow algo` (%v)\nError : %v", v, err)
+	}
+}
+
+func Test_strutils_VerifyHMAC(t *testing.T) {
+	t.Parallel()
+
+	mac := "5bdcc146bf60754e6a042426089575c75a003f089d2739839dec58b964ec3843"
+	dataset := []struct {
+		str    string
+		key    string
+		mac    string
+		output uint8
+		ok     bool
+	}{
+		{"what do ya want for nothing?", "Jefe", mac, strutils.HashOutputHex, true},
+		{"what do ya want for nothing?", "Jefe", strings.ToUpper(mac), strutils.HashOutputHex, true},
+		{"what do ya want for nothing?", "Jefe", "W9zBRr9gdU5qBCQmCJV1x1oAPwidJzmDnexYuWTsOEM=", strutils.HashOutputBase64, true},
+		{"what do ya want for nothing!", "Jefe", mac, strutils.HashOutputHex, false},
+		{"what do ya want for nothing?", "jefe", mac, strutils.HashOutputHex, false},
+		{"what do ya want for nothing?", "Jefe", mac[:62], strutils.HashOutputHex, false},
+		{"what do ya want for nothing?", "Jefe", mac[:63], strutils.HashOutputHex, false},
+		{"what do ya want for nothing?", "Jefe", "zz" + mac[2:], strutils.HashOutputHex, false},
+		{"what do ya want for nothing?", "Jefe", "!!!", strutils.HashOutputBase64, false},
+		{"what do ya want for nothing?", "Jefe", "", strutils.HashOutputRaw, false},
+	}
+
+	// check : common
+	for _, v := range dataset {
+		retval, err := strproc.VerifyHMAC(v.str, v.key, strutils.HashSHA256, v.mac, v.output)
+		assert.AssertNil(t, err, "Error : %v", err)
+		assert.AssertEquals(t, retval, v.ok, "Return Value mismatch (%v, %v).\nExpected: %v\nActual: %v", v.str, v.mac, v.ok, retval)
+	}
+
+	// check : not allow algo, output
+	_, err := strproc.VerifyHMAC("abc", "key", strutils.HashCRC64ISO, mac)
+	assert.AssertNotNil(t, err, "Couldn't check the `not allow algo`\nError : %v", err)
+
+	_, err = strproc.VerifyHMAC("abc", "key", strutils.HashSHA256, mac, 9)
+	assert.AssertNotNil(t, err, "Couldn't check the `not allow output`\nError : %v", err)
+}
+
+func Test_strutils_HKDF(t *testing.T) {
+	t.Parallel()
+
+	ikm := strings.Repeat("\x0b", 22)
+
+	// RFC 5869 (test case 1, 3, 4)
+	dataset := []struct {
+		secret string
+		salt   string
+		info   string
+		length int
+		algo   uint8
+		okm    string
+	}{
+		{ikm, "\x00\x01\x02\x03\x04\x05\x06\x07\x08\x09\x0a\x0b\x0c", "\xf0\xf1\xf2\xf3\xf4\xf5\xf6\xf7\xf8\xf9", 42, strutils.HashSHA256, "3cb25f25faacd57a90434f64d0362f2a2d2d0a90cf1a5a4c5db02d56ecc4c5bf34007208d5b887185865"},
+		{ikm, "", "", 42, strutils.HashSHA256, "8da4e775a563c18f715f802a063c5a31b8a11f5c5ee1879ec3454e5f3c738d2d9d201395faa4b61a96c8"},
+		{ikm[:11], "\x00\x01\x02\x03\x04\x05\x06\x07\x08\x09\x0a\x0b\x0c", "\xf0\xf1\xf2\xf3\xf4\xf5\xf6\xf7\xf8\xf9", 42, strutils.HashSHA1, "085a01ea1b10f36933068b56efa5ad81a4f14b822f5b091568a9cdd4f155fda2c22e422478d305f3f896"},
+	}
+
+	// check : common
+	for _, v := range dataset {
+		retval, err := strproc.HKDF(v.secret, v.salt, v.info, v.length, v.algo)
+		assert.AssertNil(t, err, "Error : %v", err)
+		assert.AssertEquals(t, retval, v.okm, "Return Value mismatch (%v).\nExpected: %v\nActual: %v", v.algo, v.okm, retval)
+	}
+
+	// check : output
+	retval, err := strproc.HKDF("secret", "salt", "info", 16, strutils.HashSHA256, strutils.HashOutputRaw)
+	assert.AssertNil(t, err, "Error : %v", err)
+	assert.AssertEquals(t, len(retval), 16, "Return Value mismatch.\nExpected: %v\nActual: %v", 16, len(retval))
+
+	// check : not allow length, algo
+	for _, v := range []int{0, -1, 255*32 + 1} {
+		_, err = strproc.HKDF("secret", "salt", "info", v, strutils.HashSHA256)
+		assert.AssertNotNil(t, err, "Couldn't check the `not allow length` (%v)\nError : %v", v, err)
+	}
+
+	_, err = strproc.HKDF("secret", "salt", "info", 32, strutils.HashAdler32)
+	assert.AssertNotNil(t, err, "Couldn't check the `not allow algo`\nError : %v", err)
+}
@@ -0,0 +1,792 @@
+package strutils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// link style control of HTML2Text
+const (
+	LinkStyleInline   = 0 // text (url)
+	LinkStyleFootnote = 1 // text[1] , and [1] url at the end
+	LinkStyleNone     = 2 // text only
+)
+
+// elements that make a paragraph break
+var html2TextBlockElements = map[string]bool{
+	"address": true, "article": true, "aside": true, "blockquote": true, "center": true, "details": true, "dialog": true,
+	"div": true, "dl": true, "fieldset": true, "figcaption": true, "figure": true, "footer": true, "form": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true, "header": true, "hr": true, "main": true,
+	"nav": true, "ol": true, "p": true, "pre": true, "section": true, "summary": true, "table": true, "ul": true,
+}
+
+// elements that make a line break
+var html2TextLineElements = map[string]bool{"li": true, "dt": true, "dd": true, "tr": true, "caption": true}
+
+// elements that contents are not the readable text
+var html2TextSkipElements = map[string]bool{"title": true, "head": true, "template": true, "select": true}
+
+type html2TextList struct {
+	ordered bool
+	no      int
+}
+
+type html2TextTable struct {
+	rows   [][]string
+	header []bool
+	cell   []byte
+	incell bool
+	depth  int // nested tables are flatten into the cell
+}
+
+// html2Text is the state of HTML2Text
+type html2Text struct {
+	buf       []byte
+	pending   int  // pending newlines
+	newlines  int  // newlines at the end of buf
+	space     bool // pending space
+	linestart bool
+	pre       int
+	quote     int
+	skip      int
+	lists     []html2TextList
+	table     *html2TextTable
+	linkStyle int
+	links     []string
+	href      []string // stack of href
+	linkText  []int    // stack of the position of link text
+	markdown  bool     // write the markdown syntax (HTML2Markdown)
+	code      int
+}
+
+func (w *html2Text) prefix() string {
+	indent := len(w.lists) - 1
+	if indent < 0 {
+		indent = 0
+	}
+
+	// markdown needs the indent of the list marker width ("1. ") for nested blocks
+	if w.markdown {
+		return strings.Repeat("> ", w.quote) + strings.Repeat("   ", indent)
+	}
+
+	return strings.Repeat("> ", w.quote) + strings.Repeat("  ", indent)
+}
+
+// block requests n newlines before the next text
+func (w *html2Text) block(n int) {
+	if w.table != nil {
+		w.space = true
+		return
+	}
+
+	if n > w.pending {
+		w.pending = n
+	}
+}
+
+func (w *html2Text) flush() {
+	if w.pending == 0 {
+		return
+	}
+
+	if len(w.buf) == 0 {
+		w.buf = append(w.buf, w.prefix()...)
+	} else {
+		for ; w.newlines < w.pending; w.newlines++ {
+			w.buf = append(w.buf, 10)
+			w.buf = append(w.buf, w.prefix()...)
+		}
+	}
+
+	w.linestart = true
+	w.pending = 0
+}
+
+// newline writes a line break (<br>)
+func (w *html2Text) newline() {
+	if w.table != nil {
+		w.space = true
+		return
+	}
+
+	w.flush()
+	w.buf = append(w.buf, 10)
+	w.buf = append(w.buf, w.prefix()...)
+	w.newlines++
+	w.linestart = true
+	w.space = false
+}
+
+// lineBreak writes <br>, markdown needs the backslash for the hard line break
+func (w *html2Text) lineBreak() {
+	if w.markdown && w.table == nil {
+		w.mark("\\", false)
+	}
+	w.newline()
+}
+
+// raw writes str as it is without collapsing white spaces
+func (w *html2Text) raw(str string) {
+	if w.table != nil {
+		w.text(str)
+		return
+	}
+
+	w.flush()
+
+	if w.space && !w.linestart {
+		w.buf = append(w.buf, 32)
+	}
+	w.space = false
+
+	for i := 0; i < len(str); i++ {
+		w.buf = append(w.buf, str[i])
+		if str[i] == 10 {
+			w.buf = append(w.buf, w.prefix()...)
+			w.newlines++
+		} else {
+			w.newlines = 0
+		}
+	}
+	w.linestart = w.newlines > 0
+}
+
+// text writes str with collapsing white spaces
+func (w *html2Text) text(str string) {
+	if w.skip > 0 {
+		return
+	}
+
+	if w.pre > 0 && w.table == nil {
+		w.raw(str)
+		return
+	}
+
+	for i, r := range str {
+		if r == 32 || r == 9 || r == 10 || r == 12 || r == 13 {
+			w.space = true
+			continue
+		}
+
+		escape := w.markdown && w.code == 0 && markdownEscape(str[i:], r)
+
+		if w.table != nil {
+			tbl := w.table
+			if !tbl.incell {
+				continue
+			}
+
+			if w.space && len(tbl.cell) > 0 {
+				tbl.cell = append(tbl.cell, 32)
+			}
+			if escape || (w.markdown && r == 124) { // |
+				tbl.cell = append(tbl.cell, 92)
+			}
+			tbl.cell = append(tbl.cell, string(r)...)
+			w.space = false
+			continue
+		}
+
+		w.flush()
+		if w.space && !w.linestart {
+			w.buf = append(w.buf, 32)
+		}
+		if escape || (w.markdown && w.code == 0 && w.linestart && strings.ContainsRune("#+->=", r)) {
+			w.buf = append(w.buf, 92) // backslash
+		}
+		w.buf = append(w.buf, string(r)...)
+		w.space = false
+		w.linestart = false
+		w.newlines = 0
+	}
+}
+
+// markdownEscape returns true if r (at the beginning of str) is the markdown syntax in the text
+func markdownEscape(str string, r rune) bool {
+	switch r {
+	case 92, 96, 42, 95, 91, 93, 60: // \ ` * _ [ ] <
+		return true
+	case 38: // &
+		_, size := entityRef(str)
+		return size > 0
+	}
+
+	return false
+}
+
+// mark writes the inline markdown syntax, open is the syntax before the contents (white spaces are not allowed after it)
+func (w *html2Text) mark(str string, open bool) {
+	if w.skip > 0 {
+		return
+	}
+
+	if tbl := w.table; tbl != nil {
+		if !tbl.incell {
+			return
+		}
+
+		if open && w.space && len(tbl.cell) > 0 {
+			tbl.cell = append(tbl.cell, 32)
+		}
+		if open {
+			w.space = false
+		}
+		tbl.cell = append(tbl.cell, str...)
+		return
+	}
+
+	w.flush()
+	if open {
+		if w.space && !w.linestart {
+			w.buf = append(w.buf, 32)
+		}
+		w.space = false
+	}
+	w.buf = append(w.buf, str...)
+	w.newlines = 0
+	w.linestart = open
+}
+
+// markdownURL escapes the characters that ends the link destination of markdown
+func markdownURL(str string) string {
+	return strings.NewReplacer(" ", "%20", "(", "%28", ")", "%29", "<", "%3C", ">", "%3E").Replace(str)
+}
+
+// length of written text, for link text
+func (w *html2Text) pos() int {
+	if w.table != nil {
+		return len(w.table.cell)
+	}
+
+	return len(w.buf)
+}
+
+func (w *html2Text) written(from int) string {
+	if w.table != nil {
+		if from > len(w.table.cell) {
+			return ""
+		}
+		return string(w.table.cell[from:])
+	}
+
+	if from > len(w.buf) {
+		return ""
+	}
+
+	return string(w.buf[from:])
+}
+
+func (w *html2Text) endCell() {
+	tbl := w.table
+	if !tbl.incell {
+		return
+	}
+
+	if len(tbl.rows) == 0 {
+		tbl.rows = append(tbl.rows, nil)
+		tbl.header = append(tbl.header, true)
+	}
+
+	last := len(tbl.rows) - 1
+	tbl.rows[last] = append(tbl.rows[last], string(tbl.cell))
+	tbl.cell = tbl.cell[:0]
+	tbl.incell = false
+	w.space = false
+}
+
+// renderTable writes the table as aligned columns
+func (w *html2Text) renderTable() {
+	tbl := w.table
+	w.endCell()
+	w.table = nil
+
+	var widths []int
+	for _, row := range tbl.rows {
+		for k, v := range row {
+			if k >= len(widths) {
+				widths = append(widths, 0)
+			}
+
+			if l := utf8.RuneCountInString(v); l > widths[k] {
+				widths[k] = l
+			}
+		}
+	}
+
+	if w.markdown {
+		w.block(2)
+		w.raw(strings.Join(markdownTable(tbl.rows, widths), "\n"))
+		w.block(2)
+		return
+	}
+
+	lines := make([]string, 0, len(tbl.rows)+1)
+	for n, row := range tbl.rows {
+		if len(row) == 0 {
+			continue
+		}
+
+		line := make([]string, len(row))
+		for k, v := range row {
+			line[k] = v + strings.Repeat(" ", widths[k]-utf8.RuneCountInString(v))
+		}
+		lines = append(lines, strings.TrimRight(strings.Join(line, "  "), " "))
+
+		// separator after the header row
+		if n == 0 && tbl.header[0] && len(tbl.rows) > 1 {
+			sep := make([]string, len(widths))
+			for k, v := range widths {
+				sep[k] = strings.Repeat("-", v)
+			}
+			lines = append(lines, strings.Join(sep, "  "))
+		}
+	}
+
+	w.block(2)
+	w.raw(strings.Join(lines, "\n"))
+	w.block(2)
+}
+
+// markdownTable returns the lines of the pipe table, the first row is the header row
+func markdownTable(rows [][]string, widths []int) []string {
+	for k, v := range widths {
+		if v < 3 {
+			widths[k] = 3
+		}
+	}
+
+	lines := make([]string, 0, len(rows)+1)
+	for _, row := range rows {
+		if len(row) == 0 {
+			continue
+		}
+
+		line := make([]string, len(widths))
+		for k := range widths {
+			v := ""
+			if k < len(row) {
+				v = row[k]
+			}
+			line[k] = v + strings.Repeat(" ", widths[k]-utf8.RuneCountInString(v))
+		}
+		lines = append(lines, "| "+strings.Join(line, " | ")+" |")
+
+		// separator after the header row
+		if len(lines) == 1 {
+			sep := make([]string, len(widths))
+			for k, v := range widths {
+				sep[k] = strings.Repeat("-", v)
+			}
+			lines = append(lines, "| "+strings.Join(sep, " | ")+" |")
+		}
+	}
+
+	return lines
+}
+
+// markdownStartTag writes the markdown syntax of the start tag, returns false if tag is not the markdown element
+func (w *html2Text) markdownStartTag(tok htmlToken) bool {
+	name := tok.Data
+
+	switch name {
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		w.block(2)
+		w.mark(strings.Repeat("#", int(name[1]-48))+" ", true)
+
+	case "b", "strong":
+		w.mark("**", true)
+
+	case "i", "em":
+		w.mark("*", true)
+
+	case "code":
+		if w.pre == 0 {
+			w.mark("`", true)
+			w.code++
+		}
+
+	case "hr":
+		w.block(2)
+		w.raw("---")
+		w.block(2)
+
+	case "img":
+		src, alt := "", ""
+		for _, v := range tok.Attr {
+			switch v.Key {
+			case "src":
+				src = strings.TrimSpace(v.Val)
+			case "alt":
+				alt = strings.NewReplacer("[", "\\[", "]", "\\]").Replace(v.Val)
+			}
+		}
+		if src == "" || strings.HasPrefix(strings.ToLower(src), "javascript:") {
+			return true
+		}
+		w.mark("!["+alt+"]("+markdownURL(src)+")", true)
+		w.linestart = false
+
+	case "a":
+		href := ""
+		for _, v := range tok.Attr {
+			if v.Key == "href" {
+				href = strings.TrimSpace(v.Val)
+			}
+		}
+		if strings.HasPrefix(strings.ToLower(href), "javascript:") {
+			href = ""
+		}
+		w.href = append(w.href, href)
+		w.linkText = append(w.linkText, w.pos())
+		if href != "" {
+			w.mark("[", true)
+		}
+
+	case "pre":
+		w.block(2)
+		w.raw("```")
+		w.newline()
+		w.pre++
+
+	default:
+		return false
+	}
+
+	return true
+}
+
+// markdownEndTag writes the markdown syntax of the end tag, returns false if tag is not the markdown element
+func (w *html2Text) markdownEndTag(tok htmlToken) bool {
+	switch tok.Data {
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		w.block(2)
+
+	case "b", "strong":
+		w.mark("**", false)
+
+	case "i", "em":
+		w.mark("*", false)
+
+	case "code":
+		if w.pre == 0 && w.code > 0 {
+			w.code--
+			w.mark("`", false)
+		}
+
+	case "a":
+		n := len(w.href)
+		if n == 0 {
+			return true
+		}
+
+		href := w.href[n-1]
+		w.href, w.linkText = w.href[:n-1], w.linkText[:n-1]
+		if href != "" {
+			w.mark("]("+markdownURL(href)+")", false)
+		}
+
+	case "pre":
+		if w.pre == 0 {
+			return true
+		}
+		if w.newlines == 0 {
+			w.newline()
+		}
+		w.raw("```")
+		w.block(2)
+		w.pre--
+
+	default:
+		return false
+	}
+
+	return true
+}
+
+func (w *html2Text) startTag(tok htmlToken) {
+	name := tok.Data
+
+	if html2TextSkipElements[name] {
+		w.skip++
+		return
+	}
+
+	if tbl := w.table; tbl != nil {
+		switch name {
+		case "table":
+			tbl.depth++
+			return
+		case "tr":
+			if tbl.depth == 0 {
+				w.endCell()
+				tbl.rows = append(tbl.rows, nil)
+				tbl.header = append(tbl.header, true)
+			}
+			w.space = true
+			return
+		case "td", "th":
+			if tbl.depth == 0 {
+				w.endCell()
+				if len(tbl.rows) == 0 {
+					tbl.rows = append(tbl.rows, nil)
+					tbl.header = append(tbl.header, true)
+				}
+				if name == "td" {
+					tbl.header[len(tbl.header)-1] = false
+				}
+				tbl.incell = true
+			}
+			w.space = true
+			return
+		}
+	}
+
+	if w.markdown && w.markdownStartTag(tok) {
+		return
+	}
+
+	switch {
+	case name == "table":
+		w.block(2)
+		w.table = &html2TextTable{}
+		return
+
+	case name == "img":
+		for _, v := range tok.Attr {
+			if v.Key == "alt" && v.Val != "" {
+				w.text(" " + v.Val + " ")
+			}
+		}
+		return
+
+	case name == "a":
+		href := ""
+		for _, v := range tok.Attr {
+			if v.Key == "href" {
+				href = strings.TrimSpace(v.Val)
+			}
+		}
+		if href == "" || href[0] == 35 || strings.HasPrefix(strings.ToLower(href), "javascript:") { // #anchor
+			href = ""
+		}
+		w.href = append(w.href, href)
+		w.linkText = append(w.linkText, w.pos())
+		return
+
+	case name == "ul" || name == "ol":
+		if len(w.lists) > 0 { // nested list
+			w.block(1)
+		} else {
+			w.block(2)
+		}
+		w.lists = append(w.lists, html2TextList{ordered: name == "ol"})
+		return
+
+	case name == "li":
+		w.block(1)
+		w.flush()
+		marker := "- "
+		if n := len(w.lists); n > 0 {
+			w.lists[n-1].no++
+			if w.lists[n-1].ordered {
+				marker = strconv.Itoa(w.lists[n-1].no) + ". "
+			}
+		}
+		w.raw(marker)
+		w.linestart = true
+		w.space = false
+		return
+
+	case name == "blockquote":
+		w.block(2)
+		w.quote++
+		return
+
+	case name == "pre":
+		w.block(2)
+		w.pre++
+		return
+	}
+
+	if html2TextBlockElements[name] {
+		w.block(2)
+	} else if html2TextLineElements[name] {
+		w.block(1)
+	}
+}
+
+func (w *html2Text) endTag(tok htmlToken) {
+	name := tok.Data
+
+	if html2TextSkipElements[name] {
+		if w.skip > 0 {
+			w.skip--
+		}
+		return
+	}
+
+	if tbl := w.table; tbl != nil {
+		switch name {
+		case "table":
+			if tbl.depth > 0 {
+				tbl.depth--
+				return
+			}
+			w.renderTable()
+			return
+		case "td", "th":
+			if tbl.depth == 0 {
+				w.endCell()
+			}
+			return
+		case "tr":
+			return
+		}
+	}
+
+	if w.markdown && w.markdownEndTag(tok) {
+		return
+	}
+
+	switch name {
+	case "a":
+		n := len(w.href)
+		if n == 0 {
+			return
+		}
+
+		href, from := w.href[n-1], w.linkText[n-1]
+		w.href, w.linkText = w.href[:n-1], w.linkText[:n-1]
+
+		if href == "" {
+			return
+		}
+
+		switch w.linkStyle {
+		case LinkStyleInline:
+			if strings.TrimSpace(w.written(from)) != href {
+				w.space = true
+				w.text("(" + href + ")")
+			}
+		case LinkStyleFootnote:
+			w.links = append(w.links, href)
+			w.space = false
+			w.text("[" + strconv.Itoa(len(w.links)) + "]")
+		}
+		return
+
+	case "ul", "ol":
+		if len(w.lists) > 0 {
+			w.lists = w.lists[:len(w.lists)-1]
+		}
+		if len(w.lists) > 0 { // nested list
+			w.block(1)
+		} else {
+			w.block(2)
+		}
+		return
+
+	case "blockquote":
+		w.block(2)
+		if w.quote > 0 {
+			w.quote--
+		}
+		return
+
+	case "pre":
+		w.block(2)
+		if w.pre > 0 {
+			w.pre--
+		}
+		return
+	}
+
+	if html2TextBlockElements[name] {
+		w.block(2)
+	} else if html2TextLineElements[name] {
+		w.block(1)
+	}
+}
+
+// HTML2Text is Convert HTML to readable plain text (paragraphs, lists, aligned tables, links, pre-formatted text)
+// linkStyle : LinkStyleInline, LinkStyleFootnote, LinkStyleNone
+// NOTE : the text is stripped same as StripTags, the decoded entities and the text can't re-form a tag (&lt;b&gt;, <<b>script>)
+func (s *StringProc) HTML2Text(str string, linkStyle int) (string, error) {
+	if linkStyle < LinkStyleInline || linkStyle > LinkStyleNone {
+		return "", fmt.Errorf("Not allow linkStyle parameter : %v", linkStyle)
+	}
+
+	w := &html2Text{buf: make([]byte, 0, len(str)), linkStyle: linkStyle, linestart: true}
+
+	// the text of the tokens may re-form a tag (<<b>script>)
+	return stripTags(w.convert(str)), nil
+}
+
+// convert writes the text of the HTML str
+func (w *html2Text) convert(str string) string {
+	prevPre := false
+	z := newHTMLTokenizer(str)
+	for {
+		tok, ok := z.next()
+		if !ok {
+			break
+		}
+
+		switch tok.Type {
+		case htmlTokenText, htmlTokenCDATA:
+			text := decodeHTMLEntities(tok.Data)
+			if !w.markdown { // the decoded markup is stripped, same as StripTags (markdown escapes it)
+				text = stripTags(text)
+			}
+			if prevPre && len(text) > 0 && text[0] == 10 { // a newline right after <pre> is ignored
+				text = text[1:]
+			}
+			w.text(text)
+
+		case htmlTokenStartTag, htmlTokenSelfClosing, htmlTokenEndTag:
+			// <br>, <br/> and </br> are the line break, same as Br2Nl
+			if n, _ := htmlTagLen(tok.Raw, "br"); n > 0 {
+				w.lineBreak()
+				break
+			}
+
+			if tok.Type == htmlTokenEndTag {
+				w.endTag(tok)
+				break
+			}
+
+			w.startTag(tok)
+			if tok.Type == htmlTokenSelfClosing && !htmlVoidElements[tok.Data] {
+				w.endTag(tok)
+			}
+		}
+
+		prevPre = tok.Type == htmlTokenStartTag && tok.Data == "pre"
+	}
+
+	if w.table != nil {
+		w.renderTable()
+	}
+
+	if len(w.links) > 0 {
+		w.pending = 0
+		w.quote = 0
+		w.lists = nil
+		w.block(2)
+		for k, v := range w.links {
+			w.block(1)
+			w.raw("[" + strconv.Itoa(k+1) + "] " + v)
+		}
+	}
+
+	// trim trailing white spaces of the lines
+	lines := strings.Split(string(w.buf), "\n")
+	for k, v := range lines {
+		lines[k] = strings.TrimRight(v, " ")
+	}
+
+	return strings.Trim(strings.Join(lines, "\n"), "\n")
+}
@@ -0,0 +1,65 @@
+package strutils_test
+
+import (
+	"testing"
+
+	strutils "github.com/torden/go-strutil"
+)
+
+func Test_strutils_HTML2Text(t *testing.T) {
+	t.Parallel()
+
+	dataset := map[string]string{
+		"abcdefg":                         "abcdefg",
+		"<p>a   b\n c</p><p>d</p>":        "a b c\n\nd",
+		"<h1>Title</h1>text":              "Title\n\ntext",
+		"<div>a<br>b<br/><br />c</div>":   "a\nb\n\nc",
+		"<p>&lt;tag&gt; &amp; &copy;</p>": "& ©",
+		"<p>a &lt; b &gt; c</p>":          "a < b > c",
+		"a</br>b<BR\t/>c< br>d":           "a\nb\nc< br>d",
+		"<<b>script>alert(1)</script>x":   "",
+		"<head><title>T</title><style>x{}</style></head><body>a</body>": "a",
+		"a<script>alert(1)</script>b<!-- c -->":                         "ab",
+		"<ul><li>one</li><li>two</li></ul>":                             "- one\n- two",
+		"<ol><li>one<li>two</ol>":                                       "1. one\n2. two",
+		"<ul><li>a<ol><li>b</li><li>c</li></ol></li><li>d</li></ul>":    "- a\n  1. b\n  2. c\n- d",
+		"<p>a</p><ul><li>b</li></ul><p>c</p>":                           "a\n\n- b\n\nc",
+		"<pre>\n  a  b\n    c\n</pre>":                                  "  a  b\n    c",
+		"<p>x</p><pre>a\n\nb</pre><p>y</p>":                             "x\n\na\n\nb\n\ny",
+		"<blockquote><p>a</p><p>b</p></blockquote>c":                    "> a\n>\n> b\n\nc",
+		"<img src=x.png alt=\"logo\"> text":                             "logo text",
+		`<a href="#top">top</a>`:                                        "top",
+		`<a href="javascript:x()">x</a>`:                                "x",
+		`<a href="https://golang.org">golang</a>`:                       "golang (https://golang.org)",
+		`<a href="https://golang.org">https://golang.org</a>`:           "https://golang.org",
+		"<table><tr><th>Name</th><th>Size</th></tr><tr><td>apple</td><td>10</td></tr><tr><td>kiwi fruit</td><td>2</td></tr></table>": "Name        Size\n----------  ----\napple       10\nkiwi fruit  2",
+		"<table><tr><td>a</td><td>bb</td></tr><tr><td>ccc</td><td>d</td></tr></table>":                                               "a    bb\nccc  d",
+		"<p>x</p><table><tr><td><table><tr><td>in</td><td>ner</td></tr></table></td><td>b</td></tr></table><p>y</p>":                 "x\n\nin ner  b\n\ny",
+		"<table><tr><td>unclosed": "unclosed",
+	}
+
+	// check : common
+	for k, v := range dataset {
+		retval, err := strproc.HTML2Text(k, strutils.LinkStyleInline)
+		assert.AssertNil(t, err, "Error : %v", err)
+		assert.AssertEquals(t, retval, v, "Return Value mismatch (%v).\nExpected: %v\nActual: %v", k, v, retval)
+	}
+
+	str := `<p>See <a href="https://golang.org">golang</a> and <a href="https://github.com">github</a>.</p>`
+
+	// check : footnote
+	retval, err := strproc.HTML2Text(str, strutils.LinkStyleFootnote)
+	assert.AssertNil(t, err, "Error : %v", err)
+	str_ok := "See golang[1] and github[2].\n\n[1] https://golang.org\n[2] https://github.com"
+	assert.AssertEquals(t, retval, str_ok, "Return Value mismatch.\nExpected: %v\nActual: %v", str_ok, retval)
+
+	// check : none
+	retval, err = strproc.HTML2Text(str, strutils.LinkStyleNone)
+	assert.AssertNil(t, err, "Error : %v", err)
+	str_ok = "See golang and github."
+	assert.AssertEquals(t, retval, str_ok, "Return Value mismatch.\nExpected: %v\nActual: %v", str_ok, retval)
+
+	// check : not allow linkStyle
+	_, err = strproc.HTML2Text(str, 3)
+	assert.AssertNotNil(t, err, "Couldn't check the `not allow linkStyle`\nError : %v", err)
+}
@@ -0,0 +1,271 @@
+package strutils
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"unicode/utf8"
+)
+
+// HTML entity encoding control
+const (
+	EntityEncodeMinimal = 0 // only & < > " ' are encoded
+	EntityEncodeNamed   = 1 // minimal set and non-ascii characters to named references if exists (else numeric)
+	EntityEncodeNumeric = 2 // minimal set and non-ascii characters to decimal numeric references
+)
+
+// longest name in htmlEntityTable is `CounterClockwiseContourIntegral;` (32 chars)
+const (
+	entityNameMaxLen       = 32
+	entityLegacyNameMaxLen = 6 // all names without a semicolon are 6 or fewer chars
+)
+
+// replacement of numeric character references 0x80 ~ 0x9F
+// referrer : https://html.spec.whatwg.org/multipage/parsing.html#numeric-character-reference-end-state
+var entityC1Replacement = [32]rune{
+	'€', '\u0081', '‚', 'ƒ', '„', '…', '†', '‡',
+	'ˆ', '‰', 'Š', '‹', 'Œ', '\u008D', 'Ž', '\u008F',
+	'\u0090', '‘', '’', '“', '”', '•', '–', '—',
+	'˜', '™', 'š', '›', 'œ', '\u009D', 'ž', 'Ÿ',
+}
+
+// reverse of htmlEntityTable, made on first EntityEncodeNamed call
+var htmlEntityReverse = struct {
+	sync.Once
+	names map[rune]string
+}{}
+
+func isEntityAlnum(c byte) bool {
+	return (c >= 48 && c <= 57) || (c >= 65 && c <= 90) || (c >= 97 && c <= 122) // 0~9, A~Z, a~z
+}
+
+// entityRef looks for a character reference at the beginning of str (str[0] is '&')
+// returns the replacement string and the length of the reference, (size 0 means not a reference)
+func entityRef(str string) (string, int) {
+	l := len(str)
+	if l < 2 {
+		return "", 0
+	}
+
+	// numeric : &#NNN; , &#xHHH; (semicolon is optional, any padding)
+	if str[1] == 35 { // #
+		i := 2
+		hex := false
+		if i < l && (str[i] == 120 || str[i] == 88) { // x , X
+			hex = true
+			i++
+		}
+
+		start := i
+		var cp uint32
+		for ; i < l; i++ {
+			c := str[i]
+
+			var d uint32
+			switch {
+			case c >= 48 && c <= 57:
+				d = uint32(c - 48)
+			case hex && c >= 97 && c <= 102:
+				d = uint32(c - 87)
+			case hex && c >= 65 && c <= 70:
+				d = uint32(c - 55)
+			default:
+				goto DIGITS_END
+			}
+
+			if hex {
+				cp = cp*16 + d
+			} else {
+				cp = cp*10 + d
+			}
+
+			if cp > utf8.MaxRune { // keep it out of range without overflow
+				cp = utf8.MaxRune + 1
+			}
+		}
+	DIGITS_END:
+
+		if i == start {
+			return "", 0
+		}
+
+		if i < l && str[i] == 59 { // ;
+			i++
+		}
+
+		return string(entityCodePoint(cp)), i
+	}
+
+	// named : &name; or legacy &name
+	end := 1
+	for end < l && end <= entityNameMaxLen && isEntityAlnum(str[end]) {
+		end++
+	}
+
+	if end == 1 {
+		return "", 0
+	}
+
+	if end < l && str[end] == 59 { // ;
+		if v, ok := htmlEntityTable[str[1:end+1]]; ok {
+			return v, end + 1
+		}
+	}
+
+	// legacy forms, the longest match win. (&notit; => ¬it;)
+	maxlen := end - 1
+	if maxlen > entityLegacyNameMaxLen {
+		maxlen = entityLegacyNameMaxLen
+	}
+
+	for n := maxlen; n > 1; n-- {
+		if v, ok := htmlEntityTable[str[1:1+n]]; ok {
+			return v, n + 1
+		}
+	}
+
+	return "", 0
+}
+
+// entityCodePoint returns a rune of numeric character reference value
+func entityCodePoint(cp uint32) rune {
+	switch {
+	case cp >= 0x80 && cp <= 0x9F:
+		return entityC1Replacement[cp-0x80]
+	case cp == 0, cp > utf8.MaxRune, cp >= 0xD800 && cp <= 0xDFFF:
+		return utf8.RuneError
+	}
+
+	return rune(cp)
+}
+
+// hasHTMLEntities returns true if str has one or more character references
+func hasHTMLEntities(str string) bool {
+	l := len(str)
+	for i := 0; i < l; i++ {
+		if str[i] == 38 { // &
+			if _, n := entityRef(str[i:]); n > 0 {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// decodeHTMLEntities decodes all character references in str
+func decodeHTMLEntities(str string) string {
+	l := len(str)
+	buf := make([]byte, 0, l) // prealloca
+
+	for i := 0; i < l; i++ {
+		if str[i] == 38 { // &
+			if v, n := entityRef(str[i:]); n > 0 {
+				buf = append(buf, v...)
+				i += n - 1
+				continue
+			}
+		}
+
+		buf = append(buf, str[i])
+	}
+
+	return string(buf)
+}
+
+// entityName returns the preferred name of the rune (shortest, then lower-case first)
+func entityName(r rune) (string, bool) {
+	htmlEntityReverse.Do(func() {
+		names := make(map[rune][]string)
+		for k, v := range htmlEntityTable {
+			if k[len(k)-1] != 59 { // skip legacy
+				continue
+			}
+
+			r, size := utf8.DecodeRuneInString(v)
+			if size != len(v) { // skip two code points
+				continue
+			}
+			names[r] = append(names[r], k)
+		}
+
+		htmlEntityReverse.names = make(map[rune]string, len(names))
+		for r, v := range names {
+			sort.Slice(v, func(i, j int) bool {
+				if len(v[i]) != len(v[j]) {
+					return len(v[i]) < len(v[j])
+				}
+
+				li, lj := v[i][0] >= 97, v[j][0] >= 97
+				if li != lj {
+					return li
+				}
+
+				return v[i] < v[j]
+			})
+			htmlEntityReverse.names[r] = v[0]
+		}
+	})
+
+	name, ok := htmlEntityReverse.names[r]
+	return name, ok
+}
+
+// EncodeHTMLEntities is Convert characters to HTML character references (EntityEncodeMinimal, EntityEncodeNamed, EntityEncodeNumeric)
+func (s *StringProc) EncodeHTMLEntities(str string, mode int) (string, error) {
+	if mode < EntityEncodeMinimal || mode > EntityEncodeNumeric {
+		return "", fmt.Errorf("Not allow mode parameter : %v", mode)
+	}
+
+	buf := make([]byte, 0, len(str)*2) // prealloca
+
+	for _, r := range str {
+		switch r {
+		case 38: // &
+			buf = append(buf, "&amp;"...)
+			continue
+		case 60: // <
+			buf = append(buf, "&lt;"...)
+			continue
+		case 62: // >
+			buf = append(buf, "&gt;"...)
+			continue
+		case 34: // "
+			buf = append(buf, "&quot;"...)
+			continue
+		case 39: // '
+			buf = append(buf, "&#39;"...)
+			continue
+		}
+
+		if r < utf8.RuneSelf || mode == EntityEncodeMinimal {
+			buf = append(buf, string(r)...)
+			continue
+		}
+
+		if mode == EntityEncodeNamed {
+			if name, ok := entityName(r); ok {
+				buf = append(buf, 38)
+				buf = append(buf, name...)
+				continue
+			}
+		}
+
+		buf = append(buf, "&#"...)
+		buf = strconv.AppendInt(buf, int64(r), 10)
+		buf = append(buf, 59)
+	}
+
+	return string(buf), nil
+}
+
+// DecodeHTMLEntities is Convert all HTML character references (named, decimal, hex, legacy without semicolon) to characters
+func (s *StringProc) DecodeHTMLEntities(str string) string {
+	return decodeHTMLEntities(str)
+}
+
+// HasHTMLEntities is Validates whether the value has HTML character references
+func (s *StringProc) HasHTMLEntities(str string) bool {
+	return hasHTMLEntities(str)
+}
@@ -0,0 +1,127 @@
+package strutils_test
+
+import (
+	"testing"
+
+	strutils "github.com/torden/go-strutil"
+)
+
+func Test_strutils_DecodeHTMLEntities(t *testing.T) {
+	t.Parallel()
+
+	dataset := map[string]string{
+		"abcdefg":                             "abcdefg",
+		"&lt;script&gt;":                      "<script>",
+		"&#x27;":                              "'",
+		"&#X27;":                              "'",
+		"&#0000060":                           "<",
+		"&#0000060;":                          "<",
+		"&#x000003c;":                         "<",
+		"&#60":                                "<",
+		"&CounterClockwiseContourIntegral;":   "∳",
+		"&NotEqualTilde;":                     "≂̸",
+		"&amp":                                "&",
+		"&ampx":                               "&x",
+		"&notit;":                             "¬it;",
+		"&notin;":                             "∉",
+		"&copy2020":                           "©2020",
+		"&#128;":                              "€",
+		"&#0;":                                "�",
+		"&#xD800;":                            "�",
+		"&#99999999999999999999;":             "�",
+		"&#;":                                 "&#;",
+		"&#x;":                                "&#x;",
+		"&unknown;":                           "&unknown;",
+		"& ;":                                 "& ;",
+		"&":                                   "&",
+		"a &amp&lt b":                         "a &< b",
+		"README.md haven&rsquo;t":             "README.md haven’t",
+		"ABC PERC%NT DEF":                     "ABC PERC%NT DEF",
+		"story_fbid=2566258156763959&id=6365": "story_fbid=2566258156763959&id=6365",
+	}
+
+	// check : common
+	for k, v := range dataset {
+		retval := strproc.DecodeHTMLEntities(k)
+		assert.AssertEquals(t, retval, v, "Return Value mismatch.\nExpected: %v\nActual: %v", v, retval)
+	}
+}
+
+func Test_strutils_HasHTMLEntities(t *testing.T) {
+	t.Parallel()
+
+	dataset := map[string]bool{
+		"abcdefg":                            false,
+		"&#x27;":                             true,
+		"&#0000060":                          true,
+		"&CounterClockwiseContourIntegral;":  true,
+		"&lt":                                true,
+		"&#;":                                false,
+		"&unknown;":                          false,
+		"?story_fbid=2566258156763959&id=63": false,
+	}
+
+	// check : common
+	for k, v := range dataset {
+		retval := strproc.HasHTMLEntities(k)
+		assert.AssertEquals(t, retval, v, "Return Value mismatch.\nExpected: %v\nActual: %v", v, retval)
+	}
+}
+
+func Test_strutils_EncodeHTMLEntities(t *testing.T) {
+	t.Parallel()
+
+	dataset := map[int]map[string]string{
+		strutils.EntityEncodeMinimal: {
+			`<a href="x">'&'</a>`: "&lt;a href=&quot;x&quot;&gt;&#39;&amp;&#39;&lt;/a&gt;",
+			"café ©":              "café ©",
+		},
+		strutils.EntityEncodeNamed: {
+			`<a href="x">`: "&lt;a href=&quot;x&quot;&gt;",
+			"café © ∳":     "caf&eacute; &copy; &awconint;",
+			"가":            "&#44032;",
+			" ":            "&nbsp;",
+		},
+		strutils.EntityEncodeNumeric: {
+			`<a href="x">`: "&lt;a href=&quot;x&quot;&gt;",
+			"café ©":       "caf&#233; &#169;",
+			"😀":            "&#128512;",
+		},
+	}
+
+	// check : common
+	for mode, set := range dataset {
+		for k, v := range set {
+			retval, err := strproc.EncodeHTMLEntities(k, mode)
+			assert.AssertNil(t, err, "Error : %v", err)
+			assert.AssertEquals(t, retval, v, "Return Value mismatch.\nExpected: %v\nActual: %v", v, retval)
+
+			// check : round-trip
+			assert.AssertEquals(t, strproc.DecodeHTMLEntities(retval), k, "Round-trip mismatch.\nExpected: %v\nActual: %v", k, retval)
+		}
+	}
+
+	// check : not allow mode
+	_, err := strproc.EncodeHTMLEntities("abc", 3)
+	assert.AssertNotNil(t, err, "Couldn't check the `not allow mode`\nError : %v", err)
+}
+
+func Test_strutils_StripTags_Entities(t *testing.T) {
+	t.Parallel()
+
+	dataset := map[string]string{
+		"&#0000060;b&#0000062;alert(1)&#0000060;/b&#0000062;": "alert(1)",
+		"&#x3c;b&#x3e;bold&#x3c;/b&#x3e;":                     "bold",
+	}
+
+	// check : common
+	for k, v := range dataset {
+		retval, err := strproc.StripTags(k)
+		assert.AssertNil(t, err, "Error : %v", err)
+		assert.AssertEquals(t, retval, v, "Return Value mismatch.\nExpected: %v\nActual: %v", v, retval)
+	}
+
+	// check : IsPureTextNormal
+	ok, err := strvalidator.IsPureTextNormal("&#x3c;script&#x3e;")
+	assert.AssertFalse(t, ok, "Couldn't detect the hex entity encoded tag\nError : %v", err)
+}
@@ -0,0 +1,395 @@
+package strutils
+
+import (
+	"strings"
+)
+
+// HTML token type
+// referrer : https://html.spec.whatwg.org/multipage/parsing.html#tokenization
+const (
+	htmlTokenText        = iota // text (also contents of textarea, title)
+	htmlTokenStartTag           // <tag ...>
+	htmlTokenEndTag             // </tag ...>
+	htmlTokenSelfClosing        // <tag ... />
+	htmlTokenComment            // <!-- ... --> , <? ... > , </ ... > (bogus comment)
+	htmlTokenDoctype            // <!DOCTYPE ...>
+	htmlTokenCDATA              // <![CDATA[ ... ]]>
+	htmlTokenRawText            // contents of script, style, iframe, noembed, noframes
+)
+
+// htmlAttr is an attribute of the tag token, Val is entity decoded
+type htmlAttr struct {
+	Key string
+	Val string
+}
+
+// htmlToken is a token of htmlTokenizer
+type htmlToken struct {
+	Type int
+	Data string // lower-case tag name or text
+	Attr []htmlAttr
+	Raw  string // original markup of the token
+}
+
+// elements that contents are not the markup
+var (
+	htmlRawTextElements = map[string]bool{"script": true, "style": true, "iframe": true, "noembed": true, "noframes": true}
+	htmlRCDATAElements  = map[string]bool{"textarea": true, "title": true}
+)
+
+// htmlTokenizer is a HTML tokenizer state machine (simplified from the WHATWG tokenization)
+// NOTE : it doesn't build a tree, a tag unterminated at EOF is dropped like browsers
+type htmlTokenizer struct {
+	str       string
+	pos       int
+	rawTag    string // set when in raw text or rcdata element
+	plaintext bool   // after <plaintext>, all is text
+}
+
+func newHTMLTokenizer(str string) *htmlTokenizer {
+	return &htmlTokenizer{str: str}
+}
+
+func isHTMLSpace(c byte) bool {
+	return c == 32 || c == 9 || c == 10 || c == 12 || c == 13 // SPACE, TAB, LF, FF, CR
+}
+
+func isASCIIAlpha(c byte) bool {
+	return (c >= 65 && c <= 90) || (c >= 97 && c <= 122) // A~Z, a~z
+}
+
+// next returns the next token, false if EOF
+func (z *htmlTokenizer) next() (htmlToken, bool) {
+	for z.pos < len(z.str) {
+		if z.plaintext {
+			tok := htmlToken{Type: htmlTokenText, Data: z.str[z.pos:], Raw: z.str[z.pos:]}
+			z.pos = len(z.str)
+			return tok, true
+		}
+
+		if z.rawTag != "" {
+			if tok, ok := z.readRawText(); ok {
+				return tok, true
+			}
+			continue
+		}
+
+		// looking for markup
+		start := z.pos
+		i := z.markupIndex(start)
+		if i > start {
+			z.pos = i
+			return htmlToken{Type: htmlTokenText, Data: z.str[start:i], Raw: z.str[start:i]}, true
+		}
+
+		if i < 0 {
+			z.pos = len(z.str)
+			return htmlToken{Type: htmlTokenText, Data: z.str[start:], Raw: z.str[start:]}, true
+		}
+
+		if tok, ok := z.readMarkup(); ok {
+			return tok, true
+		}
+	}
+
+	return htmlToken{}, false
+}
+
+// markupIndex returns the index of '<' which starts markup from i, -1 if not found
+func (z *htmlTokenizer) markupIndex(i int) int {
+	l := len(z.str)
+	for ; i < l; i++ {
+		if z.str[i] != 60 || i+1 >= l { // <
+			continue
+		}
+
+		c := z.str[i+1]
+		if isASCIIAlpha(c) || c == 33 || c == 63 { // letter, !, ?
+			return i
+		}
+
+		if c == 47 && i+2 < l { // </ + any
+			return i
+		}
+	}
+
+	return -1
+}
+
+// readRawText reads contents of raw text or rcdata element until the end tag
+func (z *htmlTokenizer) readRawText() (htmlToken, bool) {
+	tag := z.rawTag
+	z.rawTag = ""
+
+	start := z.pos
+	l := len(z.str)
+	end := l
+
+	// the end tag is matched in ASCII case-insensitive, the offsets are of z.str (not a lowered copy)
+	for i := start; i+2+len(tag) <= l; i++ {
+		if z.str[i] != 60 || z.str[i+1] != 47 || !strings.EqualFold(z.str[i+2:i+2+len(tag)], tag) { // </
+			continue
+		}
+
+		after := i + 2 + len(tag)
+		if after >= l || isHTMLSpace(z.str[after]) || z.str[after] == 47 || z.str[after] == 62 { // / or >
+			end = i
+			break
+		}
+	}
+
+	z.pos = end
+	if end == start {
+		return htmlToken{}, false
+	}
+
+	typ := htmlTokenRawText
+	if htmlRCDATAElements[tag] {
+		typ = htmlTokenText
+	}
+
+	return htmlToken{Type: typ, Data: z.str[start:end], Raw: z.str[start:end]}, true
+}
+
+// readMarkup reads the markup at z.pos ('<'), false if nothing to emit
+func (z *htmlTokenizer) readMarkup() (htmlToken, bool) {
+	start := z.pos
+	s := z.str
+
+	switch c := s[start+1]; {
+	case isASCIIAlpha(c):
+		return z.readTag(start, start+1, false)
+
+	case c == 47: // /
+		if isASCIIAlpha(s[start+2]) {
+			return z.readTag(start, start+2, true)
+		}
+
+		if s[start+2] == 62 { // </> is ignored
+			z.pos = start + 3
+			return htmlToken{}, false
+		}
+
+		return z.readBogusComment(start, start+2)
+
+	case c == 63: // ?
+		return z.readBogusComment(start, start+1)
+	}
+
+	// <!
+	switch {
+	case strings.HasPrefix(s[start:], "<!--"):
+		return z.readComment(start)
+
+	case len(s) >= start+9 && strings.EqualFold(s[start:start+9], "<!doctype"):
+		tok, ok := z.readBogusComment(start, start+9)
+		tok.Type = htmlTokenDoctype
+		return tok, ok
+
+	case strings.HasPrefix(s[start:], "<![CDATA["):
+		end := strings.Index(s[start+9:], "]]>")
+		if end < 0 {
+			z.pos = len(s)
+			return htmlToken{Type: htmlTokenCDATA, Data: s[start+9:], Raw: s[start:]}, true
+		}
+
+		z.pos = start + 9 + end + 3
+		return htmlToken{Type: htmlTokenCDATA, Data: s[start+9 : start+9+end], Raw: s[start:z.pos]}, true
+	}
+
+	return z.readBogusComment(start, start+2)
+}
+
+// readComment reads <!-- ... --> (also --!> and abrupt <!--> , <!--->)
+func (z *htmlTokenizer) readComment(start int) (htmlToken, bool) {
+	s := z.str
+	i := start + 4
+
+	if strings.HasPrefix(s[i:], ">") {
+		z.pos = i + 1
+		return htmlToken{Type: htmlTokenComment, Raw: s[start:z.pos]}, true
+	}
+
+	if strings.HasPrefix(s[i:], "->") {
+		z.pos = i + 2
+		return htmlToken{Type: htmlTokenComment, Raw: s[start:z.pos]}, true
+	}
+
+	end, endlen := strings.Index(s[i:], "-->"), 3
+	if bang := strings.Index(s[i:], "--!>"); bang >= 0 && (end < 0 || bang < end) {
+		end, endlen = bang, 4
+	}
+
+	if end < 0 {
+		z.pos = len(s)
+		return htmlToken{Type: htmlTokenComment, Data: s[i:], Raw: s[start:]}, true
+	}
+
+	z.pos = i + end + endlen
+	return htmlToken{Type: htmlTokenComment, Data: s[i : i+end], Raw: s[start:z.pos]}, true
+}
+
+// readBogusComment reads until the first '>'
+func (z *htmlTokenizer) readBogusComment(start int, i int) (htmlToken, bool) {
+	s := z.str
+
+	end := strings.IndexByte(s[i:], 62) // >
+	if end < 0 {
+		z.pos = len(s)
+		return htmlToken{Type: htmlTokenComment, Data: s[i:], Raw: s[start:]}, true
+	}
+
+	z.pos = i + end + 1
+	return htmlToken{Type: htmlTokenComment, Data: s[i : i+end], Raw: s[start:z.pos]}, true
+}
+
+// readTag reads start or end tag with attributes (double-quoted, single-quoted, unquoted)
+func (z *htmlTokenizer) readTag(start int, i int, endTag bool) (htmlToken, bool) {
+	s := z.str
+	l := len(s)
+
+	tok := htmlToken{Type: htmlTokenStartTag}
+	if endTag {
+		tok.Type = htmlTokenEndTag
+	}
+
+	// tag name
+	n := i
+	for n < l && !isHTMLSpace(s[n]) && s[n] != 47 && s[n] != 62 { // / >
+		n++
+	}
+	tok.Data = strings.ToLower(s[i:n])
+	i = n
+
+	for {
+		for i < l && isHTMLSpace(s[i]) {
+			i++
+		}
+
+		if i >= l { // EOF in tag, drop it
+			z.pos = l
+			return htmlToken{}, false
+		}
+
+		if s[i] == 62 { // >
+			i++
+			break
+		}
+
+		if s[i] == 47 { // /
+			if i+1 < l && s[i+1] == 62 {
+				if tok.Type == htmlTokenStartTag {
+					tok.Type = htmlTokenSelfClosing
+				}
+				i += 2
+				break
+			}
+			i++
+			continue
+		}
+
+		// attribute name, '=' at first is a part of name
+		n = i + 1
+		for n < l && !isHTMLSpace(s[n]) && s[n] != 47 && s[n] != 62 && s[n] != 61 { // / > =
+			n++
+		}
+		attr := htmlAttr{Key: strings.ToLower(s[i:n])}
+		i = n
+
+		for i < l && isHTMLSpace(s[i]) {
+			i++
+		}
+
+		if i < l && s[i] == 61 { // =
+			i++
+			for i < l && isHTMLSpace(s[i]) {
+				i++
+			}
+
+			if i >= l {
+				z.pos = l
+				return htmlToken{}, false
+			}
+
+			switch q := s[i]; q {
+			case 34, 39: // " '
+				end := strings.IndexByte(s[i+1:], q)
+				if end < 0 {
+					z.pos = l
+					return htmlToken{}, false
+				}
+				attr.Val = decodeHTMLEntities(s[i+1 : i+1+end])
+				i += end + 2
+
+			default:
+				n = i
+				for n < l && !isHTMLSpace(s[n]) && s[n] != 62 {
+					n++
+				}
+				attr.Val = decodeHTMLEntities(s[i:n])
+				i = n
+			}
+		}
+
+		if !endTag {
+			tok.Attr = append(tok.Attr, attr)
+		}
+	}
+
+	z.pos = i
+	tok.Raw = s[start:i]
+
+	if tok.Type == htmlTokenStartTag || tok.Type == htmlTokenSelfClosing {
+		switch {
+		case htmlRawTextElements[tok.Data], htmlRCDATAElements[tok.Data]:
+			z.rawTag = tok.Data
+		case tok.Data == "plaintext":
+			z.plaintext = true
+		}
+	}
+
+	return tok, true
+}
+
+// stripTagsOnce removes all markup of str, and contents of script, style, comment.
+// returns true if any markup was found, and true if str ends in the text (not in a markup, raw text, rcdata or plaintext)
+func stripTagsOnce(str string) (string, bool, bool) {
+	buf := make([]byte, 0, len(str)) // prealloca
+	closed := false
+
+	z := newHTMLTokenizer(str)
+	for {
+		intext := z.rawTag == "" && !z.plaintext
+		tok, ok := z.next()
+		if !ok {
+			break
+		}
+
+		if tok.Type == htmlTokenText || tok.Type == htmlTokenCDATA {
+			buf = append(buf, tok.Data...)
+		}
+		closed = intext && tok.Type == htmlTokenText && z.pos == len(str)
+	}
+
+	// markup is always longer than 0, (a tag unterminated at EOF is dropped without a token)
+	return string(buf), len(buf) != len(str), closed && z.rawTag == "" && !z.plaintext
+}
+
+// stripTags removes all markup until the string has no more markup, so the output can't re-form a tag (<<b>script> => "")
+func stripTags(str string) string {
+	retval, _ := stripTagsClosed(str)
+	return retval
+}
+
+// stripTagsClosed is stripTags, and returns true if every pass ends in the text, so the string after str is stripped independently
+func stripTagsClosed(str string) (string, bool) {
+	closed := true
+	for {
+		tmpstr, found, intext := stripTagsOnce(str)
+		closed = closed && intext
+		if !found {
+			return tmpstr, closed
+		}
+		str = tmpstr
+	}
+}
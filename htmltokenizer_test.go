@@ -0,0 +1,114 @@
+package strutils_test
+
+import (
+	"testing"
+
+	strutils "github.com/torden/go-strutil"
+)
+
+func Test_strutils_StripTags_Tokenizer(t *testing.T) {
+	t.Parallel()
+
+	dataset := map[string]string{
+		// unknown or custom elements
+		"<my-widget>abc</my-widget>":       "abc",
+		"<math><mi>x</mi></math>":          "x",
+		"<template><p>abc</p></template>":  "abc",
+		"<svg><![CDATA[abc]]></svg>":       "abc",
+		"<x-y data-a=1>abc</x-y data-b=2>": "abc",
+		"<div<span>abc":                    "abc",
+		"<DIV CLASS=\"a\">abc</DIV>":       "abc",
+		"a<br/>b<br />c":                   "abc",
+		"a <b>b</b> c":                     "a b c",
+		"a < b > c":                        "a < b > c",
+		"1 << 2":                           "1 << 2",
+		"a</>b":                            "ab",
+		"a</ b>c":                          "ac",
+		"a<?php echo 1; ?>b":               "ab",
+		"a<!DOCTYPE html>b":                "ab",
+		"a<unterminated":                   "a",
+		"a<img src='x":                     "a",
+		"a</":                              "a</",
+		"a<":                               "a<",
+
+		// script, style and comment contents are dropped
+		"a<script>alert('<b>x</b>')</script>b":  "ab",
+		"a<SCRIPT type=x>alert(1)</SCRIPT >b":   "ab",
+		"a<script>alert(1)</scripty></script>b": "ab",
+		"a<script>alert(1)":                     "a",
+		"a<style>p{color:red}</style>b":         "ab",
+		"a<!-- comment <b>x</b> -->b":           "ab",
+		"a<!-->b":                               "ab",
+		"a<!--->b":                              "ab",
+		"a<!-- x --!>b":                         "ab",
+		"a<!-- unterminated":                    "a",
+		"a<iframe><b>x</b></iframe>b":           "ab",
+
+		// rcdata and plaintext, the contents are stripped again
+		"<title>a<b>c</title>":      "ac",
+		"<textarea><b></textarea>":  "",
+		"a<plaintext><b>x</b>":      "ax",
+		"<textarea>a<b</textarea>x": "a",
+
+		// '>' in attribute values
+		`<a title="x>y" href=x>abc</a>`:     "abc",
+		`<a title='x>y'>abc</a>`:            "abc",
+		`<img src=x onerror=alert(1)//>abc`: "abc",
+		`<img src="x" alt="<script>">abc`:   "abc",
+
+		// can't re-form a tag
+		"<<b>script>alert(1)<</b>/script>": "",
+		"<scr<b>ipt>alert(1)</scr<b>ipt>":  "ipt>alert(1)ipt>",
+		"<<b>b>x":                          "x",
+		"&lt;<b>script&gt;":                "",
+
+		// the length is changed by strings.ToLower (invalid UTF-8, İ, Ⱥ)
+		"<script>\xff\xff\xff\xff\xff\xff</script>x":      "x",
+		"<script>ȺȺȺȺ</script>alert(1)<script>x</script>": "alert(1)",
+		"<title>İİİİİİ</title><script>alert(1)</script>":  "İİİİİİ",
+		"<style>\xffȺİ</STYLE>b":                          "b",
+		"<title>\xffȺ</TiTlE>b":                           "\xffȺb",
+	}
+
+	// check : common
+	for k, v := range dataset {
+		retval, err := strproc.StripTags(k)
+		assert.AssertNil(t, err, "Error : %v", err)
+		assert.AssertEquals(t, retval, v, "Return Value mismatch (%v).\nExpected: %v\nActual: %v", k, v, retval)
+	}
+}
+
+func Test_strutils_StripTags_TokenizerToLower(t *testing.T) {
+	t.Parallel()
+
+	// the length is changed by strings.ToLower (invalid UTF-8, İ, Ⱥ)
+	dataset := map[string][]string{ // StripTagsWithPolicy, HTML2Text, HTML2Markdown
+		"<script>\xff\xff\xff\xff\xff\xff</script>x":      {"x", "x", "x"},
+		"<script>ȺȺȺȺ</script>alert(1)<script>x</script>": {"alert(1)", "alert(1)", "alert(1)"},
+		"<title>İİİİİİ</title><script>alert(1)</script>":  {"İİİİİİ", "", ""},
+		"<style>İİ</style><b>kept</b>":                    {"<b>kept</b>", "kept", "**kept**"},
+		"<textarea>ȺİȺ</textarea><script>x</script>kept":  {"ȺİȺkept", "ȺİȺkept", "ȺİȺkept"},
+	}
+
+	policy := strutils.NewUGCStripTagsPolicy()
+
+	// check : common
+	for k, v := range dataset {
+		retval, err := strproc.StripTagsWithPolicy(k, policy)
+		assert.AssertNil(t, err, "Error : %v", err)
+		assert.AssertEquals(t, retval, v[0], "Return Value mismatch (%q).\nExpected: %q\nActual: %q", k, v[0], retval)
+
+		retval, err = strproc.HTML2Text(k, strutils.LinkStyleInline)
+		assert.AssertNil(t, err, "Error : %v", err)
+		assert.AssertEquals(t, retval, v[1], "Return Value mismatch (%q).\nExpected: %q\nActual: %q", k, v[1], retval)
+
+		retval, err = strproc.HTML2Markdown(k)
+		assert.AssertNil(t, err, "Error : %v", err)
+		assert.AssertEquals(t, retval, v[2], "Return Value mismatch (%q).\nExpected: %q\nActual: %q", k, v[2], retval)
+
+		// check : same as StripTags
+		expected, _ := strproc.StripTags(k)
+		retval = streamString(t, strutils.NewStripTagsTransformer(), k, true)
+		assert.AssertEquals(t, retval, expected, "Return Value mismatch (%q).\nExpected: %q\nActual: %q", k, expected, retval)
+	}
+}
@@ -0,0 +1,268 @@
+// Command gen generates the grapheme_table.go from the Unicode character database (UCD)
+//
+//	go run ./internal/gen [-ucd url|dir] [-o grapheme_table.go] [-test testdata/GraphemeBreakTest.txt]
+//
+// the files of the UCD are auxiliary/GraphemeBreakProperty.txt, emoji/emoji-data.txt, DerivedCoreProperties.txt
+// and auxiliary/GraphemeBreakTest.txt (copied to the -test for the conformance test)
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const maxRune = 0x10ffff
+
+// graphemeProps is the constant name of the grapheme cluster break property (grapheme.go)
+var graphemeProps = map[string]string{
+	"CR":                    "graphemeCR",
+	"LF":                    "graphemeLF",
+	"Control":               "graphemeControl",
+	"Extend":                "graphemeExtend",
+	"ZWJ":                   "graphemeZWJ",
+	"Regional_Indicator":    "graphemeRI",
+	"Prepend":               "graphemePrepend",
+	"SpacingMark":           "graphemeSpacingMark",
+	"L":                     "graphemeL",
+	"V":                     "graphemeV",
+	"T":                     "graphemeT",
+	"LV":                    "graphemeLV",
+	"LVT":                   "graphemeLVT",
+	"Extended_Pictographic": "graphemeExtPict",
+	"InCB=Consonant":        "graphemeInCBConsonant",
+}
+
+var versionPattern = regexp.MustCompile(`-(\d+\.\d+\.\d+)\.txt`)
+
+// open is open the file of the UCD, the ucd is the URL or the directory
+func open(ucd string, name string) (io.ReadCloser, error) {
+	if !strings.HasPrefix(ucd, "http://") && !strings.HasPrefix(ucd, "https://") {
+		return os.Open(filepath.Join(ucd, filepath.FromSlash(name)))
+	}
+
+	resp, err := http.Get(strings.TrimSuffix(ucd, "/") + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("%v : %v", name, resp.Status)
+	}
+
+	return resp.Body, nil
+}
+
+// parse calls fn with the code point range and the fields of the each line, returns the version of the file
+// 0600..0605    ; Prepend # Cf   [6] ARABIC NUMBER SIGN..ARABIC NUMBER MARK ABOVE
+func parse(ucd string, name string, fn func(lo rune, hi rune, fields []string) error) (string, error) {
+	fd, err := open(ucd, name)
+	if err != nil {
+		return "", err
+	}
+	defer fd.Close()
+
+	var version string
+	scanner := bufio.NewScanner(fd)
+	for no := 1; scanner.Scan(); no++ {
+		line := scanner.Text()
+		if m := versionPattern.FindStringSubmatch(line); version == "" && m != nil && strings.HasPrefix(line, "#") {
+			version = m[1]
+		}
+
+		if i := strings.IndexByte(line, 35); i >= 0 { // #
+			line = line[:i]
+		}
+
+		if len(strings.TrimSpace(line)) == 0 {
+			continue
+		}
+
+		fields := strings.Split(line, ";")
+		for i := range fields {
+			fields[i] = strings.TrimSpace(fields[i])
+		}
+
+		lo, hi, err := parseRange(fields[0])
+		if err != nil {
+			return "", fmt.Errorf("%v:%v : %v", name, no, err)
+		}
+
+		if err = fn(lo, hi, fields); err != nil {
+			return "", fmt.Errorf("%v:%v : %v", name, no, err)
+		}
+	}
+
+	if err = scanner.Err(); err != nil {
+		return "", fmt.Errorf("%v : %v", name, err)
+	}
+
+	return version, nil
+}
+
+// parseRange is parse the code point (0600) or the range (0600..0605)
+func parseRange(str string) (rune, rune, error) {
+	los, his := str, str
+	if i := strings.Index(str, ".."); i >= 0 {
+		los, his = str[:i], str[i+2:]
+	}
+
+	lo, err := strconv.ParseUint(los, 16, 32)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	hi, err := strconv.ParseUint(his, 16, 32)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if lo > hi || hi > maxRune {
+		return 0, 0, fmt.Errorf("Not allow range %q", str)
+	}
+
+	return rune(lo), rune(hi), nil
+}
+
+// writeTable writes the ranges of the same value, the empty value is not listed
+func writeTable(buf *bytes.Buffer, comment string, name string, props []string) {
+	fmt.Fprintf(buf, "\n// %s\nvar %s = []graphemeRange{\n", comment, name)
+	for lo := 0; lo <= maxRune; {
+		hi := lo
+		for hi < maxRune && props[hi+1] == props[lo] {
+			hi++
+		}
+
+		if len(props[lo]) > 0 {
+			fmt.Fprintf(buf, "\t{0x%X, 0x%X, %s},\n", lo, hi, props[lo])
+		}
+
+		lo = hi + 1
+	}
+
+	buf.WriteString("}\n")
+}
+
+func generate(ucd string, output string, test string) error {
+	props := make([]string, maxRune+1)
+	extend := make([]string, maxRune+1)
+	linker := make([]string, maxRune+1)
+
+	set := func(lo rune, hi rune, prop string) error {
+		for r := lo; r <= hi; r++ {
+			if r >= 0xd800 && r <= 0xdfff { // the surrogates are not the rune of the Go string
+				continue
+			}
+
+			if len(props[r]) > 0 && props[r] != prop {
+				return fmt.Errorf("U+%04X is %v and %v", r, props[r], prop)
+			}
+
+			props[r] = prop
+		}
+
+		return nil
+	}
+
+	version, err := parse(ucd, "auxiliary/GraphemeBreakProperty.txt", func(lo rune, hi rune, fields []string) error {
+		prop, ok := graphemeProps[fields[1]]
+		if !ok {
+			return fmt.Errorf("Not support property %q", fields[1])
+		}
+
+		return set(lo, hi, prop)
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = parse(ucd, "emoji/emoji-data.txt", func(lo rune, hi rune, fields []string) error {
+		if fields[1] != "Extended_Pictographic" {
+			return nil
+		}
+
+		return set(lo, hi, graphemeProps[fields[1]])
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = parse(ucd, "DerivedCoreProperties.txt", func(lo rune, hi rune, fields []string) error {
+		if fields[1] != "InCB" || len(fields) < 3 {
+			return nil
+		}
+
+		switch fields[2] {
+		case "Consonant":
+			return set(lo, hi, graphemeProps["InCB=Consonant"])
+		case "Extend":
+			for r := lo; r <= hi; r++ {
+				extend[r] = "graphemeExtend"
+			}
+		case "Linker":
+			for r := lo; r <= hi; r++ {
+				linker[r] = "graphemeExtend"
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by internal/gen from the Unicode %v character database (GraphemeBreakProperty.txt, emoji-data.txt, DerivedCoreProperties.txt). DO NOT EDIT.\n\n", version)
+	buf.WriteString("package strutils\n")
+	writeTable(&buf, "ranges of the grapheme cluster break property (and Extended_Pictographic, InCB=Consonant), sorted. not listed is graphemeAny", "graphemeBreakTable", props)
+	writeTable(&buf, "ranges of InCB=Extend (Indic_Conjunct_Break), sorted", "graphemeInCBExtendTable", extend)
+	writeTable(&buf, "ranges of InCB=Linker (Indic_Conjunct_Break), sorted", "graphemeInCBLinkerTable", linker)
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return err
+	}
+
+	if err = os.WriteFile(output, src, 0644); err != nil {
+		return err
+	}
+
+	if len(test) == 0 {
+		return nil
+	}
+
+	fd, err := open(ucd, "auxiliary/GraphemeBreakTest.txt")
+	if err != nil {
+		return err
+	}
+	defer fd.Close()
+
+	data, err := io.ReadAll(fd)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(test, data, 0644)
+}
+
+func main() {
+	ucd := flag.String("ucd", "https://www.unicode.org/Public/16.0.0/ucd", "URL or directory of the Unicode character database")
+	output := flag.String("o", "grapheme_table.go", "output file")
+	test := flag.String("test", "testdata/GraphemeBreakTest.txt", "output of GraphemeBreakTest.txt, not copied if empty")
+	flag.Parse()
+
+	if err := generate(*ucd, *output, *test); err != nil {
+		fmt.Fprintf(os.Stderr, "gen: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,376 @@
+package strutils
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// Manifest format control for CreateManifest
+const (
+	ManifestGNU = uint8(iota) // GNU coreutils (sha256sum) format, <digest>  <file>
+	ManifestBSD               // BSD tag format (sha256sum --tag), SHA256 (<file>) = <digest>
+)
+
+// Manifest verify status of ManifestResult
+const (
+	_               = uint8(iota)
+	ManifestOK      // the digest is matched
+	ManifestFailed  // the digest is not matched, or couldn't read the file
+	ManifestMissing // the file is not exists
+)
+
+var manifestStatusNames = map[uint8]string{
+	ManifestOK:      "OK",
+	ManifestFailed:  "FAILED",
+	ManifestMissing: "MISSING",
+}
+
+var (
+	manifestGNUPattern = regexp.MustCompile(`^(\\?)([0-9A-Fa-f]+) ([ *])(.+)$`)
+	manifestBSDPattern = regexp.MustCompile(`^(\\?)([A-Za-z0-9/-]+) ?\((.+)\) ?= ([0-9A-Fa-f]+)$`)
+)
+
+// ManifestEntry is a line of the checksum manifest
+type ManifestEntry struct {
+	Path   string // file path, slash separated
+	Algo   uint8  // hash algorithm
+	Digest string // lower case hex digest
+	Binary bool   // binary mode ('*' of GNU format)
+}
+
+// ManifestResult is the verify result of a ManifestEntry
+type ManifestResult struct {
+	ManifestEntry
+	Status uint8  // ManifestOK, ManifestFailed, ManifestMissing
+	Actual string // digest of the file, empty if couldn't read
+	Err    error  // read error
+}
+
+// StatusName returns the name of the status (OK, FAILED, MISSING)
+func (m *ManifestResult) StatusName() string {
+	return manifestStatusNames[m.Status]
+}
+
+// manifestAlgo is return the algorithm of the name of BSD tag format
+func manifestAlgo(name string) (uint8, bool) {
+	for k, v := range hashAlgorithms {
+		if strings.EqualFold(v.name, name) {
+			return k, true
+		}
+	}
+
+	return 0, false
+}
+
+// manifestAlgoByLen is guess the algorithm by the length of the hex digest (GNU format has no algorithm name)
+func manifestAlgoByLen(digest string) (uint8, bool) {
+	switch len(digest) {
+	case 32:
+		return HashMD5, true
+	case 40:
+		return HashSHA1, true
+	case 56:
+		return HashSHA224, true
+	case 64:
+		return HashSHA256, true
+	case 96:
+		return HashSHA384, true
+	case 128:
+		return HashSHA512, true
+	}
+
+	return 0, false
+}
+
+// manifestEscape is escape the file name like coreutils, the line starts with '\' if escaped
+func manifestEscape(path string) (string, bool) {
+	if !strings.ContainsAny(path, "\\\n\r") {
+		return path, false
+	}
+
+	return strings.NewReplacer("\\", "\\\\", "\n", "\\n", "\r", "\\r").Replace(path), true
+}
+
+// manifestUnescape is reverse of manifestEscape
+func manifestUnescape(path string) (string, error) {
+	var buf strings.Builder
+	for i := 0; i < len(path); i++ {
+		if path[i] != 92 { // \
+			buf.WriteByte(path[i])
+			continue
+		}
+
+		i++
+		if i >= len(path) {
+			return "", fmt.Errorf("Not allow escape sequence at %v", i)
+		}
+
+		switch path[i] {
+		case 92: // \
+			buf.WriteByte(92)
+		case 110: // n
+			buf.WriteByte(10)
+		case 114: // r
+			buf.WriteByte(13)
+		default:
+			return "", fmt.Errorf("Not allow escape sequence \"\\%c\" at %v", path[i], i)
+		}
+	}
+
+	return buf.String(), nil
+}
+
+// ParseManifest is parse a checksum manifest of GNU coreutils format or BSD tag format (mixed is ok)
+// the algo is used for the GNU format lines, guessed by the length of digest if 0 (MD5, SHA1, SHA224, SHA256, SHA384, SHA512)
+// the empty lines and the comments (#) are skipped
+func (s *StringProc) ParseManifest(str string, algo uint8) ([]ManifestEntry, error) {
+	if _, ok := hashAlgorithms[algo]; algo != 0 && !ok {
+		return nil, fmt.Errorf("Not allow algo parameter : %v", algo)
+	}
+
+	retval := []ManifestEntry{}
+	for no, line := range strings.Split(str, "\n") {
+		line = strings.TrimSuffix(line, "\r")
+		if len(strings.TrimSpace(line)) == 0 || line[0] == 35 { // #
+			continue
+		}
+
+		var entry ManifestEntry
+		var escaped bool
+
+		if m := manifestBSDPattern.FindStringSubmatch(line); m != nil {
+			a, ok := manifestAlgo(m[2])
+			if !ok {
+				return nil, fmt.Errorf("Not allow algorithm %q at line %v", m[2], no+1)
+			}
+
+			escaped = len(m[1]) > 0
+			entry = ManifestEntry{Path: m[3], Algo: a, Digest: strings.ToLower(m[4]), Binary: true}
+		} else if m := manifestGNUPattern.FindStringSubmatch(line); m != nil {
+			a := algo
+			if a == 0 {
+				var ok bool
+				if a, ok = manifestAlgoByLen(m[2]); !ok {
+					return nil, fmt.Errorf("Not allow digest length %v at line %v", len(m[2]), no+1)
+				}
+			}
+
+			escaped = len(m[1]) > 0
+			entry = ManifestEntry{Path: m[4], Algo: a, Digest: strings.ToLower(m[2]), Binary: m[3] == "*"}
+		} else {
+			return nil, fmt.Errorf("Not allow manifest line at line %v : %q", no+1, line)
+		}
+
+		if escaped {
+			path, err := manifestUnescape(entry.Path)
+			if err != nil {
+				return nil, fmt.Errorf("%v at line %v", err, no+1)
+			}
+
+			entry.Path = path
+		}
+
+		retval = append(retval, entry)
+	}
+
+	return retval, nil
+}
+
+// FormatManifest is format the entries as a checksum manifest of GNU coreutils format or BSD tag format
+func (s *StringProc) FormatManifest(entries []ManifestEntry, format uint8) (string, error) {
+	if format != ManifestGNU && format != ManifestBSD {
+		return "", fmt.Errorf("Not allow format parameter : %v", format)
+	}
+
+	var buf strings.Builder
+	for _, v := range entries {
+		alg, ok := hashAlgorithms[v.Algo]
+		if !ok {
+			return "", fmt.Errorf("Not allow algo of %q : %v", v.Path, v.Algo)
+		}
+
+		path, escaped := manifestEscape(v.Path)
+		if escaped {
+			buf.WriteByte(92) // \
+		}
+
+		if format == ManifestBSD {
+			buf.WriteString(alg.name + " (" + path + ") = " + v.Digest + "\n")
+			continue
+		}
+
+		mode := " "
+		if v.Binary {
+			mode = "*"
+		}
+
+		buf.WriteString(v.Digest + " " + mode + path + "\n")
+	}
+
+	return buf.String(), nil
+}
+
+// manifestLocalPath returns whether the path of the manifest is under the dir, not absolute and without '..'
+// NOTE : the manifest may be untrusted, the file outside the dir shouldn't be read
+func manifestLocalPath(path string) bool {
+	native := filepath.FromSlash(path)
+	if len(path) == 0 || path[0] == 47 || filepath.IsAbs(native) || len(filepath.VolumeName(native)) > 0 { // /
+		return false
+	}
+
+	for _, v := range strings.Split(filepath.ToSlash(native), "/") {
+		if v == ".." {
+			return false
+		}
+	}
+
+	return true
+}
+
+// manifestHashes is checksum of the entries with the bounded worker pool, the digest or the error is set in the results
+func (s *StringProc) manifestHashes(ctx context.Context, dir string, results []ManifestResult, workers int) error {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	jobs := make(chan int)
+	wg := sync.WaitGroup{}
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for no := range jobs {
+				r := &results[no]
+
+				if !manifestLocalPath(r.Path) {
+					r.Err = fmt.Errorf("Not allow path outside the dir : %q", r.Path)
+					continue
+				}
+
+				path := filepath.Join(dir, filepath.FromSlash(r.Path))
+				sums, err := s.FileHashes(ctx, path, []uint8{r.Algo}, nil)
+				if err != nil {
+					r.Err = err
+					continue
+				}
+
+				r.Actual = sums[r.Algo]
+			}
+		}()
+	}
+
+	for no := range results {
+		if ctx.Err() != nil {
+			break
+		}
+
+		jobs <- no
+	}
+
+	close(jobs)
+	wg.Wait()
+
+	return ctx.Err()
+}
+
+// CreateManifest is checksum of the all regular files under the dir, and return the checksum manifest of the format
+// the paths are relative to the dir and slash separated, in lexical order (the symbolic links are not followed)
+// the files are hashed in parallel by the workers (runtime.NumCPU() if 0), and stopped when the ctx is canceled
+func (s *StringProc) CreateManifest(ctx context.Context, dir string, algo uint8, format uint8, workers int) (string, error) {
+	if _, ok := hashAlgorithms[algo]; !ok {
+		return "", fmt.Errorf("Not allow algo parameter : %v", algo)
+	}
+
+	if format != ManifestGNU && format != ManifestBSD {
+		return "", fmt.Errorf("Not allow format parameter : %v", format)
+	}
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	results := []ManifestResult{}
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+
+		results = append(results, ManifestResult{ManifestEntry: ManifestEntry{Path: filepath.ToSlash(rel), Algo: algo}})
+		return nil
+	})
+
+	if err != nil {
+		return "", err
+	}
+
+	if err := s.manifestHashes(ctx, dir, results, workers); err != nil {
+		return "", err
+	}
+
+	entries := make([]ManifestEntry, 0, len(results))
+	for _, v := range results {
+		if v.Err != nil {
+			return "", v.Err
+		}
+
+		v.Digest = v.Actual
+		entries = append(entries, v.ManifestEntry)
+	}
+
+	return s.FormatManifest(entries, format)
+}
+
+// VerifyManifest is verify the all entries of the checksum manifest, the paths are relative to the dir
+// the absolute paths and the paths with '..' are ManifestFailed (the Err is set), the files outside the dir aren't read
+// the result is in the order of the manifest, the status is ManifestOK, ManifestFailed or ManifestMissing
+// the files are hashed in parallel by the workers (runtime.NumCPU() if 0), and stopped when the ctx is canceled
+func (s *StringProc) VerifyManifest(ctx context.Context, manifest string, dir string, algo uint8, workers int) ([]ManifestResult, error) {
+	entries, err := s.ParseManifest(manifest, algo)
+	if err != nil {
+		return nil, err
+	}
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	results := make([]ManifestResult, len(entries))
+	for i, v := range entries {
+		results[i].ManifestEntry = v
+	}
+
+	if err := s.manifestHashes(ctx, dir, results, workers); err != nil {
+		return nil, err
+	}
+
+	for i := range results {
+		r := &results[i]
+		switch {
+		case os.IsNotExist(r.Err):
+			r.Status = ManifestMissing
+		case r.Err == nil && r.Actual == r.Digest:
+			r.Status = ManifestOK
+		default:
+			r.Status = ManifestFailed
+		}
+	}
+
+	return results, nil
+}
@@ -0,0 +1,208 @@
+package strutils_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	strutils "github.com/torden/go-strutil"
+)
+
+// manifestTestDir is create a directory for the manifest tests
+func manifestTestDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "strutils-manifest")
+	assert.AssertNil(t, err, "Error : %v", err)
+
+	files := map[string]string{
+		"a.txt":         "abcdefg",
+		"b/c.txt":       "0123456789",
+		"b/d/e f.bin":   "",
+		"back\\sl.txt":  "x",
+		"new\nline.txt": "y",
+	}
+
+	for k, v := range files {
+		path := filepath.Join(dir, filepath.FromSlash(k))
+		err = os.MkdirAll(filepath.Dir(path), 0750)
+		assert.AssertNil(t, err, "Error : %v", err)
+
+		err = ioutil.WriteFile(path, []byte(v), 0640)
+		assert.AssertNil(t, err, "Error : %v", err)
+	}
+
+	return dir
+}
+
+func Test_strutils_CreateManifest(t *testing.T) {
+	t.Parallel()
+
+	dir := manifestTestDir(t)
+	defer os.RemoveAll(dir)
+
+	dataset := map[uint8]string{
+		strutils.ManifestGNU: "7ac66c0f148de9519b8bd264312c4d64  a.txt\n" +
+			"781e5e245d69b566979b86e28d23f2c7  b/c.txt\n" +
+			"d41d8cd98f00b204e9800998ecf8427e  b/d/e f.bin\n" +
+			"\\9dd4e461268c8034f5c8564e155c67a6  back\\\\sl.txt\n" +
+			"\\415290769594460e2e485922904f345d  new\\nline.txt\n",
+		strutils.ManifestBSD: "MD5 (a.txt) = 7ac66c0f148de9519b8bd264312c4d64\n" +
+			"MD5 (b/c.txt) = 781e5e245d69b566979b86e28d23f2c7\n" +
+			"MD5 (b/d/e f.bin) = d41d8cd98f00b204e9800998ecf8427e\n" +
+			"\\MD5 (back\\\\sl.txt) = 9dd4e461268c8034f5c8564e155c67a6\n" +
+			"\\MD5 (new\\nline.txt) = 415290769594460e2e485922904f345d\n",
+	}
+
+	// check : common
+	for k, v := range dataset {
+		retval, err := strproc.CreateManifest(context.Background(), dir, strutils.HashMD5, k, 2)
+		assert.AssertNil(t, err, "Error : %v", err)
+		assert.AssertEquals(t, retval, v, "Return Value mismatch (%v).\nExpected: %q\nActual: %q", k, v, retval)
+	}
+
+	// check : canceled
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := strproc.CreateManifest(ctx, dir, strutils.HashSHA256, strutils.ManifestGNU, 0)
+	assert.AssertEquals(t, err, context.Canceled, "Couldn't check the `canceled`\nError : %v", err)
+
+	// check : not allow algo, format, dir
+	_, err = strproc.CreateManifest(context.Background(), dir, 0, strutils.ManifestGNU, 0)
+	assert.AssertNotNil(t, err, "Couldn't check the `not allow algo`\nError : %v", err)
+
+	_, err = strproc.CreateManifest(context.Background(), dir, strutils.HashSHA256, 5, 0)
+	assert.AssertNotNil(t, err, "Couldn't check the `not allow format`\nError : %v", err)
+
+	_, err = strproc.CreateManifest(context.Background(), "./HELLO_GOLANG", strutils.HashSHA256, strutils.ManifestGNU, 0)
+	assert.AssertNotNil(t, err, "Couldn't check the `not exists dir`\nError : %v", err)
+}
+
+func Test_strutils_ParseManifest(t *testing.T) {
+	t.Parallel()
+
+	manifest := "# comment\n" +
+		"7AC66C0F148DE9519B8BD264312C4D64 *a.txt\r\n" +
+		"\n" +
+		"SHA1 (b/c.txt) = 87acec17cd9dcd20a716cc2cf67417b71c8a7016\n" +
+		"SHA256 (x (1).txt) = 7d1a54127b222502f5b79b5fb0803061152a44f92b37e23c6527baf665d4da9a\n" +
+		"\\d41d8cd98f00b204e9800998ecf8427e  new\\nline\\\\.txt\n"
+
+	expected := []strutils.ManifestEntry{
+		{Path: "a.txt", Algo: strutils.HashMD5, Digest: "7ac66c0f148de9519b8bd264312c4d64", Binary: true},
+		{Path: "b/c.txt", Algo: strutils.HashSHA1, Digest: "87acec17cd9dcd20a716cc2cf67417b71c8a7016", Binary: true},
+		{Path: "x (1).txt", Algo: strutils.HashSHA256, Digest: "7d1a54127b222502f5b79b5fb0803061152a44f92b37e23c6527baf665d4da9a", Binary: true},
+		{Path: "new\nline\\.txt", Algo: strutils.HashMD5, Digest: "d41d8cd98f00b204e9800998ecf8427e", Binary: false},
+	}
+
+	// check : common
+	retval, err := strproc.ParseManifest(manifest, 0)
+	assert.AssertNil(t, err, "Error : %v", err)
+	assert.AssertEquals(t, retval, expected, "Return Value mismatch.\nExpected: %v\nActual: %v", expected, retval)
+
+	// check : format
+	formatted, err := strproc.FormatManifest(retval, strutils.ManifestBSD)
+	assert.AssertNil(t, err, "Error : %v", err)
+
+	retval, err = strproc.ParseManifest(formatted, 0)
+	assert.AssertNil(t, err, "Error : %v", err)
+	for i := range expected {
+		expected[i].Binary = true
+	}
+	assert.AssertEquals(t, retval, expected, "Return Value mismatch.\nExpected: %v\nActual: %v", expected, retval)
+
+	// check : algo of the GNU format
+	retval, err = strproc.ParseManifest("0123456789abcdef  a.txt", strutils.HashCRC64ECMA)
+	assert.AssertNil(t, err, "Error : %v", err)
+	assert.AssertEquals(t, retval[0].Algo, strutils.HashCRC64ECMA, "Return Value mismatch.\nExpected: %v\nActual: %v", strutils.HashCRC64ECMA, retval[0].Algo)
+
+	// check : malformed
+	malformed := []string{
+		"not a manifest line",
+		"0123456789abcdef  a.txt",
+		"7ac66c0f148de9519b8bd264312c4d64a.txt",
+		"WHIRLPOOL (a.txt) = 7ac66c0f148de9519b8bd264312c4d64",
+		"\\7ac66c0f148de9519b8bd264312c4d64  a\\t.txt",
+	}
+
+	for _, v := range malformed {
+		_, err := strproc.ParseManifest(v, 0)
+		assert.AssertNotNil(t, err, "Couldn't check the `malformed` (%q)\nError : %v", v, err)
+	}
+
+	_, err = strproc.ParseManifest(manifest, 100)
+	assert.AssertNotNil(t, err, "Couldn't check the `not allow algo`\nError : %v", err)
+
+	_, err = strproc.FormatManifest(expected, 5)
+	assert.AssertNotNil(t, err, "Couldn't check the `not allow format`\nError : %v", err)
+}
+
+func Test_strutils_VerifyManifest(t *testing.T) {
+	t.Parallel()
+
+	dir := manifestTestDir(t)
+	defer os.RemoveAll(dir)
+
+	manifest, err := strproc.CreateManifest(context.Background(), dir, strutils.HashSHA256, strutils.ManifestGNU, 0)
+	assert.AssertNil(t, err, "Error : %v", err)
+
+	// check : common
+	retval, err := strproc.VerifyManifest(context.Background(), manifest, dir, strutils.HashSHA256, 3)
+	assert.AssertNil(t, err, "Error : %v", err)
+	assert.AssertEquals(t, len(retval), 5, "Return Value mismatch.\nExpected: %v\nActual: %v", 5, len(retval))
+	for _, v := range retval {
+		assert.AssertEquals(t, v.Status, strutils.ManifestOK, "Return Value mismatch (%q).\nExpected: %v\nActual: %v", v.Path, "OK", v.StatusName())
+	}
+
+	// check : failed, missing
+	err = ioutil.WriteFile(filepath.Join(dir, "a.txt"), []byte("modified"), 0640)
+	assert.AssertNil(t, err, "Error : %v", err)
+
+	err = os.Remove(filepath.Join(dir, "b", "c.txt"))
+	assert.AssertNil(t, err, "Error : %v", err)
+
+	expected := map[string]string{
+		"a.txt":         "FAILED",
+		"b/c.txt":       "MISSING",
+		"b/d/e f.bin":   "OK",
+		"back\\sl.txt":  "OK",
+		"new\nline.txt": "OK",
+	}
+
+	retval, err = strproc.VerifyManifest(context.Background(), manifest, dir, 0, 0)
+	assert.AssertNil(t, err, "Error : %v", err)
+	for _, v := range retval {
+		assert.AssertEquals(t, v.StatusName(), expected[v.Path], "Return Value mismatch (%q).\nExpected: %v\nActual: %v", v.Path, expected[v.Path], v.StatusName())
+	}
+
+	// check : outside the dir
+	sum, err := strproc.Hash("abcdefg", strutils.HashSHA256)
+	assert.AssertNil(t, err, "Error : %v", err)
+
+	outside := []string{
+		"../a.txt",
+		"d/../../a.txt",
+		"..",
+		filepath.ToSlash(filepath.Join(dir, "a.txt")),
+		"/" + filepath.ToSlash(filepath.Join(dir, "a.txt")),
+	}
+
+	for _, v := range outside {
+		path, _ := strproc.FormatManifest([]strutils.ManifestEntry{{Path: v, Algo: strutils.HashSHA256, Digest: sum}}, strutils.ManifestGNU)
+		retval, err = strproc.VerifyManifest(context.Background(), path, filepath.Join(dir, "b"), 0, 0)
+		assert.AssertNil(t, err, "Error : %v", err)
+		assert.AssertEquals(t, retval[0].StatusName(), "FAILED", "Return Value mismatch (%q).\nExpected: %v\nActual: %v", v, "FAILED", retval[0].StatusName())
+		assert.AssertNotNil(t, retval[0].Err, "Couldn't check the `outside the dir` (%q)", v)
+		assert.AssertEquals(t, retval[0].Actual, "", "Return Value mismatch (%q).\nExpected: %v\nActual: %v", v, "", retval[0].Actual)
+	}
+
+	// check : canceled
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = strproc.VerifyManifest(ctx, manifest, dir, 0, 0)
+	assert.AssertEquals(t, err, context.Canceled, "Couldn't check the `canceled`\nError : %v", err)
+
+	// check : malformed
+	_, err = strproc.VerifyManifest(context.Background(), "malformed", dir, 0, 0)
+	assert.AssertNotNil(t, err, "Couldn't check the `malformed`\nError : %v", err)
+}
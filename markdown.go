@@ -0,0 +1,838 @@
+package strutils
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	markdownATXPattern      = regexp.MustCompile(`^ {0,3}(#{1,6})(?:[ \t]+(.*?))?(?:[ \t]+#+)?[ \t]*$`)
+	markdownHRPattern       = regexp.MustCompile(`^ {0,3}(?:(?:\*[ \t]*){3,}|(?:-[ \t]*){3,}|(?:_[ \t]*){3,})$`)
+	markdownSetextPattern   = regexp.MustCompile(`^ {0,3}(?:=+|-+)[ \t]*$`)
+	markdownFencePattern    = regexp.MustCompile("^( {0,3})(`{3,}|~{3,})(.*)$")
+	markdownAutolinkPattern = regexp.MustCompile(`^<([A-Za-z][A-Za-z0-9+.\-]{1,31}:[^<>\x00-\x20]*)>`)
+	markdownEmailPattern    = regexp.MustCompile("^<([A-Za-z0-9.!#$%&'*+/=?^_`{|}~-]+@[A-Za-z0-9](?:[A-Za-z0-9-]{0,61}[A-Za-z0-9])?(?:\\.[A-Za-z0-9](?:[A-Za-z0-9-]{0,61}[A-Za-z0-9])?)*)>")
+)
+
+// allowed elements of Markdown2HTML, the output is sanitized with this
+var markdownPolicy = &StripTagsPolicy{
+	Elements: map[string][]string{
+		"a":          {"href", "title"},
+		"blockquote": nil,
+		"br":         nil,
+		"code":       {"class"},
+		"em":         nil,
+		"h1":         nil,
+		"h2":         nil,
+		"h3":         nil,
+		"h4":         nil,
+		"h5":         nil,
+		"h6":         nil,
+		"hr":         nil,
+		"img":        {"src", "alt", "title"},
+		"li":         nil,
+		"ol":         {"start"},
+		"p":          nil,
+		"pre":        nil,
+		"strong":     nil,
+		"ul":         nil,
+	},
+	URLSchemes:       []string{"http", "https", "mailto"},
+	AllowRelativeURL: true,
+}
+
+// markdownLines splits str into lines, tabs of the indentation are expanded to spaces (tab stop 4)
+func markdownLines(str string) []string {
+	str = strings.NewReplacer("\r\n", "\n", "\r", "\n").Replace(str)
+
+	lines := strings.Split(str, "\n")
+	for k, v := range lines {
+		if !strings.Contains(v, "\t") {
+			continue
+		}
+
+		buf := make([]byte, 0, len(v)+8)
+		i := 0
+		for ; i < len(v) && (v[i] == 32 || v[i] == 9); i++ {
+			if v[i] == 9 {
+				buf = append(buf, strings.Repeat(" ", 4-len(buf)%4)...)
+			} else {
+				buf = append(buf, 32)
+			}
+		}
+		lines[k] = string(append(buf, v[i:]...))
+	}
+
+	return lines
+}
+
+func markdownIndent(line string) int {
+	n := 0
+	for n < len(line) && line[n] == 32 {
+		n++
+	}
+
+	return n
+}
+
+func markdownBlank(line string) bool {
+	return strings.TrimSpace(line) == ""
+}
+
+// markdownPunct returns true if r is the punctuation of CommonMark (ASCII punctuation, unicode punctuation and symbol)
+func markdownPunct(r rune) bool {
+	if r < 128 {
+		return strings.ContainsRune("!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", r)
+	}
+
+	return unicode.IsPunct(r) || unicode.IsSymbol(r)
+}
+
+// markdownUnescape processes backslash escapes and character references of link destination and title
+func markdownUnescape(str string) string {
+	buf := make([]byte, 0, len(str))
+	for i := 0; i < len(str); i++ {
+		if str[i] == 92 && i+1 < len(str) && str[i+1] < 128 && markdownPunct(rune(str[i+1])) { // backslash
+			i++
+		}
+		buf = append(buf, str[i])
+	}
+
+	return decodeHTMLEntities(string(buf))
+}
+
+// markdownListMarker parses the list marker of the line
+// returns the kind of the marker (- + * . or ) of ordered list), the start number of ordered list, the width of the marker with indent
+func markdownListMarker(line string) (byte, int, int, bool) {
+	i := markdownIndent(line)
+	if i > 3 || i >= len(line) {
+		return 0, 0, 0, false
+	}
+
+	var kind byte
+	start := 0
+
+	switch c := line[i]; {
+	case c == 45 || c == 43 || c == 42: // - + *
+		kind = c
+		i++
+
+	case c >= 48 && c <= 57:
+		j := i
+		for ; j < len(line) && j-i < 10 && line[j] >= 48 && line[j] <= 57; j++ {
+		}
+
+		if j-i > 9 || j >= len(line) || (line[j] != 46 && line[j] != 41) { // . )
+			return 0, 0, 0, false
+		}
+
+		start, _ = strconv.Atoi(line[i:j])
+		kind = line[j]
+		i = j + 1
+
+	default:
+		return 0, 0, 0, false
+	}
+
+	if i == len(line) { // empty item
+		return kind, start, i + 1, true
+	}
+
+	if line[i] != 32 {
+		return 0, 0, 0, false
+	}
+
+	n := markdownIndent(line[i:])
+	if n > 4 || i+n == len(line) { // indented code in the item
+		return kind, start, i + 1, true
+	}
+
+	return kind, start, i + n, true
+}
+
+// markdownFence parses the opening code fence, returns the indent, the fence and the info string
+func markdownFence(line string) (int, string, string, bool) {
+	m := markdownFencePattern.FindStringSubmatch(line)
+	if m == nil || (m[2][0] == 96 && strings.Contains(m[3], "`")) {
+		return 0, "", "", false
+	}
+
+	return len(m[1]), m[2], strings.TrimSpace(m[3]), true
+}
+
+func markdownFenceClose(line string, fence string) bool {
+	str := strings.TrimSpace(line)
+	return markdownIndent(line) < 4 && len(str) >= len(fence) && strings.Trim(str, fence[:1]) == ""
+}
+
+// markdownLanguage returns the language of the info string, for the class of code element
+func markdownLanguage(info string) string {
+	if n := strings.IndexAny(info, " \t"); n >= 0 {
+		info = info[:n]
+	}
+
+	for _, c := range info {
+		if !(c < 128 && (isEntityAlnum(byte(c)) || c == 45 || c == 95 || c == 43 || c == 46 || c == 35)) { // - _ + . #
+			return ""
+		}
+	}
+
+	return info
+}
+
+// markdownInterrupts returns true if the line starts a block that interrupts a paragraph
+func markdownInterrupts(line string) bool {
+	if markdownATXPattern.MatchString(line) || markdownHRPattern.MatchString(line) {
+		return true
+	}
+
+	if _, _, _, ok := markdownFence(line); ok {
+		return true
+	}
+
+	if markdownIndent(line) < 4 && strings.HasPrefix(strings.TrimLeft(line, " "), ">") {
+		return true
+	}
+
+	// only not empty item, ordered list must start with 1
+	if kind, start, width, ok := markdownListMarker(line); ok && width < len(line) && !markdownBlank(line[width:]) {
+		return kind == 45 || kind == 43 || kind == 42 || start == 1
+	}
+
+	return false
+}
+
+// appendMarkdownBlocks appends the html of the block elements, paragraphs are written without <p> in the tight list
+func appendMarkdownBlocks(buf []byte, lines []string, tight bool) []byte {
+	for i := 0; i < len(lines); {
+		line := lines[i]
+
+		if markdownBlank(line) {
+			i++
+			continue
+		}
+
+		// indented code block
+		if markdownIndent(line) >= 4 {
+			var code []string
+			for ; i < len(lines) && (markdownBlank(lines[i]) || markdownIndent(lines[i]) >= 4); i++ {
+				if len(lines[i]) > 4 {
+					code = append(code, lines[i][4:])
+				} else {
+					code = append(code, "")
+				}
+			}
+
+			for len(code) > 0 && markdownBlank(code[len(code)-1]) {
+				code = code[:len(code)-1]
+			}
+
+			buf = append(buf, "<pre><code>"...)
+			buf = appendEscapedHTML(buf, strings.Join(code, "\n")+"\n", false)
+			buf = append(buf, "</code></pre>\n"...)
+			continue
+		}
+
+		// fenced code block, unclosed block goes to the end
+		if indent, fence, info, ok := markdownFence(line); ok {
+			var code []string
+			for i++; i < len(lines); i++ {
+				if markdownFenceClose(lines[i], fence) {
+					i++
+					break
+				}
+
+				n := markdownIndent(lines[i])
+				if n > indent {
+					n = indent
+				}
+				code = append(code, lines[i][n:])
+			}
+
+			buf = append(buf, "<pre><code"...)
+			if lang := markdownLanguage(info); lang != "" {
+				buf = append(buf, ` class="language-`+lang+`"`...)
+			}
+			buf = append(buf, 62) // >
+			if len(code) > 0 {
+				buf = appendEscapedHTML(buf, strings.Join(code, "\n")+"\n", false)
+			}
+			buf = append(buf, "</code></pre>\n"...)
+			continue
+		}
+
+		// atx heading
+		if m := markdownATXPattern.FindStringSubmatch(line); m != nil {
+			level := strconv.Itoa(len(m[1]))
+			buf = append(buf, "<h"+level+">"...)
+			buf = appendMarkdownInline(buf, m[2])
+			buf = append(buf, "</h"+level+">\n"...)
+			i++
+			continue
+		}
+
+		// thematic break
+		if markdownHRPattern.MatchString(line) {
+			buf = append(buf, "<hr />\n"...)
+			i++
+			continue
+		}
+
+		// blockquote
+		if markdownIndent(line) < 4 && strings.HasPrefix(strings.TrimLeft(line, " "), ">") {
+			var quote []string
+			for ; i < len(lines); i++ {
+				str := strings.TrimLeft(lines[i], " ")
+				if markdownIndent(lines[i]) < 4 && strings.HasPrefix(str, ">") {
+					str = strings.TrimPrefix(str[1:], " ")
+					quote = append(quote, str)
+					continue
+				}
+
+				// lazy continuation line of the paragraph
+				if markdownBlank(lines[i]) || markdownBlank(quote[len(quote)-1]) || markdownInterrupts(lines[i]) {
+					break
+				}
+				quote = append(quote, lines[i])
+			}
+
+			buf = append(buf, "<blockquote>\n"...)
+			buf = appendMarkdownBlocks(buf, quote, false)
+			buf = append(buf, "</blockquote>\n"...)
+			continue
+		}
+
+		// list
+		if _, _, _, ok := markdownListMarker(line); ok {
+			buf, i = appendMarkdownList(buf, lines, i)
+			continue
+		}
+
+		// paragraph or setext heading
+		level := 0
+		var para []string
+		for ; i < len(lines); i++ {
+			str := lines[i]
+			if markdownBlank(str) {
+				break
+			}
+
+			if len(para) > 0 {
+				if markdownSetextPattern.MatchString(str) {
+					level = 1
+					if strings.Contains(str, "-") {
+						level = 2
+					}
+					i++
+					break
+				}
+
+				if markdownInterrupts(str) {
+					break
+				}
+			}
+
+			para = append(para, strings.TrimLeft(str, " "))
+		}
+
+		text := strings.TrimRight(strings.Join(para, "\n"), " ")
+
+		switch {
+		case level > 0:
+			buf = append(buf, "<h"+strconv.Itoa(level)+">"...)
+			buf = appendMarkdownInline(buf, text)
+			buf = append(buf, "</h"+strconv.Itoa(level)+">\n"...)
+		case tight:
+			buf = appendMarkdownInline(buf, text)
+			buf = append(buf, 10)
+		default:
+			buf = append(buf, "<p>"...)
+			buf = appendMarkdownInline(buf, text)
+			buf = append(buf, "</p>\n"...)
+		}
+	}
+
+	return buf
+}
+
+// appendMarkdownList appends the html of the list starts at lines[i], returns the index of the next line
+// NOTE : a list is loose if any of items are separated by blank lines or has blank lines
+func appendMarkdownList(buf []byte, lines []string, i int) ([]byte, int) {
+	kind, start, _, _ := markdownListMarker(lines[i])
+
+	var items [][]string
+	loose := false
+
+	for i < len(lines) {
+		k, _, width, ok := markdownListMarker(lines[i])
+		if !ok || k != kind || markdownHRPattern.MatchString(lines[i]) {
+			break
+		}
+
+		item := []string{""}
+		if width < len(lines[i]) {
+			item[0] = lines[i][width:]
+		}
+
+		for i++; i < len(lines); i++ {
+			str := lines[i]
+			if markdownBlank(str) {
+				item = append(item, "")
+				continue
+			}
+
+			if markdownIndent(str) >= width {
+				item = append(item, str[width:])
+				continue
+			}
+
+			// lazy continuation line of the paragraph
+			if _, _, _, ok := markdownListMarker(str); !ok && !markdownBlank(item[len(item)-1]) && !markdownInterrupts(str) {
+				item = append(item, strings.TrimLeft(str, " "))
+				continue
+			}
+
+			break
+		}
+
+		trailing := 0
+		for len(item) > 1 && markdownBlank(item[len(item)-1]) {
+			item = item[:len(item)-1]
+			trailing++
+		}
+
+		for _, v := range item {
+			if markdownBlank(v) {
+				loose = true
+			}
+		}
+
+		if trailing > 0 && i < len(lines) {
+			if k, _, _, ok := markdownListMarker(lines[i]); ok && k == kind && !markdownHRPattern.MatchString(lines[i]) {
+				loose = true
+			}
+		}
+
+		items = append(items, item)
+	}
+
+	switch {
+	case kind != 46 && kind != 41: // . )
+		buf = append(buf, "<ul>\n"...)
+	case start != 1:
+		buf = append(buf, `<ol start="`+strconv.Itoa(start)+`">`+"\n"...)
+	default:
+		buf = append(buf, "<ol>\n"...)
+	}
+
+	for _, item := range items {
+		inner := appendMarkdownBlocks(nil, item, !loose)
+		buf = append(buf, "<li>"...)
+		buf = append(buf, strings.TrimSuffix(string(inner), "\n")...)
+		buf = append(buf, "</li>\n"...)
+	}
+
+	if kind != 46 && kind != 41 {
+		buf = append(buf, "</ul>\n"...)
+	} else {
+		buf = append(buf, "</ol>\n"...)
+	}
+
+	return buf, i
+}
+
+// markdownLink parses the inline link [label](destination "title") at str[i]
+// returns the label, the destination, the title and the end of link
+func markdownLink(str string, i int) (string, string, string, int, bool) {
+	// label, brackets are balanced
+	j, depth := i, 0
+	for ; j < len(str); j++ {
+		if str[j] == 92 { // backslash
+			j++
+			continue
+		}
+
+		if str[j] == 91 { // [
+			depth++
+		} else if str[j] == 93 { // ]
+			depth--
+			if depth == 0 {
+				break
+			}
+		}
+	}
+
+	if j >= len(str) || j+1 >= len(str) || str[j+1] != 40 { // (
+		return "", "", "", 0, false
+	}
+
+	label := str[i+1 : j]
+	k := j + 2
+	for k < len(str) && (str[k] == 32 || str[k] == 10) {
+		k++
+	}
+
+	// destination
+	from := k
+	dest := ""
+	if k < len(str) && str[k] == 60 { // <
+		end := strings.IndexAny(str[k+1:], "<>\n")
+		if end < 0 || str[k+1+end] != 62 { // >
+			return "", "", "", 0, false
+		}
+		dest = str[k+1 : k+1+end]
+		k += end + 2
+	} else {
+		parens := 0
+	DEST:
+		for ; k < len(str); k++ {
+			switch c := str[k]; {
+			case c == 92 && k+1 < len(str): // backslash
+				k++
+			case c <= 32:
+				break DEST
+			case c == 40: // (
+				parens++
+			case c == 41: // )
+				if parens == 0 {
+					break DEST
+				}
+				parens--
+			}
+		}
+		dest = str[from:k]
+	}
+
+	// title, separated by white spaces
+	spaces := k
+	for k < len(str) && (str[k] == 32 || str[k] == 10) {
+		k++
+	}
+
+	title := ""
+	if k < len(str) && k > spaces && (str[k] == 34 || str[k] == 39 || str[k] == 40) { // " ' (
+		closing := str[k]
+		if closing == 40 {
+			closing = 41
+		}
+
+		end := k + 1
+		for ; end < len(str) && str[end] != closing; end++ {
+			if str[end] == 92 { // backslash
+				end++
+			}
+		}
+
+		if end >= len(str) {
+			return "", "", "", 0, false
+		}
+
+		title = str[k+1 : end]
+		k = end + 1
+		for k < len(str) && (str[k] == 32 || str[k] == 10) {
+			k++
+		}
+	}
+
+	if k >= len(str) || str[k] != 41 { // )
+		return "", "", "", 0, false
+	}
+
+	return label, markdownUnescape(dest), markdownUnescape(title), k + 1, true
+}
+
+// markdownCodeSpanEnd returns the index of the closing backtick string of length n
+func markdownCodeSpanEnd(str string, i int, n int) int {
+	for i < len(str) {
+		if str[i] != 96 { // `
+			i++
+			continue
+		}
+
+		j := i
+		for j < len(str) && str[j] == 96 {
+			j++
+		}
+
+		if j-i == n {
+			return i
+		}
+		i = j
+	}
+
+	return -1
+}
+
+// markdownInline is a html or a delimiter run of emphasis
+type markdownInline struct {
+	html   string
+	delim  byte // * or _ , 0 is html
+	count  int  // remaining delimiters
+	length int  // length of the delimiter run
+	open   bool
+	close  bool
+	before string // closing tags before the delimiters
+	after  string // opening tags after the delimiters
+}
+
+// appendMarkdownInline appends the html of inline elements (code span, emphasis, link, image, autolink, line break)
+func appendMarkdownInline(buf []byte, str string) []byte {
+	var items []markdownInline
+	var lit []byte // literal text, not escaped
+
+	addHTML := func(html string) {
+		if len(lit) > 0 {
+			items = append(items, markdownInline{html: string(appendEscapedHTML(nil, string(lit), false))})
+			lit = lit[:0]
+		}
+
+		if html != "" {
+			items = append(items, markdownInline{html: html})
+		}
+	}
+
+	for i := 0; i < len(str); {
+		c := str[i]
+
+		switch {
+		case c == 92: // backslash
+			if i+1 < len(str) && str[i+1] == 10 { // hard line break
+				addHTML("<br />\n")
+				i += 2
+				continue
+			}
+
+			if i+1 < len(str) && str[i+1] < 128 && markdownPunct(rune(str[i+1])) {
+				lit = append(lit, str[i+1])
+				i += 2
+				continue
+			}
+
+			lit = append(lit, c)
+			i++
+
+		case c == 96: // `
+			n := 1
+			for i+n < len(str) && str[i+n] == 96 {
+				n++
+			}
+
+			end := markdownCodeSpanEnd(str, i+n, n)
+			if end < 0 {
+				lit = append(lit, str[i:i+n]...)
+				i += n
+				continue
+			}
+
+			code := strings.Replace(str[i+n:end], "\n", " ", -1)
+			if len(code) > 2 && code[0] == 32 && code[len(code)-1] == 32 && strings.Trim(code, " ") != "" {
+				code = code[1 : len(code)-1]
+			}
+
+			addHTML("<code>" + string(appendEscapedHTML(nil, code, false)) + "</code>")
+			i = end + n
+
+		case c == 42 || c == 95: // * _
+			n := 1
+			for i+n < len(str) && str[i+n] == c {
+				n++
+			}
+
+			prev, next := rune(32), rune(32)
+			if i > 0 {
+				prev, _ = utf8.DecodeLastRuneInString(str[:i])
+			}
+			if i+n < len(str) {
+				next, _ = utf8.DecodeRuneInString(str[i+n:])
+			}
+
+			// referrer : https://spec.commonmark.org/0.30/#left-flanking-delimiter-run
+			left := !unicode.IsSpace(next) && (!markdownPunct(next) || unicode.IsSpace(prev) || markdownPunct(prev))
+			right := !unicode.IsSpace(prev) && (!markdownPunct(prev) || unicode.IsSpace(next) || markdownPunct(next))
+
+			open, close := left, right
+			if c == 95 { // intraword _ is not emphasis
+				open = left && (!right || markdownPunct(prev))
+				close = right && (!left || markdownPunct(next))
+			}
+
+			addHTML("")
+			items = append(items, markdownInline{delim: c, count: n, length: n, open: open, close: close})
+			i += n
+
+		case c == 33 && i+1 < len(str) && str[i+1] == 91: // ![
+			label, dest, title, end, ok := markdownLink(str, i+1)
+			if !ok {
+				lit = append(lit, c)
+				i++
+				continue
+			}
+
+			alt := decodeHTMLEntities(stripTags(string(appendMarkdownInline(nil, label))))
+			html := `<img src="` + string(appendEscapedHTML(nil, dest, true)) + `" alt="` + string(appendEscapedHTML(nil, alt, true)) + `"`
+			if title != "" {
+				html += ` title="` + string(appendEscapedHTML(nil, title, true)) + `"`
+			}
+			addHTML(html + " />")
+			i = end
+
+		case c == 91: // [
+			label, dest, title, end, ok := markdownLink(str, i)
+			if !ok {
+				lit = append(lit, c)
+				i++
+				continue
+			}
+
+			html := `<a href="` + string(appendEscapedHTML(nil, dest, true)) + `"`
+			if title != "" {
+				html += ` title="` + string(appendEscapedHTML(nil, title, true)) + `"`
+			}
+			addHTML(html + ">" + string(appendMarkdownInline(nil, label)) + "</a>")
+			i = end
+
+		case c == 60: // <
+			if m := markdownAutolinkPattern.FindStringSubmatch(str[i:]); m != nil {
+				addHTML(`<a href="` + string(appendEscapedHTML(nil, m[1], true)) + `">` + string(appendEscapedHTML(nil, m[1], false)) + "</a>")
+				i += len(m[0])
+				continue
+			}
+
+			if m := markdownEmailPattern.FindStringSubmatch(str[i:]); m != nil {
+				addHTML(`<a href="mailto:` + string(appendEscapedHTML(nil, m[1], true)) + `">` + string(appendEscapedHTML(nil, m[1], false)) + "</a>")
+				i += len(m[0])
+				continue
+			}
+
+			lit = append(lit, c) // raw html is escaped
+			i++
+
+		case c == 38: // &
+			if val, size := entityRef(str[i:]); size > 0 {
+				lit = append(lit, val...)
+				i += size
+				continue
+			}
+
+			lit = append(lit, c)
+			i++
+
+		case c == 10: // hard line break with 2 or more spaces, or soft line break
+			n := len(lit)
+			for len(lit) > 0 && lit[len(lit)-1] == 32 {
+				lit = lit[:len(lit)-1]
+			}
+
+			if n-len(lit) >= 2 {
+				addHTML("<br />\n")
+			} else {
+				lit = append(lit, 10)
+			}
+			i++
+
+		default:
+			lit = append(lit, c)
+			i++
+		}
+	}
+	addHTML("")
+
+	markdownEmphasis(items)
+
+	for _, v := range items {
+		if v.delim == 0 {
+			buf = append(buf, v.html...)
+			continue
+		}
+
+		buf = append(buf, v.before...)
+		buf = append(buf, strings.Repeat(string(v.delim), v.count)...)
+		buf = append(buf, v.after...)
+	}
+
+	return buf
+}
+
+// markdownEmphasis matches the delimiter runs to em and strong
+// referrer : https://spec.commonmark.org/0.30/#process-emphasis
+func markdownEmphasis(items []markdownInline) {
+	// lower bound of the opener search, by the delimiter (* _), the length of closer % 3, the closer can open
+	var bottom [2][3][2]int
+	for a := range bottom {
+		for b := range bottom[a] {
+			bottom[a][b] = [2]int{-1, -1}
+		}
+	}
+
+	for ci := range items {
+		c := &items[ci]
+		if c.delim == 0 || !c.close {
+			continue
+		}
+
+		x, z := 0, 0
+		if c.delim == 95 { // _
+			x = 1
+		}
+		if c.open {
+			z = 1
+		}
+
+		for c.count > 0 {
+			found := -1
+			for oi := ci - 1; oi > bottom[x][c.length%3][z]; oi-- {
+				o := &items[oi]
+				if o.delim != c.delim || !o.open || o.count == 0 {
+					continue
+				}
+
+				// rule of 3
+				if (o.close || c.open) && (o.length+c.length)%3 == 0 && (o.length%3 != 0 || c.length%3 != 0) {
+					continue
+				}
+
+				found = oi
+				break
+			}
+
+			if found < 0 {
+				bottom[x][c.length%3][z] = ci - 1
+				break
+			}
+
+			o := &items[found]
+			n, tag := 1, "em"
+			if o.count >= 2 && c.count >= 2 {
+				n, tag = 2, "strong"
+			}
+
+			o.count -= n
+			c.count -= n
+			o.after = "<" + tag + ">" + o.after
+			c.before += "</" + tag + ">"
+
+			// delimiters between the opener and the closer are literal
+			for k := found + 1; k < ci; k++ {
+				items[k].open, items[k].close = false, false
+			}
+		}
+	}
+}
+
+// Markdown2HTML is Convert the markdown (CommonMark subset : headings, paragraphs, emphasis, links, images, lists, code, blockquote, thematic break) to HTML
+// NOTE : raw html in the markdown is escaped, and the output is sanitized with allowed elements and url schemes (http, https, mailto)
+func (s *StringProc) Markdown2HTML(str string) (string, error) {
+	buf := appendMarkdownBlocks(make([]byte, 0, len(str)*2), markdownLines(str), false) // prealloca
+
+	return s.StripTagsWithPolicy(string(buf), markdownPolicy)
+}
+
+// HTML2Markdown is Convert HTML to the markdown (headings, paragraphs, emphasis, links, images, lists, code, blockquote, tables)
+func (s *StringProc) HTML2Markdown(str string) (string, error) {
+	w := &html2Text{buf: make([]byte, 0, len(str)), linkStyle: LinkStyleNone, linestart: true, markdown: true}
+
+	return w.convert(str), nil
+}
@@ -0,0 +1,136 @@
+package strutils_test
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_strutils_Markdown2HTML(t *testing.T) {
+	t.Parallel()
+
+	dataset := map[string]string{
+		"abcdefg":                           "<p>abcdefg</p>\n",
+		"a\nb\n\nc":                         "<p>a\nb</p>\n<p>c</p>\n",
+		"# Title\n###### h6 ##\n####### no": "<h1>Title</h1>\n<h6>h6</h6>\n<p>####### no</p>\n",
+		"Title\n===\nSub\n---":              "<h1>Title</h1>\n<h2>Sub</h2>\n",
+		"a\n\n***\n\n- - -":                 "<p>a</p>\n<hr />\n<hr />\n",
+
+		// emphasis
+		"*em* _em_ **strong** __strong__": "<p><em>em</em> <em>em</em> <strong>strong</strong> <strong>strong</strong></p>\n",
+		"***both*** *a **b** c*":          "<p><em><strong>both</strong></em> <em>a <strong>b</strong> c</em></p>\n",
+		"snake_case_word foo*bar*baz":     "<p>snake_case_word foo<em>bar</em>baz</p>\n",
+		"a * not em * \\*escaped\\*":      "<p>a * not em * *escaped*</p>\n",
+
+		// code
+		"a `<b>` b ``x`y``":                       "<p>a <code>&lt;b&gt;</code> b <code>x`y</code></p>\n",
+		"```go\nfmt.Println(\"<x>\")\n```":        "<pre><code class=\"language-go\">fmt.Println(\"&lt;x&gt;\")\n</code></pre>\n",
+		"~~~\n*a*\n\n~~~":                         "<pre><code>*a*\n\n</code></pre>\n",
+		"    code\n    block":                     "<pre><code>code\nblock\n</code></pre>\n",
+		"```\"><script>alert(1)</script>\nx\n```": "<pre><code>x\n</code></pre>\n",
+
+		// links and images
+		`[Go](https://golang.org "The Go")`: "<p><a href=\"https://golang.org\" title=\"The Go\">Go</a></p>\n",
+		"[a *b*](/path?a=1&b=2)":            "<p><a href=\"/path?a=1&amp;b=2\">a <em>b</em></a></p>\n",
+		"![logo *x*](x.png)":                "<p><img src=\"x.png\" alt=\"logo x\" /></p>\n",
+		"<https://a.com> <a@golang.org>":    "<p><a href=\"https://a.com\">https://a.com</a> <a href=\"mailto:a@golang.org\">a@golang.org</a></p>\n",
+		"[not link] [x](unclosed":           "<p>[not link] [x](unclosed</p>\n",
+
+		// lists
+		"- one\n- two\n  - nested\n- three": "<ul>\n<li>one</li>\n<li>two\n<ul>\n<li>nested</li>\n</ul></li>\n<li>three</li>\n</ul>\n",
+		"1. a\n2. b":                        "<ol>\n<li>a</li>\n<li>b</li>\n</ol>\n",
+		"3) a\n4) b":                        "<ol start=\"3\">\n<li>a</li>\n<li>b</li>\n</ol>\n",
+		"- a\n\n- b":                        "<ul>\n<li><p>a</p></li>\n<li><p>b</p></li>\n</ul>\n",
+		"- a\n+ b":                          "<ul>\n<li>a</li>\n</ul>\n<ul>\n<li>b</li>\n</ul>\n",
+		"a\n2. b":                           "<p>a\n2. b</p>\n",
+
+		// blockquote
+		"> a\nlazy\n> > b": "<blockquote>\n<p>a\nlazy</p>\n<blockquote>\n<p>b</p>\n</blockquote>\n</blockquote>\n",
+
+		// line breaks, escaping
+		"a  \nb\\\nc":           "<p>a<br />\nb<br />\nc</p>\n",
+		"&copy; &lt;b&gt; & <":  "<p>© &lt;b&gt; &amp; &lt;</p>\n",
+		"<b>raw</b> <!-- x -->": "<p>&lt;b&gt;raw&lt;/b&gt; &lt;!-- x --&gt;</p>\n",
+	}
+
+	// check : common
+	for k, v := range dataset {
+		retval, err := strproc.Markdown2HTML(k)
+		assert.AssertNil(t, err, "Error : %v", err)
+		assert.AssertEquals(t, retval, v, "Return Value mismatch (%v).\nExpected: %v\nActual: %v", k, v, retval)
+	}
+
+	// check : xss
+	xss := []string{
+		"[x](javascript:alert(1))",
+		"[x](JaVaScRiPt&#58;alert(1))",
+		"[x](java\\script:alert(1))",
+		"![x](data:text/html;base64,PHNjcmlwdD4=)",
+		"[x](<javascript:alert(1)>)",
+		"<javascript:alert(1)>",
+		"<script>alert(1)</script>",
+		"<img src=x onerror=alert(1)>",
+		"[x](\"onmouseover=\"alert(1))",
+		"[x](/a \"\\\" onmouseover=\\\"alert(1)\")",
+		"``` onload=alert(1)\nx\n```",
+	}
+
+	for _, v := range xss {
+		retval, err := strproc.Markdown2HTML(v)
+		assert.AssertNil(t, err, "Error : %v", err)
+
+		// without the emitted tags, no more markup
+		plain, _ := strproc.StripTags(retval)
+		lower := strings.ToLower(retval)
+		assert.AssertFalse(t, strings.Contains(lower, "<script") || strings.Contains(lower, "=\"javascript:") || strings.Contains(lower, "=\"data:") || strings.Contains(plain, "<"), "Couldn't sanitize (%v) : %v", v, retval)
+		assert.AssertFalse(t, strings.Contains(lower, "\" onmouseover=") || strings.Contains(lower, " onerror=\"") || strings.Contains(lower, " onload="), "Couldn't sanitize (%v) : %v", v, retval)
+	}
+}
+
+func Test_strutils_HTML2Markdown(t *testing.T) {
+	t.Parallel()
+
+	dataset := map[string]string{
+		"abcdefg":                               "abcdefg",
+		"<h1>Title</h1><h3>Sub</h3><p>a  b</p>": "# Title\n\n### Sub\n\na b",
+		"<p>a <b>b </b>c <em>d</em> <code>a*b</code></p>":                             "a **b** c *d* `a*b`",
+		"<ul><li>a<ol><li>b</li></ol></li><li>c</li></ul>":                            "- a\n   1. b\n- c",
+		"<blockquote><p>q</p><p>r</p></blockquote>":                                   "> q\n>\n> r",
+		"<p>1*2_3 [x] &lt;b&gt; &amp;copy;</p><p># no</p>":                            "1\\*2\\_3 \\[x\\] \\<b> \\&copy;\n\n\\# no",
+		"<pre>a *b*\n  c</pre>":                                                       "```\na *b*\n  c\n```",
+		"a<br>b<hr>c":                                                                 "a\\\nb\n\n---\n\nc",
+		`<a href="https://golang.org">go lang</a>`:                                    "[go lang](https://golang.org)",
+		`<a href="/a b(c)">x</a>`:                                                     "[x](/a%20b%28c%29)",
+		`<a href="javascript:x()">x</a> <a>y</a>`:                                     "x y",
+		`<img src="x.png" alt="logo">`:                                                "![logo](x.png)",
+		"<script>alert(1)</script><p>a</p>":                                           "a",
+		"<table><tr><th>a</th><th>b</th></tr><tr><td>1|2</td><td>x</td></tr></table>": "| a    | b   |\n| ---- | --- |\n| 1\\|2 | x   |",
+	}
+
+	// check : common
+	for k, v := range dataset {
+		retval, err := strproc.HTML2Markdown(k)
+		assert.AssertNil(t, err, "Error : %v", err)
+		assert.AssertEquals(t, retval, v, "Return Value mismatch (%v).\nExpected: %v\nActual: %v", k, v, retval)
+	}
+
+	// check : round trip, markdown => html => markdown => html
+	markdown := []string{
+		"# Title\n\nHello *world* and **bold**, `code`.",
+		"- one\n- two\n   1. nested\n- three",
+		"> quote\n\n[Go](https://golang.org) ![logo](x.png)",
+		"```\nfmt.Println(\"<x>\")\n```",
+		"1\\*2 \\_3\\_ \\[x\\] \\<b> a\\\nb",
+	}
+
+	for _, v := range markdown {
+		html, err := strproc.Markdown2HTML(v)
+		assert.AssertNil(t, err, "Error : %v", err)
+
+		md, err := strproc.HTML2Markdown(html)
+		assert.AssertNil(t, err, "Error : %v", err)
+
+		retval, err := strproc.Markdown2HTML(md)
+		assert.AssertNil(t, err, "Error : %v", err)
+		assert.AssertEquals(t, retval, html, "Return Value mismatch (%v).\nExpected: %v\nActual: %v", md, html, retval)
+	}
+}
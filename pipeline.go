@@ -0,0 +1,627 @@
+package strutils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Pipeline parameter type of PipelineParam
+const (
+	PipelineString = iota // string
+	PipelineInt           // integer (the number without fraction)
+	PipelineBool          // boolean
+	PipelineEnum          // one of the names of the Enum, the value is int
+)
+
+var pipelineTypeNames = map[int]string{
+	PipelineString: "string",
+	PipelineInt:    "int",
+	PipelineBool:   "bool",
+	PipelineEnum:   "enum",
+}
+
+var pipelineNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// PipelineParam is the schema of a parameter of PipelineOp
+type PipelineParam struct {
+	Name     string
+	Type     int            // PipelineString, PipelineInt, PipelineBool, PipelineEnum
+	Required bool           // error if not given
+	Default  interface{}    // value if not given (string, int, bool or the name of the Enum)
+	Enum     map[string]int // names and values of PipelineEnum
+}
+
+// PipelineArgs is the validated parameters of a step, string, int or bool by the type (int of PipelineEnum)
+type PipelineArgs map[string]interface{}
+
+// String returns the string parameter
+func (a PipelineArgs) String(name string) string {
+	v, _ := a[name].(string)
+	return v
+}
+
+// Int returns the int or enum parameter
+func (a PipelineArgs) Int(name string) int {
+	v, _ := a[name].(int)
+	return v
+}
+
+// Bool returns the bool parameter
+func (a PipelineArgs) Bool(name string) bool {
+	v, _ := a[name].(bool)
+	return v
+}
+
+// PipelineOp is an operation of the pipeline
+// Stream is optional, the streaming version of the Func (the output must be same as the Func)
+// Lines is true if the Func of each line is same as the Func of the whole string, the Func is applied line by line (without the newline) in the stream if Stream is nil
+// the operation without both (hash, padding, ...) needs the whole string, can't be in the stream
+type PipelineOp struct {
+	Name   string
+	Params []PipelineParam
+	Func   func(str string, args PipelineArgs) (string, error)
+	Stream func(args PipelineArgs) (Transformer, error)
+	Lines  bool
+}
+
+// PipelineError is the error of the pipeline spec (unknown operation, bad parameter)
+type PipelineError struct {
+	Step  int    // number of the step, from 1
+	Op    string // name of the operation
+	Param string // name of the parameter, empty if not a parameter error
+	Err   error
+}
+
+func (e *PipelineError) Error() string {
+	if len(e.Param) > 0 {
+		return fmt.Sprintf("pipeline step %d (%s) parameter %s : %v", e.Step, e.Op, e.Param, e.Err)
+	}
+
+	return fmt.Sprintf("pipeline step %d (%s) : %v", e.Step, e.Op, e.Err)
+}
+
+// Unwrap returns the error of the step
+func (e *PipelineError) Unwrap() error {
+	return e.Err
+}
+
+// PipelineRegistry is the registry of the pipeline operations by name
+type PipelineRegistry struct {
+	sync.RWMutex
+	ops map[string]PipelineOp
+}
+
+// NewPipelineRegistry Creates and returns a PipelineRegistry's pointer with the built-in operations of StringProc
+func NewPipelineRegistry() *PipelineRegistry {
+	r := &PipelineRegistry{ops: make(map[string]PipelineOp)}
+	for _, op := range pipelineBuiltins(&StringProc{}) {
+		r.ops[op.Name] = op
+	}
+
+	return r
+}
+
+// pipelineValue is convert the value of the spec (JSON, YAML, ...) to the type of the parameter
+func pipelineValue(p PipelineParam, v interface{}) (interface{}, error) {
+	switch p.Type {
+	case PipelineString:
+		if str, ok := v.(string); ok {
+			return str, nil
+		}
+
+	case PipelineBool:
+		if b, ok := v.(bool); ok {
+			return b, nil
+		}
+
+	case PipelineInt:
+		var f float64
+		switch n := v.(type) {
+		case json.Number:
+			i, err := n.Int64()
+			if err != nil {
+				return nil, fmt.Errorf("Not allow int : %v", n)
+			}
+			f = float64(i)
+		case float32, float64:
+			f = reflect.ValueOf(n).Float()
+		case int, int8, int16, int32, int64:
+			f = float64(reflect.ValueOf(n).Int())
+		case uint, uint8, uint16, uint32, uint64:
+			f = float64(reflect.ValueOf(n).Uint())
+		default:
+			return nil, fmt.Errorf("Not allow type %T, expected int", v)
+		}
+
+		if f != math.Trunc(f) || f > math.MaxInt32 || f < math.MinInt32 {
+			return nil, fmt.Errorf("Not allow int : %v", v)
+		}
+
+		return int(f), nil
+
+	case PipelineEnum:
+		name, ok := v.(string)
+		if !ok {
+			break
+		}
+
+		if i, ok := p.Enum[name]; ok {
+			return i, nil
+		}
+
+		names := make([]string, 0, len(p.Enum))
+		for k := range p.Enum {
+			names = append(names, k)
+		}
+		sort.Strings(names)
+
+		return nil, fmt.Errorf("Not allow value %q, expected one of %v", name, strings.Join(names, ", "))
+	}
+
+	return nil, fmt.Errorf("Not allow type %T, expected %v", v, pipelineTypeNames[p.Type])
+}
+
+// Register is add (or replace) the operation, the name is lower case snake (strip_tags)
+func (r *PipelineRegistry) Register(op PipelineOp) error {
+	if !pipelineNamePattern.MatchString(op.Name) {
+		return fmt.Errorf("Not allow pipeline op name : %q", op.Name)
+	}
+
+	if op.Func == nil {
+		return fmt.Errorf("Not exists Func of pipeline op : %v", op.Name)
+	}
+
+	seen := make(map[string]bool, len(op.Params))
+	for _, p := range op.Params {
+		if !pipelineNamePattern.MatchString(p.Name) || p.Name == "op" || seen[p.Name] {
+			return fmt.Errorf("Not allow parameter name of pipeline op %v : %q", op.Name, p.Name)
+		}
+		seen[p.Name] = true
+
+		if _, ok := pipelineTypeNames[p.Type]; !ok {
+			return fmt.Errorf("Not allow parameter type of pipeline op %v : %v", op.Name, p.Type)
+		}
+
+		if p.Default != nil {
+			if _, err := pipelineValue(p, p.Default); err != nil {
+				return fmt.Errorf("Not allow default of parameter %v of pipeline op %v : %v", p.Name, op.Name, err)
+			}
+		}
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	r.ops[op.Name] = op
+	return nil
+}
+
+// Names returns the names of the registered operations, sorted
+func (r *PipelineRegistry) Names() []string {
+	r.RLock()
+	defer r.RUnlock()
+
+	retval := make([]string, 0, len(r.ops))
+	for k := range r.ops {
+		retval = append(retval, k)
+	}
+
+	sort.Strings(retval)
+	return retval
+}
+
+// pipelineStep is a validated step of Pipeline
+type pipelineStep struct {
+	op   PipelineOp
+	args PipelineArgs
+}
+
+// Pipeline is the validated steps, created by PipelineRegistry.Compile
+type Pipeline struct {
+	steps []pipelineStep
+}
+
+// Compile is validate the spec and return the Pipeline
+// a step is {"op": name, parameter: value, ...}, the unknown operation, the unknown parameter, the missing required parameter
+// and the type mismatch are returned as *PipelineError
+func (r *PipelineRegistry) Compile(spec []map[string]interface{}) (*Pipeline, error) {
+	r.RLock()
+	defer r.RUnlock()
+
+	p := &Pipeline{steps: make([]pipelineStep, 0, len(spec))}
+	for no, v := range spec {
+		name, ok := v["op"].(string)
+		if !ok {
+			return nil, &PipelineError{Step: no + 1, Op: fmt.Sprint(v["op"]), Err: fmt.Errorf("Not exists op name")}
+		}
+
+		op, ok := r.ops[name]
+		if !ok {
+			return nil, &PipelineError{Step: no + 1, Op: name, Err: fmt.Errorf("Not exists pipeline op")}
+		}
+
+		params := make(map[string]PipelineParam, len(op.Params))
+		args := make(PipelineArgs, len(op.Params))
+		for _, param := range op.Params {
+			params[param.Name] = param
+
+			val, given := v[param.Name]
+			if !given {
+				if param.Required {
+					return nil, &PipelineError{Step: no + 1, Op: name, Param: param.Name, Err: fmt.Errorf("Not exists required parameter")}
+				}
+
+				if param.Default == nil {
+					continue
+				}
+				val = param.Default
+			}
+
+			typed, err := pipelineValue(param, val)
+			if err != nil {
+				return nil, &PipelineError{Step: no + 1, Op: name, Param: param.Name, Err: err}
+			}
+
+			args[param.Name] = typed
+		}
+
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			if _, ok := params[k]; !ok && k != "op" {
+				return nil, &PipelineError{Step: no + 1, Op: name, Param: k, Err: fmt.Errorf("Not exists parameter")}
+			}
+		}
+
+		p.steps = append(p.steps, pipelineStep{op: op, args: args})
+	}
+
+	return p, nil
+}
+
+// CompileJSON is Compile of the JSON spec, an array of the steps. Ex) [{"op":"strip_tags"},{"op":"pad_left","fill":"0","width":10}]
+func (r *PipelineRegistry) CompileJSON(data []byte) (*Pipeline, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var spec []map[string]interface{}
+	if err := dec.Decode(&spec); err != nil {
+		return nil, err
+	}
+
+	return r.Compile(spec)
+}
+
+// Run is apply the steps to the string, the error of the step is returned as *ChainError (the name is the operation)
+func (p *Pipeline) Run(str string) (string, error) {
+	chain := Chain(str)
+	for _, v := range p.steps {
+		step := v
+		chain.Apply(step.op.Name, func(str string) (string, error) {
+			return step.op.Func(str, step.args)
+		})
+	}
+
+	return chain.Result()
+}
+
+// lineTransformer is the Transformer of the function, applied line by line (the newline is kept)
+type lineTransformer struct {
+	fn func(str string) (string, error)
+}
+
+func (t *lineTransformer) Transform(dst []byte, src []byte, atEOF bool) ([]byte, int, error) {
+	n := 0
+	for n < len(src) {
+		end := bytes.IndexByte(src[n:], 10) // \n
+		if end < 0 && !atEOF {
+			break
+		}
+
+		next := len(src)
+		if end >= 0 {
+			next = n + end + 1
+		}
+
+		line := src[n:next]
+		nl := len(line)
+		for nl > 0 && (line[nl-1] == 10 || line[nl-1] == 13) { // \n , \r\n
+			nl--
+		}
+
+		retval, err := t.fn(string(line[:nl]))
+		if err != nil {
+			return dst, n, err
+		}
+
+		dst = append(dst, retval...)
+		dst = append(dst, line[nl:]...)
+		n = next
+	}
+
+	return dst, n, nil
+}
+
+func (t *lineTransformer) Reset() {}
+
+// stepTransformer is the Transformer of a step, the error is returned as *ChainError
+type stepTransformer struct {
+	t    Transformer
+	step int
+	name string
+}
+
+func (t *stepTransformer) Transform(dst []byte, src []byte, atEOF bool) ([]byte, int, error) {
+	dst, n, err := t.t.Transform(dst, src, atEOF)
+	if err != nil {
+		return dst, n, &ChainError{Step: t.step, Name: t.name, Err: err}
+	}
+
+	return dst, n, nil
+}
+
+func (t *stepTransformer) Reset() {
+	t.t.Reset()
+}
+
+// Transformer returns the Transformer of the steps for NewReader and NewWriter, the output is same as Run
+// the operations without the streaming version are applied line by line (PipelineOp.Lines),
+// *PipelineError is returned if an operation needs the whole string
+func (p *Pipeline) Transformer() (Transformer, error) {
+	ts := make([]Transformer, 0, len(p.steps))
+	for no, v := range p.steps {
+		step := v
+
+		var t Transformer
+		switch {
+		case step.op.Stream != nil:
+			var err error
+			if t, err = step.op.Stream(step.args); err != nil {
+				return nil, &ChainError{Step: no + 1, Name: step.op.Name, Err: err}
+			}
+
+		case step.op.Lines:
+			t = &lineTransformer{fn: func(str string) (string, error) {
+				return step.op.Func(str, step.args)
+			}}
+
+		default:
+			return nil, &PipelineError{Step: no + 1, Op: step.op.Name, Err: fmt.Errorf("Not support the stream, needs the whole string")}
+		}
+
+		ts = append(ts, &stepTransformer{t: t, step: no + 1, name: step.op.Name})
+	}
+
+	return ChainTransformers(ts...), nil
+}
+
+// NewReader returns an io.Reader that reads the r processed by the steps, same as NewReader with the Transformer
+func (p *Pipeline) NewReader(r io.Reader) (io.Reader, error) {
+	t, err := p.Transformer()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewReader(r, t), nil
+}
+
+// NewWriter returns an io.WriteCloser that writes to w processed by the steps, same as NewWriter with the Transformer
+func (p *Pipeline) NewWriter(w io.Writer) (io.WriteCloser, error) {
+	t, err := p.Transformer()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewWriter(w, t), nil
+}
+
+// pipeline enum values of the built-in operations
+var (
+	pipelineEntityModes = map[string]int{"minimal": EntityEncodeMinimal, "named": EntityEncodeNamed, "numeric": EntityEncodeNumeric}
+	pipelineLinkStyles  = map[string]int{"inline": LinkStyleInline, "footnote": LinkStyleFootnote, "none": LinkStyleNone}
+	pipelineHashOutputs = map[string]int{"hex": int(HashOutputHex), "base64": int(HashOutputBase64), "raw": int(HashOutputRaw)}
+	pipelineByteUnits   = map[string]int{
+		"lower_single": int(LowerCaseSingle), "lower_double": int(LowerCaseDouble),
+		"upper_single": int(UpperCaseSingle), "upper_double": int(UpperCaseDouble),
+		"camel_double": int(CamelCaseDouble), "camel_long": int(CamelCaseLong),
+	}
+	pipelineEscapeDialects = map[string]int{
+		"mysql": EscapeMySQL, "postgresql": EscapePostgreSQL, "sqlite": EscapeSQLite, "shell": EscapeShell,
+		"c": EscapeC, "go": EscapeGo, "json": EscapeJSON, "javascript": EscapeJavaScript, "csv": EscapeCSV,
+		"ldap_dn": EscapeLDAPDN, "ldap_filter": EscapeLDAPFilter,
+	}
+)
+
+// pipelineHashAlgorithms returns the lower case names of the hash algorithms (md5, sha256, sha512/256, crc32, ...)
+func pipelineHashAlgorithms() map[string]int {
+	retval := make(map[string]int, len(hashAlgorithms))
+	for k, v := range hashAlgorithms {
+		retval[strings.ToLower(v.name)] = int(k)
+	}
+
+	return retval
+}
+
+// pipelineHMACAlgorithms returns the enum of the algo parameter of hmac, the algorithms of newHMAC (not the checksums)
+func pipelineHMACAlgorithms() map[string]int {
+	retval := make(map[string]int)
+	for k, v := range pipelineHashAlgorithms() {
+		if v >= int(HashMD5) && v <= int(HashSHA512_256) {
+			retval[k] = v
+		}
+	}
+
+	return retval
+}
+
+// pipelineFunc is the PipelineOp.Func of the method without the parameters
+func pipelineFunc(fn func(str string) string) func(string, PipelineArgs) (string, error) {
+	return func(str string, args PipelineArgs) (string, error) {
+		return fn(str), nil
+	}
+}
+
+// pipelineFuncErr is the PipelineOp.Func of the method without the parameters, returns an error
+func pipelineFuncErr(fn func(str string) (string, error)) func(string, PipelineArgs) (string, error) {
+	return func(str string, args PipelineArgs) (string, error) {
+		return fn(str)
+	}
+}
+
+// pipelineStream is the PipelineOp.Stream of the Transformer without the parameters
+func pipelineStream(fn func() Transformer) func(PipelineArgs) (Transformer, error) {
+	return func(args PipelineArgs) (Transformer, error) {
+		return fn(), nil
+	}
+}
+
+// pipelineBuiltins returns the built-in operations of the StringProc methods
+func pipelineBuiltins(s *StringProc) []PipelineOp {
+	hashAlgos := pipelineHashAlgorithms()
+
+	width := PipelineParam{Name: "width", Type: PipelineInt, Required: true}
+	fill := PipelineParam{Name: "fill", Type: PipelineString, Default: " "}
+	breakstr := PipelineParam{Name: "break", Type: PipelineString, Default: "\n"}
+	algo := PipelineParam{Name: "algo", Type: PipelineEnum, Required: true, Enum: hashAlgos}
+	hmacAlgo := PipelineParam{Name: "algo", Type: PipelineEnum, Required: true, Enum: pipelineHMACAlgorithms()}
+	output := PipelineParam{Name: "output", Type: PipelineEnum, Default: "hex", Enum: pipelineHashOutputs}
+	dialect := PipelineParam{Name: "dialect", Type: PipelineEnum, Required: true, Enum: pipelineEscapeDialects}
+
+	return []PipelineOp{
+		{Name: "add_slashes", Func: pipelineFunc(s.AddSlashes), Stream: pipelineStream(NewAddSlashesTransformer)},
+		{Name: "strip_slashes", Func: pipelineFunc(s.StripSlashes), Lines: true},
+		{Name: "nl2br", Func: pipelineFunc(s.Nl2Br), Stream: pipelineStream(NewNl2BrTransformer)},
+		{Name: "br2nl", Func: pipelineFunc(s.Br2Nl), Stream: pipelineStream(NewBr2NlTransformer)},
+		{Name: "strip_tags", Func: pipelineFuncErr(s.StripTags), Stream: pipelineStream(NewStripTagsTransformer)},
+		{Name: "decode_url", Func: pipelineFuncErr(s.DecodeURLEncoded), Stream: pipelineStream(NewDecodeURLEncodedTransformer)},
+		{Name: "decode_unicode_entities", Func: pipelineFuncErr(s.DecodeUnicodeEntities)},
+		{Name: "decode_html_entities", Func: pipelineFunc(s.DecodeHTMLEntities), Lines: true},
+		{
+			Name:   "encode_html_entities",
+			Params: []PipelineParam{{Name: "mode", Type: PipelineEnum, Default: "minimal", Enum: pipelineEntityModes}},
+			Func: func(str string, args PipelineArgs) (string, error) {
+				return s.EncodeHTMLEntities(str, args.Int("mode"))
+			},
+			Lines: true,
+		},
+		{
+			Name:   "word_wrap",
+			Params: []PipelineParam{width, breakstr},
+			Func: func(str string, args PipelineArgs) (string, error) {
+				return s.WordWrapSimple(str, args.Int("width"), args.String("break"))
+			},
+			Stream: func(args PipelineArgs) (Transformer, error) {
+				return NewWordWrapTransformer(args.Int("width"), args.String("break"))
+			},
+		},
+		{
+			Name:   "word_wrap_around",
+			Params: []PipelineParam{width, breakstr},
+			Func: func(str string, args PipelineArgs) (string, error) {
+				return s.WordWrapAround(str, args.Int("width"), args.String("break"))
+			},
+			Stream: func(args PipelineArgs) (Transformer, error) {
+				return NewWordWrapAroundTransformer(args.Int("width"), args.String("break"))
+			},
+		},
+		{
+			Name:   "pad_left",
+			Params: []PipelineParam{fill, width},
+			Func: func(str string, args PipelineArgs) (string, error) {
+				return s.PaddingLeft(str, args.String("fill"), args.Int("width")), nil
+			},
+		},
+		{
+			Name:   "pad_right",
+			Params: []PipelineParam{fill, width},
+			Func: func(str string, args PipelineArgs) (string, error) {
+				return s.PaddingRight(str, args.String("fill"), args.Int("width")), nil
+			},
+		},
+		{
+			Name:   "pad_both",
+			Params: []PipelineParam{fill, width},
+			Func: func(str string, args PipelineArgs) (string, error) {
+				return s.PaddingBoth(str, args.String("fill"), args.Int("width")), nil
+			},
+		},
+		{Name: "lower_case_first_words", Func: pipelineFunc(s.LowerCaseFirstWords), Lines: true},
+		{Name: "upper_case_first_words", Func: pipelineFunc(s.UpperCaseFirstWords), Lines: true},
+		{Name: "swap_case_first_words", Func: pipelineFunc(s.SwapCaseFirstWords), Lines: true},
+		{Name: "reverse", Func: pipelineFunc(s.ReverseStr)},
+		{Name: "reverse_graphemes", Func: pipelineFunc(s.ReverseGraphemes)},
+		{
+			Name:   "truncate",
+			Params: []PipelineParam{width, {Name: "tail", Type: PipelineString, Default: ""}},
+			Func: func(str string, args PipelineArgs) (string, error) {
+				return s.GraphemeTruncate(str, args.Int("width"), args.String("tail"))
+			},
+		},
+		{
+			Name: "number_fmt",
+			Func: func(str string, args PipelineArgs) (string, error) {
+				return s.NumberFmt(str)
+			},
+		},
+		{
+			Name: "human_byte_size",
+			Params: []PipelineParam{
+				{Name: "decimals", Type: PipelineInt, Default: 2},
+				{Name: "unit", Type: PipelineEnum, Default: "upper_double", Enum: pipelineByteUnits},
+			},
+			Func: func(str string, args PipelineArgs) (string, error) {
+				return s.HumanByteSize(str, args.Int("decimals"), uint8(args.Int("unit")))
+			},
+		},
+		{Name: "md5", Func: pipelineFuncErr(s.MD5Hash)},
+		{
+			Name:   "hash",
+			Params: []PipelineParam{algo, output},
+			Func: func(str string, args PipelineArgs) (string, error) {
+				return s.Hash(str, uint8(args.Int("algo")), uint8(args.Int("output")))
+			},
+		},
+		{
+			Name:   "hmac",
+			Params: []PipelineParam{{Name: "key", Type: PipelineString, Required: true}, hmacAlgo, output},
+			Func: func(str string, args PipelineArgs) (string, error) {
+				return s.HMAC(str, args.String("key"), uint8(args.Int("algo")), uint8(args.Int("output")))
+			},
+		},
+		{
+			Name:   "escape",
+			Params: []PipelineParam{dialect},
+			Func: func(str string, args PipelineArgs) (string, error) {
+				return s.EscapeString(str, args.Int("dialect"))
+			},
+		},
+		{
+			Name:   "unescape",
+			Params: []PipelineParam{dialect},
+			Func: func(str string, args PipelineArgs) (string, error) {
+				return s.UnescapeString(str, args.Int("dialect"))
+			},
+		},
+		{
+			Name:   "html2text",
+			Params: []PipelineParam{{Name: "link_style", Type: PipelineEnum, Default: "inline", Enum: pipelineLinkStyles}},
+			Func: func(str string, args PipelineArgs) (string, error) {
+				return s.HTML2Text(str, args.Int("link_style"))
+			},
+		},
+		{Name: "markdown2html", Func: pipelineFuncErr(s.Markdown2HTML)},
+		{Name: "html2markdown", Func: pipelineFuncErr(s.HTML2Markdown)},
+	}
+}
@@ -0,0 +1,308 @@
+package strutils_test
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	strutils "github.com/torden/go-strutil"
+)
+
+func Test_strutils_Pipeline(t *testing.T) {
+	t.Parallel()
+
+	registry := strutils.NewPipelineRegistry()
+
+	dataset := []struct {
+		spec string
+		str  string
+		ret  string
+	}{
+		{`[{"op":"strip_tags"},{"op":"pad_left","fill":"0","width":10}]`, "<b>1234</b>", "0000001234"},
+		{`[{"op":"decode_url"},{"op":"upper_case_first_words"}]`, "just%20a%20string", "Just A String"},
+		{`[{"op":"reverse"},{"op":"pad_both","fill":"*","width":7}]`, "abc", "**cba**"},
+		{`[{"op":"pad_right","width":5}]`, "ab", "ab   "},
+		{`[{"op":"number_fmt"}]`, "1234567", "1,234,567"},
+		{`[{"op":"human_byte_size","decimals":1,"unit":"upper_double"}]`, "2048", "2.0KB"},
+		{`[{"op":"human_byte_size"}]`, "2048", "2.00KB"},
+		{`[{"op":"md5"}]`, "123456789", "25f9e794323b453885f5181f1b624d0b"},
+		{`[{"op":"hash","algo":"md5"}]`, "123456789", "25f9e794323b453885f5181f1b624d0b"},
+		{`[{"op":"encode_html_entities"}]`, "<a>&</a>", "&lt;a&gt;&amp;&lt;/a&gt;"},
+		{`[{"op":"encode_html_entities"},{"op":"decode_html_entities"}]`, "<a>&</a>", "<a>&</a>"},
+		{`[{"op":"add_slashes"},{"op":"strip_slashes"}]`, `a\b`, `a\b`},
+		{`[{"op":"nl2br"},{"op":"br2nl"}]`, "a\nb", "a\nb"},
+		{`[{"op":"word_wrap","width":3,"break":"|"}]`, "abc def", "abc|def"},
+		{`[{"op":"truncate","width":6,"tail":"~"},{"op":"reverse_graphemes"}]`, "café bar", "~ éfac"},
+		{`[{"op":"escape","dialect":"json"}]`, `a"b`, `"a\"b"`},
+		{`[]`, "abc", "abc"},
+	}
+
+	// check : common
+	for _, v := range dataset {
+		p, err := registry.CompileJSON([]byte(v.spec))
+		assert.AssertNil(t, err, "Error : %v", err)
+
+		retval, err := p.Run(v.str)
+		assert.AssertNil(t, err, "Error : %v", err)
+		assert.AssertEquals(t, retval, v.ret, "Return Value mismatch.\nExpected: %q\nActual: %q", v.ret, retval)
+	}
+
+	// check : decoded spec (YAML, ...)
+	p, err := registry.Compile([]map[string]interface{}{
+		{"op": "strip_tags"},
+		{"op": "pad_left", "fill": "0", "width": 10},
+	})
+	assert.AssertNil(t, err, "Error : %v", err)
+
+	retval, err := p.Run("<b>1234</b>")
+	assert.AssertNil(t, err, "Error : %v", err)
+	assert.AssertEquals(t, retval, "0000001234", "Return Value mismatch.\nExpected: %q\nActual: %q", "0000001234", retval)
+
+	// check : runtime error
+	p, err = registry.CompileJSON([]byte(`[{"op":"strip_tags"},{"op":"number_fmt"}]`))
+	assert.AssertNil(t, err, "Error : %v", err)
+
+	_, err = p.Run("abc")
+	var chainErr *strutils.ChainError
+	assert.AssertTrue(t, errors.As(err, &chainErr), "Return Value mismatch.\nExpected: %v\nActual: %v", "*ChainError", err)
+	assert.AssertEquals(t, chainErr.Step, 2, "Return Value mismatch.\nExpected: %v\nActual: %v", 2, chainErr.Step)
+	assert.AssertEquals(t, chainErr.Name, "number_fmt", "Return Value mismatch.\nExpected: %v\nActual: %v", "number_fmt", chainErr.Name)
+}
+
+func Test_strutils_PipelineErrors(t *testing.T) {
+	t.Parallel()
+
+	registry := strutils.NewPipelineRegistry()
+
+	dataset := []struct {
+		spec  string
+		step  int
+		op    string
+		param string
+	}{
+		{`[{"op":"unknown"}]`, 1, "unknown", ""},
+		{`[{"fill":"0"}]`, 1, "<nil>", ""},
+		{`[{"op":"strip_tags"},{"op":"pad_left","fill":"0"}]`, 2, "pad_left", "width"},
+		{`[{"op":"pad_left","fill":"0","width":"10"}]`, 1, "pad_left", "width"},
+		{`[{"op":"pad_left","fill":"0","width":10.5}]`, 1, "pad_left", "width"},
+		{`[{"op":"pad_left","fill":0,"width":10}]`, 1, "pad_left", "fill"},
+		{`[{"op":"pad_left","width":10,"size":10}]`, 1, "pad_left", "size"},
+		{`[{"op":"human_byte_size","unit":"huge"}]`, 1, "human_byte_size", "unit"},
+		{`[{"op":"hash","algo":"unknown"}]`, 1, "hash", "algo"},
+		{`[{"op":"hmac","key":"secret","algo":"crc32"}]`, 1, "hmac", "algo"},
+		{`[{"op":"hmac","key":"secret","algo":"fnv64a"}]`, 1, "hmac", "algo"},
+		{`[{"op":"escape"}]`, 1, "escape", "dialect"},
+	}
+
+	// check : common
+	for _, v := range dataset {
+		_, err := registry.CompileJSON([]byte(v.spec))
+
+		var pipelineErr *strutils.PipelineError
+		if !errors.As(err, &pipelineErr) {
+			t.Errorf("Return Value mismatch.\nExpected: %v\nActual: %v (%v)", "*PipelineError", err, v.spec)
+			continue
+		}
+
+		assert.AssertEquals(t, pipelineErr.Step, v.step, "Return Value mismatch.\nExpected: %v\nActual: %v (%v)", v.step, pipelineErr.Step, v.spec)
+		assert.AssertEquals(t, pipelineErr.Op, v.op, "Return Value mismatch.\nExpected: %v\nActual: %v (%v)", v.op, pipelineErr.Op, v.spec)
+		assert.AssertEquals(t, pipelineErr.Param, v.param, "Return Value mismatch.\nExpected: %v\nActual: %v (%v)", v.param, pipelineErr.Param, v.spec)
+	}
+
+	_, err := registry.CompileJSON([]byte(`{"op":"strip_tags"}`))
+	assert.AssertNotNil(t, err, "Failure : Couldn't check the not array spec")
+}
+
+func Test_strutils_PipelineRegister(t *testing.T) {
+	t.Parallel()
+
+	registry := strutils.NewPipelineRegistry()
+
+	err := registry.Register(strutils.PipelineOp{
+		Name: "wrap_with",
+		Params: []strutils.PipelineParam{
+			{Name: "prefix", Type: strutils.PipelineString, Required: true},
+			{Name: "suffix", Type: strutils.PipelineString, Default: "]"},
+			{Name: "repeat", Type: strutils.PipelineInt, Default: 1},
+			{Name: "upper", Type: strutils.PipelineBool, Default: false},
+		},
+		Func: func(str string, args strutils.PipelineArgs) (string, error) {
+			if args.Bool("upper") {
+				str = strings.ToUpper(str)
+			}
+
+			return args.String("prefix") + strings.Repeat(str, args.Int("repeat")) + args.String("suffix"), nil
+		},
+	})
+	assert.AssertNil(t, err, "Error : %v", err)
+
+	p, err := registry.CompileJSON([]byte(`[{"op":"wrap_with","prefix":"[","repeat":2,"upper":true},{"op":"nl2br"}]`))
+	assert.AssertNil(t, err, "Error : %v", err)
+
+	retval, err := p.Run("ab")
+	assert.AssertNil(t, err, "Error : %v", err)
+	assert.AssertEquals(t, retval, "[ABAB]", "Return Value mismatch.\nExpected: %q\nActual: %q", "[ABAB]", retval)
+
+	found := false
+	for _, v := range registry.Names() {
+		found = found || v == "wrap_with"
+	}
+	assert.AssertTrue(t, found, "Failure : Couldn't find the registered op")
+
+	// check : not allowed op
+	dataset := []strutils.PipelineOp{
+		{Name: "Bad Name", Func: func(str string, args strutils.PipelineArgs) (string, error) { return str, nil }},
+		{Name: "no_func"},
+		{Name: "reserved", Params: []strutils.PipelineParam{{Name: "op"}}, Func: func(str string, args strutils.PipelineArgs) (string, error) { return str, nil }},
+		{Name: "duplicated", Params: []strutils.PipelineParam{{Name: "a"}, {Name: "a"}}, Func: func(str string, args strutils.PipelineArgs) (string, error) { return str, nil }},
+		{Name: "bad_type", Params: []strutils.PipelineParam{{Name: "a", Type: 99}}, Func: func(str string, args strutils.PipelineArgs) (string, error) { return str, nil }},
+		{Name: "bad_default", Params: []strutils.PipelineParam{{Name: "a", Type: strutils.PipelineInt, Default: "1"}}, Func: func(str string, args strutils.PipelineArgs) (string, error) { return str, nil }},
+	}
+
+	for _, v := range dataset {
+		err := registry.Register(v)
+		assert.AssertNotNil(t, err, "Failure : Couldn't check the not allowed op %v", v.Name)
+	}
+}
+
+func Test_strutils_PipelineStream(t *testing.T) {
+	t.Parallel()
+
+	registry := strutils.NewPipelineRegistry()
+
+	str := "<b>12</b> just%20a \\tmp 'line'\r\nsecond <i>line</i> here\n\nlast"
+
+	dataset := []struct {
+		spec string
+		ret  string
+	}{
+		{`[{"op":"strip_tags"},{"op":"upper_case_first_words"}]`, "12 Just A \\tmp 'line'\nSecond Line Here\nLast"},
+		{`[{"op":"decode_url"},{"op":"nl2br"}]`, "<b>12</b> just a \\tmp 'line'<br />second <i>line</i> here<br />last"},
+		{`[{"op":"upper_case_first_words"},{"op":"add_slashes"}]`, "<b>12</b> Just%20a \\\\tmp 'line'\r\nSecond <i>line</i> Here\n\nLast"},
+		{`[{"op":"word_wrap","width":4}]`, "<b>12</b>\njust%20a\n\\tmp\n'line'\r\nsecond\n<i>line</i>\nhere\n\nlast"},
+	}
+
+	// check : common, same as Run
+	for _, v := range dataset {
+		spec, expected := v.spec, v.ret
+
+		p, err := registry.CompileJSON([]byte(spec))
+		assert.AssertNil(t, err, "Error : %v", err)
+
+		retval, err := p.Run(str)
+		assert.AssertNil(t, err, "Error : %v", err)
+		assert.AssertEquals(t, retval, expected, "Return Value mismatch of Run.\nExpected: %q\nActual: %q (%v)", expected, retval, spec)
+
+		r, err := p.NewReader(strings.NewReader(str))
+		assert.AssertNil(t, err, "Error : %v", err)
+
+		buf, err := ioutil.ReadAll(r)
+		assert.AssertNil(t, err, "Error : %v", err)
+		assert.AssertEquals(t, string(buf), expected, "Return Value mismatch.\nExpected: %q\nActual: %q (%v)", expected, string(buf), spec)
+
+		var out bytes.Buffer
+		w, err := p.NewWriter(&out)
+		assert.AssertNil(t, err, "Error : %v", err)
+
+		for i := 0; i < len(str); i++ {
+			_, err = w.Write([]byte{str[i]})
+			assert.AssertNil(t, err, "Error : %v", err)
+		}
+
+		err = w.Close()
+		assert.AssertNil(t, err, "Error : %v", err)
+		assert.AssertEquals(t, out.String(), expected, "Return Value mismatch.\nExpected: %q\nActual: %q (%v)", expected, out.String(), spec)
+	}
+
+	// check : the operation needs the whole string
+	p, err := registry.CompileJSON([]byte(`[{"op":"strip_tags"},{"op":"pad_left","fill":"0","width":6}]`))
+	assert.AssertNil(t, err, "Error : %v", err)
+
+	_, err = p.NewReader(strings.NewReader(str))
+	var pipelineErr *strutils.PipelineError
+	assert.AssertTrue(t, errors.As(err, &pipelineErr), "Return Value mismatch.\nExpected: %v\nActual: %v", "*PipelineError", err)
+	assert.AssertEquals(t, pipelineErr.Step, 2, "Return Value mismatch.\nExpected: %v\nActual: %v", 2, pipelineErr.Step)
+	assert.AssertEquals(t, pipelineErr.Op, "pad_left", "Return Value mismatch.\nExpected: %v\nActual: %v", "pad_left", pipelineErr.Op)
+
+	_, err = p.NewWriter(ioutil.Discard)
+	assert.AssertTrue(t, errors.As(err, &pipelineErr), "Return Value mismatch.\nExpected: %v\nActual: %v", "*PipelineError", err)
+
+	// check : error of the step
+	p, err = registry.CompileJSON([]byte(`[{"op":"strip_tags"},{"op":"strip_slashes"},{"op":"word_wrap","width":0}]`))
+	assert.AssertNil(t, err, "Error : %v", err)
+
+	_, err = p.Transformer()
+	var chainErr *strutils.ChainError
+	assert.AssertTrue(t, errors.As(err, &chainErr), "Return Value mismatch.\nExpected: %v\nActual: %v", "*ChainError", err)
+	assert.AssertEquals(t, chainErr.Name, "word_wrap", "Return Value mismatch.\nExpected: %v\nActual: %v", "word_wrap", chainErr.Name)
+}
+
+func Test_strutils_PipelineRunStream(t *testing.T) {
+	t.Parallel()
+
+	registry := strutils.NewPipelineRegistry()
+
+	// parameters of the built-in operations, {"op":name} if not in
+	params := map[string]string{
+		"word_wrap":        `"width":10`,
+		"word_wrap_around": `"width":10,"break":"|"`,
+		"pad_left":         `"width":20`,
+		"pad_right":        `"width":20`,
+		"pad_both":         `"width":20`,
+		"truncate":         `"width":10`,
+		"hash":             `"algo":"sha256"`,
+		"hmac":             `"algo":"sha256","key":"secret"`,
+		"escape":           `"dialect":"json"`,
+		"unescape":         `"dialect":"json"`,
+	}
+
+	// the streaming operations, the others need the whole string
+	streams := map[string]bool{
+		"add_slashes": true, "strip_slashes": true, "nl2br": true, "br2nl": true, "strip_tags": true, "decode_url": true,
+		"decode_html_entities": true, "encode_html_entities": true, "word_wrap": true, "word_wrap_around": true,
+		"lower_case_first_words": true, "upper_case_first_words": true, "swap_case_first_words": true,
+	}
+
+	dataset := []string{
+		"",
+		"plain text",
+		"<b>Bold</b> &amp; %3Ci%3Etext%3C/i%3E\r\nsecond  line<br/>x\n\nthird \\line\\",
+		"The quick brown fox jumped over the lazy dog.\nAnd\tthe CAT slept\n",
+		"a+b%41 c\\d \\'e\\' <textarea><i>y</i></textarea> &lt;b&gt;x\r\n",
+		"caf\u00e9 &eacute; <title>t<b>i</b></title> 50% \\0\n",
+	}
+
+	// check : same output of Run and the stream for each built-in operation
+	for _, name := range registry.Names() {
+		spec := `[{"op":"` + name + `"}]`
+		if v, ok := params[name]; ok {
+			spec = `[{"op":"` + name + `",` + v + `}]`
+		}
+
+		p, err := registry.CompileJSON([]byte(spec))
+		assert.AssertNil(t, err, "Error : %v (%v)", err, spec)
+
+		_, err = p.Transformer()
+		if !streams[name] {
+			var pipelineErr *strutils.PipelineError
+			assert.AssertTrue(t, errors.As(err, &pipelineErr), "Return Value mismatch.\nExpected: %v\nActual: %v (%v)", "*PipelineError", err, spec)
+			continue
+		}
+		assert.AssertNil(t, err, "Error : %v (%v)", err, spec)
+
+		for _, str := range dataset {
+			expected, err := p.Run(str)
+			assert.AssertNil(t, err, "Error : %v (%v, %q)", err, spec, str)
+
+			r, err := p.NewReader(iotest.OneByteReader(strings.NewReader(str)))
+			assert.AssertNil(t, err, "Error : %v (%v)", err, spec)
+
+			buf, err := ioutil.ReadAll(r)
+			assert.AssertNil(t, err, "Error : %v (%v, %q)", err, spec, str)
+			assert.AssertEquals(t, string(buf), expected, "Return Value mismatch (%v, %q).\nExpected: %q\nActual: %q", spec, str, expected, string(buf))
+		}
+	}
+}
@@ -0,0 +1,273 @@
+package strutils
+
+import (
+	"errors"
+	"sort"
+	"strings"
+)
+
+// StripTagsPolicy is the allow list of StripTagsWithPolicy
+type StripTagsPolicy struct {
+	Elements         map[string][]string          // allowed element => allowed attributes of the element
+	GlobalAttrs      []string                     // allowed attributes on all allowed elements
+	URLSchemes       []string                     // allowed url schemes of url attributes (href, src, ...)
+	AllowRelativeURL bool                         // allow url without scheme (/path, ../path, #anchor)
+	ForceAttrs       map[string]map[string]string // element => attribute => value, added or overwritten. Ex) {"a": {"rel": "nofollow"}}
+}
+
+// NewUGCStripTagsPolicy Creates and returns a policy for user generated contents (comments, forum posts)
+func NewUGCStripTagsPolicy() *StripTagsPolicy {
+	return &StripTagsPolicy{
+		Elements: map[string][]string{
+			"a":          {"href", "title"},
+			"b":          nil,
+			"blockquote": {"cite"},
+			"br":         nil,
+			"code":       nil,
+			"del":        nil,
+			"em":         nil,
+			"h1":         nil,
+			"h2":         nil,
+			"h3":         nil,
+			"h4":         nil,
+			"h5":         nil,
+			"h6":         nil,
+			"hr":         nil,
+			"i":          nil,
+			"img":        {"src", "alt", "title", "width", "height"},
+			"li":         nil,
+			"ol":         nil,
+			"p":          nil,
+			"pre":        nil,
+			"s":          nil,
+			"strong":     nil,
+			"sub":        nil,
+			"sup":        nil,
+			"u":          nil,
+			"ul":         nil,
+		},
+		URLSchemes:       []string{"http", "https", "mailto"},
+		AllowRelativeURL: true,
+		ForceAttrs: map[string]map[string]string{
+			"a": {"rel": "nofollow"},
+		},
+	}
+}
+
+// attributes that has url
+var htmlURLAttrs = map[string]bool{"href": true, "src": true, "cite": true, "action": true, "formaction": true, "poster": true, "background": true, "longdesc": true, "usemap": true, "xlink:href": true}
+
+// elements without end tag
+var htmlVoidElements = map[string]bool{"area": true, "base": true, "br": true, "col": true, "embed": true, "hr": true, "img": true, "input": true, "link": true, "meta": true, "param": true, "source": true, "track": true, "wbr": true}
+
+// compiled StripTagsPolicy
+type stripTagsRule struct {
+	elements map[string]map[string]bool
+	global   map[string]bool
+	schemes  map[string]bool
+	relative bool
+	force    map[string]map[string]string
+}
+
+func (p *StripTagsPolicy) compile() *stripTagsRule {
+	rule := &stripTagsRule{
+		elements: make(map[string]map[string]bool, len(p.Elements)),
+		global:   make(map[string]bool, len(p.GlobalAttrs)),
+		schemes:  make(map[string]bool, len(p.URLSchemes)),
+		relative: p.AllowRelativeURL,
+		force:    make(map[string]map[string]string, len(p.ForceAttrs)),
+	}
+
+	for k, v := range p.Elements {
+		attrs := make(map[string]bool, len(v))
+		for _, a := range v {
+			attrs[strings.ToLower(a)] = true
+		}
+		rule.elements[strings.ToLower(k)] = attrs
+	}
+
+	for _, v := range p.GlobalAttrs {
+		rule.global[strings.ToLower(v)] = true
+	}
+
+	for _, v := range p.URLSchemes {
+		rule.schemes[strings.ToLower(v)] = true
+	}
+
+	for k, v := range p.ForceAttrs {
+		attrs := make(map[string]string, len(v))
+		for a, val := range v {
+			attrs[strings.ToLower(a)] = val
+		}
+		rule.force[strings.ToLower(k)] = attrs
+	}
+
+	return rule
+}
+
+// isAllowedURL checks the scheme of the url, (browsers ignore tab, newline in the url : "java\tscript:")
+func (r *stripTagsRule) isAllowedURL(val string) bool {
+	buf := make([]byte, 0, len(val))
+	for i := 0; i < len(val); i++ {
+		if val[i] > 32 && val[i] != 127 { // without control character and space
+			buf = append(buf, val[i])
+		}
+	}
+
+	for k, c := range buf {
+		switch {
+		case c == 58: // :
+			if k == 0 {
+				return r.relative
+			}
+			return r.schemes[strings.ToLower(string(buf[:k]))]
+
+		case c == 47 || c == 63 || c == 35: // / ? #
+			return r.relative
+
+		case isASCIIAlpha(c), k > 0 && (c >= 48 && c <= 57 || c == 43 || c == 45 || c == 46): // scheme = alpha *( alpha / digit / + / - / . )
+			continue
+
+		default:
+			return r.relative
+		}
+	}
+
+	return r.relative
+}
+
+// appendEscapedHTML appends str to buf with escaping & < > (and " in attribute value)
+func appendEscapedHTML(buf []byte, str string, attr bool) []byte {
+	for i := 0; i < len(str); i++ {
+		switch c := str[i]; {
+		case c == 38: // &
+			buf = append(buf, "&amp;"...)
+		case c == 60: // <
+			buf = append(buf, "&lt;"...)
+		case c == 62: // >
+			buf = append(buf, "&gt;"...)
+		case c == 34 && attr: // "
+			buf = append(buf, "&quot;"...)
+		default:
+			buf = append(buf, c)
+		}
+	}
+
+	return buf
+}
+
+func (r *stripTagsRule) appendStartTag(buf []byte, tok htmlToken) []byte {
+	allowed := r.elements[tok.Data]
+	force := r.force[tok.Data]
+
+	buf = append(buf, 60) // <
+	buf = append(buf, tok.Data...)
+
+	seen := make(map[string]bool, len(tok.Attr))
+	for _, v := range tok.Attr {
+		if seen[v.Key] || (!allowed[v.Key] && !r.global[v.Key]) {
+			continue
+		}
+
+		if _, ok := force[v.Key]; ok {
+			continue
+		}
+
+		if htmlURLAttrs[v.Key] && !r.isAllowedURL(v.Val) {
+			continue
+		}
+
+		seen[v.Key] = true
+		buf = append(buf, 32)
+		buf = append(buf, v.Key...)
+		buf = append(buf, `="`...)
+		buf = appendEscapedHTML(buf, v.Val, true)
+		buf = append(buf, 34)
+	}
+
+	// sorted, for the same output
+	keys := make([]string, 0, len(force))
+	for k := range force {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		buf = append(buf, 32)
+		buf = append(buf, k...)
+		buf = append(buf, `="`...)
+		buf = appendEscapedHTML(buf, force[k], true)
+		buf = append(buf, 34)
+	}
+
+	if htmlVoidElements[tok.Data] {
+		return append(buf, " />"...)
+	}
+
+	return append(buf, 62) // >
+}
+
+// StripTagsWithPolicy is remove all tag in string without allowed tags and attributes of the policy (like strip_tags of PHP)
+// NOTE : texts are html escaped, unbalanced tags are closed, contents of script, style and comment are dropped
+func (s *StringProc) StripTagsWithPolicy(str string, policy *StripTagsPolicy) (string, error) {
+	if policy == nil {
+		return "", errors.New("policy is nil")
+	}
+
+	rule := policy.compile()
+	buf := make([]byte, 0, len(str)) // prealloca
+
+	var stack []string
+
+	z := newHTMLTokenizer(str)
+	for {
+		tok, ok := z.next()
+		if !ok {
+			break
+		}
+
+		switch tok.Type {
+		case htmlTokenText, htmlTokenCDATA:
+			buf = appendEscapedHTML(buf, decodeHTMLEntities(tok.Data), false)
+
+		case htmlTokenStartTag, htmlTokenSelfClosing:
+			if _, ok := rule.elements[tok.Data]; !ok {
+				continue
+			}
+
+			buf = rule.appendStartTag(buf, tok)
+
+			if htmlVoidElements[tok.Data] {
+				continue
+			}
+
+			if tok.Type == htmlTokenSelfClosing {
+				buf = append(buf, "</"+tok.Data+">"...)
+				continue
+			}
+
+			stack = append(stack, tok.Data)
+
+		case htmlTokenEndTag:
+			// close up to the matched element, drop if not opened
+			for i := len(stack) - 1; i >= 0; i-- {
+				if stack[i] != tok.Data {
+					continue
+				}
+
+				for n := len(stack) - 1; n >= i; n-- {
+					buf = append(buf, "</"+stack[n]+">"...)
+				}
+				stack = stack[:i]
+				break
+			}
+		}
+	}
+
+	// close unclosed elements
+	for n := len(stack) - 1; n >= 0; n-- {
+		buf = append(buf, "</"+stack[n]+">"...)
+	}
+
+	return string(buf), nil
+}
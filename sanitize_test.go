@@ -0,0 +1,75 @@
+package strutils_test
+
+import (
+	"testing"
+
+	strutils "github.com/torden/go-strutil"
+)
+
+func Test_strutils_StripTagsWithPolicy(t *testing.T) {
+	t.Parallel()
+
+	policy := strutils.NewUGCStripTagsPolicy()
+
+	dataset := map[string]string{
+		"abcdefg":                      "abcdefg",
+		"<b>bold</b> <i>italic</i>":    "<b>bold</b> <i>italic</i>",
+		"<B CLASS=x>bold</B>":          "<b>bold</b>",
+		"<p>a<br>b</p>":                "<p>a<br />b</p>",
+		"<div><p>abc</p></div>":        "<p>abc</p>",
+		"<my-widget>abc</my-widget>":   "abc",
+		"<script>alert(1)</script>abc": "abc",
+		"<style>p{}</style>abc":        "abc",
+		"a<!-- comment -->b":           "ab",
+		"<p>unclosed":                  "<p>unclosed</p>",
+		"</b>stray":                    "stray",
+		"<b><i>x</b>y</i>":             "<b><i>x</i></b>y",
+		"1 < 2 & 3 > 2":                "1 &lt; 2 &amp; 3 &gt; 2",
+		"&lt;script&gt;":               "&lt;script&gt;",
+		"<b/>x":                        "<b></b>x",
+		`<a href="https://a.com/?a=1&amp;b=2">a</a>`:   `<a href="https://a.com/?a=1&amp;b=2" rel="nofollow">a</a>`,
+		`<a href="/path" title='t"t'>a</a>`:            `<a href="/path" title="t&quot;t" rel="nofollow">a</a>`,
+		`<a href="javascript:alert(1)">a</a>`:          `<a rel="nofollow">a</a>`,
+		`<a href="jav&#x61;script:alert(1)">a</a>`:     `<a rel="nofollow">a</a>`,
+		"<a href=\"java\tscript:alert(1)\">a</a>":      `<a rel="nofollow">a</a>`,
+		`<a href=" JAVASCRIPT:alert(1)">a</a>`:         `<a rel="nofollow">a</a>`,
+		`<a href="data:text/html,x">a</a>`:             `<a rel="nofollow">a</a>`,
+		`<a href="mailto:a@golang.org">a</a>`:          `<a href="mailto:a@golang.org" rel="nofollow">a</a>`,
+		`<a href="x" rel="follow" onclick="x()">a</a>`: `<a href="x" rel="nofollow">a</a>`,
+		`<img src="x.png" onerror="alert(1)">`:         `<img src="x.png" />`,
+		`<img src="x.png" src="y.png">`:                `<img src="x.png" />`,
+		`<img src=x.png alt=">">`:                      `<img src="x.png" alt="&gt;" />`,
+	}
+
+	// check : common
+	for k, v := range dataset {
+		retval, err := strproc.StripTagsWithPolicy(k, policy)
+		assert.AssertNil(t, err, "Error : %v", err)
+		assert.AssertEquals(t, retval, v, "Return Value mismatch (%v).\nExpected: %v\nActual: %v", k, v, retval)
+	}
+
+	// check : custom policy
+	custom := &strutils.StripTagsPolicy{
+		Elements:    map[string][]string{"span": nil, "a": {"href"}},
+		GlobalAttrs: []string{"class"},
+		URLSchemes:  []string{"https"},
+	}
+
+	dataset = map[string]string{
+		`<span class="x" id="y">a</span>`: `<span class="x">a</span>`,
+		`<a href="https://a.com">a</a>`:   `<a href="https://a.com">a</a>`,
+		`<a href="http://a.com">a</a>`:    `<a>a</a>`,
+		`<a href="/path">a</a>`:           `<a>a</a>`,
+		`<b>a</b>`:                        `a`,
+	}
+
+	for k, v := range dataset {
+		retval, err := strproc.StripTagsWithPolicy(k, custom)
+		assert.AssertNil(t, err, "Error : %v", err)
+		assert.AssertEquals(t, retval, v, "Return Value mismatch (%v).\nExpected: %v\nActual: %v", k, v, retval)
+	}
+
+	// check : nil policy
+	_, err := strproc.StripTagsWithPolicy("abc", nil)
+	assert.AssertNotNil(t, err, "Couldn't check the `nil policy`\nError : %v", err)
+}
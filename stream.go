@@ -0,0 +1,548 @@
+package strutils
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const (
+	streamBufferSize = 32 * 1024 // read buffer size of NewReader
+	streamMaxPending = 64 * 1024 // max length of a markup split across the buffers (StripTags, Br2Nl)
+)
+
+// Transformer is a streaming transformation of the string, used by NewReader and NewWriter
+// Transform appends the transformed src to dst, and returns the extended dst and the number of the consumed bytes of src
+// the not consumed bytes (a token split across the buffers) are given again with the following bytes, all of src must be consumed if atEOF
+// Reset is reset the state to reuse the Transformer
+type Transformer interface {
+	Transform(dst []byte, src []byte, atEOF bool) ([]byte, int, error)
+	Reset()
+}
+
+// nl2brTransformer is the streaming Nl2Br
+type nl2brTransformer struct{}
+
+// NewNl2BrTransformer returns a Transformer same as Nl2Br
+func NewNl2BrTransformer() Transformer {
+	return &nl2brTransformer{}
+}
+
+func (t *nl2brTransformer) Transform(dst []byte, src []byte, atEOF bool) ([]byte, int, error) {
+	l := len(src)
+	for i := 0; i < l; i++ {
+		n := bytes.IndexAny(src[i:], "\r\n")
+		if n < 0 {
+			return append(dst, src[i:]...), l, nil
+		}
+
+		dst = append(dst, src[i:i+n]...)
+		i += n
+
+		if i+1 >= l && !atEOF { // NL+CR or CR+NL, need a byte more
+			return dst, i, nil
+		}
+
+		dst = append(dst, "<br />"...)
+		if i+1 < l && (src[i+1] == 10 || src[i+1] == 13) {
+			i++
+		}
+	}
+
+	return dst, l, nil
+}
+
+func (t *nl2brTransformer) Reset() {}
+
+// br2nlTransformer is the streaming Br2Nl
+type br2nlTransformer struct{}
+
+// NewBr2NlTransformer returns a Transformer same as Br2Nl
+func NewBr2NlTransformer() Transformer {
+	return &br2nlTransformer{}
+}
+
+// htmlTagPartial returns true if str could be the beginning of the start or end tag of the element, more bytes are needed for htmlTagLen
+func htmlTagPartial(str string, name string) bool {
+	l := len(str)
+	if l == 0 || str[0] != 60 { // <
+		return false
+	}
+
+	// the name is immediately after < or </, same as the tokenizer (< br> is text)
+	i := 1
+
+	if i < l && str[i] == 47 { // /
+		i++
+	}
+
+	if l-i <= len(name) {
+		return strings.EqualFold(str[i:], name[:l-i])
+	}
+
+	if !strings.EqualFold(str[i:i+len(name)], name) {
+		return false
+	}
+	i += len(name)
+
+	if c := str[i]; c != 62 && c != 47 && !isHTMLSpace(c) { // > /
+		return false
+	}
+
+	var quote byte
+	for ; i < l; i++ {
+		switch c := str[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == 34 || c == 39: // " '
+			quote = c
+		case c == 62: // >
+			return false
+		}
+	}
+
+	return true
+}
+
+func (t *br2nlTransformer) Transform(dst []byte, src []byte, atEOF bool) ([]byte, int, error) {
+	s := string(src)
+	l := len(s)
+	for i := 0; i < l; i++ {
+		n := strings.IndexByte(s[i:], 60) // <
+		if n < 0 {
+			return append(dst, s[i:]...), l, nil
+		}
+
+		dst = append(dst, s[i:i+n]...)
+		i += n
+
+		str := s[i:]
+		if n, _ := htmlTagLen(str, "br"); n > 0 {
+			dst = append(dst, 10)
+			i += n - 1
+			continue
+		}
+
+		if !atEOF && len(str) <= streamMaxPending && htmlTagPartial(str, "br") {
+			return dst, i, nil
+		}
+
+		dst = append(dst, 60)
+	}
+
+	return dst, l, nil
+}
+
+func (t *br2nlTransformer) Reset() {}
+
+// stripTagsTransformer is the streaming StripTags
+type stripTagsTransformer struct {
+	s      StringProc
+	tried  int   // length of src at the last failed split, retried if doubled
+	form   bool  // %HH was decoded, '+' is a space
+	spaces int   // number of the pending white spaces
+	space  byte  // first pending white space
+	err    error // error of Canonicalize, returned atEOF like StripTags
+}
+
+// NewStripTagsTransformer returns a Transformer same as StripTags
+// the input is split into the chunks at the white space out of the markup, each chunk is decoded and stripped same as StripTags
+// a chunk is held until the markup (tag, comment, script, textarea, ...) is closed and '+' is decided (a space if the string has %HH)
+// NOTE : the chunk is not held more than 64KiB, a longer markup or '+' may differ from StripTags
+func NewStripTagsTransformer() Transformer {
+	return &stripTagsTransformer{}
+}
+
+// appendSpace appends the byte, the white spaces (2 or more) are replaced to a newline like StripTags
+func (t *stripTagsTransformer) appendSpace(dst []byte, c byte) []byte {
+	if isHTMLSpace(c) {
+		if t.spaces == 0 {
+			t.space = c
+		}
+		t.spaces++
+		return dst
+	}
+
+	dst = t.flush(dst)
+	return append(dst, c)
+}
+
+// flush appends the pending white spaces
+func (t *stripTagsTransformer) flush(dst []byte) []byte {
+	switch {
+	case t.spaces == 1:
+		dst = append(dst, t.space)
+	case t.spaces > 1:
+		dst = append(dst, 10)
+	}
+
+	t.spaces = 0
+	return dst
+}
+
+func (t *stripTagsTransformer) Transform(dst []byte, src []byte, atEOF bool) ([]byte, int, error) {
+	n := len(src)
+	if !atEOF {
+		if n < t.tried*2 {
+			return dst, 0, nil
+		}
+
+		// the chunk ends with a white space, an encoded character can't be split (%25 + 3C)
+		n = bytes.LastIndexAny(src, " \t\n\f\r") + 1
+		if n == 0 {
+			if len(src) <= streamMaxPending {
+				t.tried = len(src)
+				return dst, 0, nil
+			}
+			n = len(src)
+		}
+	}
+
+	canonicalized, err := t.s.Canonicalize(string(src[:n]), EncodingHTMLEntity, EncodingUnicodePercent, EncodingFormURL)
+	if canonicalized == nil {
+		return dst, 0, err
+	}
+
+	// '+' is a space if the string has %HH, the following chunk may have %HH
+	decoded := canonicalized.Str
+	if t.form {
+		decoded = strings.Replace(decoded, "+", " ", -1)
+	}
+
+	// every pass must end in the text, the following chunk is stripped independently
+	str, closed := stripTagsClosed(decoded)
+	if (!closed || strings.IndexByte(decoded, 43) >= 0) && !atEOF && len(src) <= streamMaxPending {
+		t.tried = len(src)
+		return dst, 0, nil
+	}
+
+	if err != nil && t.err == nil {
+		t.err = err
+	}
+	if canonicalized.Found[EncodingFormURL] > 0 {
+		t.form = true
+	}
+	t.tried = 0
+
+	for i := 0; i < len(str); i++ {
+		dst = t.appendSpace(dst, str[i])
+	}
+
+	if atEOF {
+		return t.flush(dst), n, t.err
+	}
+
+	return dst, n, nil
+}
+
+func (t *stripTagsTransformer) Reset() {
+	*t = stripTagsTransformer{}
+}
+
+// addSlashesTransformer is the streaming AddSlashes
+type addSlashesTransformer struct{}
+
+// NewAddSlashesTransformer returns a Transformer same as AddSlashes
+func NewAddSlashesTransformer() Transformer {
+	return &addSlashesTransformer{}
+}
+
+func (t *addSlashesTransformer) Transform(dst []byte, src []byte, atEOF bool) ([]byte, int, error) {
+	l := len(src)
+	for i := 0; i < l; i++ {
+		n := bytes.IndexByte(src[i:], 92) // \
+		if n < 0 {
+			return append(dst, src[i:]...), l, nil
+		}
+
+		dst = append(dst, src[i:i+n]...)
+		i += n
+
+		if i+1 >= l && !atEOF { // \\ , need a byte more
+			return dst, i, nil
+		}
+
+		dst = append(dst, 92, 92)
+		if i+1 < l && src[i+1] == 92 {
+			i++
+		}
+	}
+
+	return dst, l, nil
+}
+
+func (t *addSlashesTransformer) Reset() {}
+
+// decodeURLEncodedTransformer is the streaming DecodeURLEncoded
+type decodeURLEncodedTransformer struct {
+	s StringProc
+}
+
+// NewDecodeURLEncodedTransformer returns a Transformer same as DecodeURLEncoded
+func NewDecodeURLEncodedTransformer() Transformer {
+	return &decodeURLEncodedTransformer{}
+}
+
+func (t *decodeURLEncodedTransformer) Transform(dst []byte, src []byte, atEOF bool) ([]byte, int, error) {
+	l := len(src)
+	for i := 0; i < l; i++ {
+		n := bytes.IndexByte(src[i:], 37) // %
+		if n < 0 {
+			return append(dst, src[i:]...), l, nil
+		}
+
+		dst = append(dst, src[i:i+n]...)
+		i += n
+
+		if i+6 > l && !atEOF { // %XX or %uXXXX, need bytes more
+			return dst, i, nil
+		}
+
+		if i+3 <= l && src[i+1] != 117 && t.s.isHex(src[i+1]) && t.s.isHex(src[i+2]) {
+			dst = append(dst, t.s.unHex(src[i+1])<<4|t.s.unHex(src[i+2]))
+			i += 2
+			continue
+		}
+
+		if i+6 <= l && src[i+1] == 117 { // % + u
+			runeval, err := strconv.ParseInt(string(src[i+2:i+6]), 16, 64)
+			if err != nil {
+				return dst, i, err
+			}
+
+			dst = append(dst, string(rune(runeval))...)
+			i += 5
+			continue
+		}
+
+		dst = append(dst, 37)
+	}
+
+	return dst, l, nil
+}
+
+func (t *decodeURLEncodedTransformer) Reset() {}
+
+// wordWrapTransformer is the streaming WordWrapSimple
+type wordWrapTransformer struct {
+	wd       int
+	breakstr string
+	brpos    int
+}
+
+// NewWordWrapTransformer returns a Transformer same as WordWrapSimple
+func NewWordWrapTransformer(wd int, breakstr string) (Transformer, error) {
+	if wd < 1 {
+		return nil, errors.New("wd At least 1 or More")
+	}
+
+	return &wordWrapTransformer{wd: wd, breakstr: breakstr}, nil
+}
+
+func (t *wordWrapTransformer) Transform(dst []byte, src []byte, atEOF bool) ([]byte, int, error) {
+	for _, v := range src {
+		if (v == 9 || v == 32) && t.brpos >= t.wd {
+			dst = append(dst, t.breakstr...)
+			t.brpos = -1
+		} else {
+			dst = append(dst, v)
+		}
+		t.brpos++
+	}
+
+	return dst, len(src), nil
+}
+
+func (t *wordWrapTransformer) Reset() {
+	t.brpos = 0
+}
+
+// wordWrapAroundTransformer is the streaming WordWrapAround
+type wordWrapAroundTransformer struct {
+	wd       int
+	breakstr string
+	pos      int // position of the byte from the beginning
+	width    int // the next break is the first space at or after the width
+}
+
+// NewWordWrapAroundTransformer returns a Transformer same as WordWrapAround
+func NewWordWrapAroundTransformer(wd int, breakstr string) (Transformer, error) {
+	if wd < 1 {
+		return nil, errors.New("wd At least 1 or More")
+	}
+
+	return &wordWrapAroundTransformer{wd: wd, breakstr: breakstr, width: wd}, nil
+}
+
+func (t *wordWrapAroundTransformer) Transform(dst []byte, src []byte, atEOF bool) ([]byte, int, error) {
+	for _, v := range src {
+		if (v == 9 || v == 32) && t.pos >= t.width {
+			dst = append(dst, t.breakstr...)
+			t.width += t.wd
+		} else {
+			dst = append(dst, v)
+		}
+		t.pos++
+	}
+
+	return dst, len(src), nil
+}
+
+func (t *wordWrapAroundTransformer) Reset() {
+	t.pos = 0
+	t.width = t.wd
+}
+
+// chainTransformer is the Transformers in order
+type chainTransformer struct {
+	ts      []Transformer
+	pending [][]byte // not consumed input of the transformers except the first
+	bufs    [][]byte // output buffers of the transformers except the last
+}
+
+// ChainTransformers returns a Transformer that applies the transformers in order
+func ChainTransformers(ts ...Transformer) Transformer {
+	return &chainTransformer{
+		ts:      ts,
+		pending: make([][]byte, len(ts)),
+		bufs:    make([][]byte, len(ts)),
+	}
+}
+
+func (c *chainTransformer) Transform(dst []byte, src []byte, atEOF bool) ([]byte, int, error) {
+	if len(c.ts) == 0 {
+		return append(dst, src...), len(src), nil
+	}
+
+	in := src
+	consumed := 0
+	for i, t := range c.ts {
+		out := dst
+		if i < len(c.ts)-1 {
+			out = c.bufs[i][:0]
+		}
+
+		if i > 0 {
+			c.pending[i] = append(c.pending[i], in...)
+			in = c.pending[i]
+		}
+
+		out, n, err := t.Transform(out, in, atEOF)
+		if err != nil {
+			return dst, 0, err
+		}
+
+		if i == 0 {
+			consumed = n
+		} else {
+			c.pending[i] = c.pending[i][:copy(c.pending[i], in[n:])]
+		}
+
+		if i == len(c.ts)-1 {
+			return out, consumed, nil
+		}
+
+		c.bufs[i] = out
+		in = out
+	}
+
+	return dst, consumed, nil
+}
+
+func (c *chainTransformer) Reset() {
+	for i, t := range c.ts {
+		t.Reset()
+		c.pending[i] = c.pending[i][:0]
+	}
+}
+
+// transformReader is the io.Reader of NewReader
+type transformReader struct {
+	r   io.Reader
+	t   Transformer
+	buf []byte // read buffer
+	src []byte // not consumed input
+	dst []byte // transformed, not read yet
+	err error  // error of r or Transform
+}
+
+// NewReader returns an io.Reader that reads the transformed r by the Transformer
+func NewReader(r io.Reader, t Transformer) io.Reader {
+	return &transformReader{r: r, t: t, buf: make([]byte, streamBufferSize)}
+}
+
+func (r *transformReader) Read(p []byte) (int, error) {
+	for len(r.dst) == 0 {
+		if r.err != nil {
+			return 0, r.err
+		}
+
+		n, err := r.r.Read(r.buf)
+		r.src = append(r.src, r.buf[:n]...)
+
+		if err != nil && err != io.EOF {
+			r.err = err
+			continue
+		}
+
+		var m int
+		r.dst, m, r.err = r.t.Transform(r.dst[:0], r.src, err == io.EOF)
+		r.src = r.src[:copy(r.src, r.src[m:])]
+
+		if r.err == nil && err == io.EOF {
+			r.err = io.EOF
+		}
+	}
+
+	n := copy(p, r.dst)
+	r.dst = r.dst[n:]
+	return n, nil
+}
+
+// transformWriter is the io.WriteCloser of NewWriter
+type transformWriter struct {
+	w   io.Writer
+	t   Transformer
+	src []byte // not consumed input
+	dst []byte // output buffer
+}
+
+// NewWriter returns an io.WriteCloser that writes the transformed bytes to w by the Transformer
+// NOTE : Close must be called to flush the remaining bytes, w is not closed
+func NewWriter(w io.Writer, t Transformer) io.WriteCloser {
+	return &transformWriter{w: w, t: t}
+}
+
+func (w *transformWriter) write(p []byte, atEOF bool) error {
+	w.src = append(w.src, p...)
+
+	var n int
+	var err error
+	w.dst, n, err = w.t.Transform(w.dst[:0], w.src, atEOF)
+	w.src = w.src[:copy(w.src, w.src[n:])]
+
+	// the transformed bytes before the error are written, same as NewReader
+	if len(w.dst) > 0 {
+		if _, err := w.w.Write(w.dst); err != nil {
+			return err
+		}
+	}
+
+	return err
+}
+
+func (w *transformWriter) Write(p []byte) (int, error) {
+	if err := w.write(p, false); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+func (w *transformWriter) Close() error {
+	return w.write(nil, true)
+}
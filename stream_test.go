@@ -0,0 +1,228 @@
+package strutils_test
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	strutils "github.com/torden/go-strutil"
+)
+
+// streamString returns the transformed str by NewReader (reads a byte at a time if oneByte) and by NewWriter (writes a byte at a time)
+func streamString(t *testing.T, tr strutils.Transformer, str string, oneByte bool) string {
+	var r io.Reader = strings.NewReader(str)
+	if oneByte {
+		r = iotest.OneByteReader(r)
+	}
+
+	tr.Reset()
+	buf, err := ioutil.ReadAll(strutils.NewReader(r, tr))
+	assert.AssertNil(t, err, "Error (%q) : %v", str, err)
+
+	tr.Reset()
+	var wbuf bytes.Buffer
+	w := strutils.NewWriter(&wbuf, tr)
+	for i := 0; i < len(str); i++ {
+		_, err := w.Write([]byte{str[i]})
+		assert.AssertNil(t, err, "Error (%q) : %v", str, err)
+	}
+
+	err = w.Close()
+	assert.AssertNil(t, err, "Error (%q) : %v", str, err)
+	assert.AssertEquals(t, wbuf.String(), string(buf), "Return Value mismatch of NewWriter (%q).\nExpected: %v\nActual: %v", str, string(buf), wbuf.String())
+
+	return string(buf)
+}
+
+func Test_strutils_StreamTransformers(t *testing.T) {
+	t.Parallel()
+
+	wordwrap, err := strutils.NewWordWrapTransformer(5, "\n")
+	assert.AssertNil(t, err, "Error : %v", err)
+
+	wordwrapAround, err := strutils.NewWordWrapAroundTransformer(5, "\n")
+	assert.AssertNil(t, err, "Error : %v", err)
+
+	dataset := []struct {
+		tr   strutils.Transformer
+		fn   func(string) string
+		strs []string
+	}{
+		{
+			strutils.NewNl2BrTransformer(),
+			strproc.Nl2Br,
+			[]string{"", "abc", "a\nb", "a\r\nb", "a\n\rb", "a\n\nb", "a\n", "\r", "대한\n민국만세\r\n", "a\n\n\nb"},
+		},
+		{
+			strutils.NewBr2NlTransformer(),
+			strproc.Br2Nl,
+			[]string{"", "abc", "a<br>b", "a<br />b<BR/>c", "a<br class=\"x>y\">b", "a</br>b", "a<b>c</b>", "a<", "a<br", "a<brx>b", "a < b", "<br\t/>", "a < br>b", "a </ br>b", "a <  /br>b"},
+		},
+		{
+			strutils.NewAddSlashesTransformer(),
+			strproc.AddSlashes,
+			[]string{"", "abc", `a\b`, `a\\b`, `a\\\b`, `\`, `\\`, `대한\민국만세`},
+		},
+		{
+			strutils.NewDecodeURLEncodedTransformer(),
+			func(str string) string {
+				retval, _ := strproc.DecodeURLEncoded(str)
+				return retval
+			},
+			[]string{"", "abc", "a%20b", "%", "%2", "%zz", "a%u", "%uAC00%uB098", "%E1%84%8B%E1%85%A1%E1%86%AB", "100%", "a+b%2Fc", strings.Repeat("%uAC00 %41%4", 5000)},
+		},
+		{
+			wordwrap,
+			func(str string) string {
+				retval, _ := strproc.WordWrapSimple(str, 5, "\n")
+				return retval
+			},
+			[]string{"", "abc", "The quick brown fox jumped over the lazy dog.", "a\tb c\td e f g h"},
+		},
+		{
+			wordwrapAround,
+			func(str string) string {
+				retval, _ := strproc.WordWrapAround(str, 5, "\n")
+				return retval
+			},
+			[]string{"", "abc", " ", "The quick brown fox jumped over the lazy dog.", "a\tb c\td e f g h", "abcdefgh ij k", "Thisisaverylongwordtowrap and more"},
+		},
+		{
+			strutils.NewStripTagsTransformer(),
+			func(str string) string {
+				retval, _ := strproc.StripTags(str)
+				return retval
+			},
+			[]string{
+				"", "abc", "<b>bold</b> text", "a<br/>b", "a < b", "a<", "a</", "a</>b",
+				"<p>para1</p>\n\n\n<p>para2</p>", "<script>if (a < b) { x = '</scr' }</script>text",
+				"<style>p{}</style>  <title>a</title>", "<!-- comment <b> -->text", "<![CDATA[x < y]]>z",
+				"<!DOCTYPE html><html><body>a</body></html>", "<a href=\"x>y\">link</a>", "<? php ?>x", "<<b>>x",
+			},
+		},
+	}
+
+	// check : same as the StringProc methods
+	for _, v := range dataset {
+		for _, str := range v.strs {
+			expected := v.fn(str)
+
+			retval := streamString(t, v.tr, str, false)
+			assert.AssertEquals(t, retval, expected, "Return Value mismatch (%q).\nExpected: %q\nActual: %q", str, expected, retval)
+
+			retval = streamString(t, v.tr, str, true)
+			assert.AssertEquals(t, retval, expected, "Return Value mismatch of a byte at a time (%q).\nExpected: %q\nActual: %q", str, expected, retval)
+		}
+	}
+
+	// check : the large input, the tokens split across the buffers
+	large := strings.Repeat("line <br/>\r\n<b>bold</b> <!-- comment --> \\ ", 5000)
+	for _, v := range dataset {
+		expected := v.fn(large)
+		retval := streamString(t, v.tr, large, false)
+		assert.AssertTrue(t, retval == expected, "Return Value mismatch of the large input (%T)", v.tr)
+	}
+}
+
+func Test_strutils_StreamStripTags(t *testing.T) {
+	t.Parallel()
+
+	tr := strutils.NewStripTagsTransformer()
+
+	// check : same as StripTags, re-formed tags, rcdata, the encoded markup
+	dataset := []string{
+		"<<b>script>alert(1)<</b>/script>",
+		"<<b>script>alert(1)</script> after",
+		"<title>a<b>c</title> x",
+		"<textarea><b>x</b></textarea>",
+		"x <textarea><b></textarea> y",
+		"x <textarea> a <script> b </textarea> c </script> d",
+		"<plaintext><b>x</b> y",
+		"a < b <", "a </", "a &amp; b",
+		"&lt;b&gt;hi &lt;/b&gt; there",
+		"%3Cb%3Ehi %3C/b%3E there",
+		"%253Cb%253Ehi there",
+		"%u003Cb%u003Ehi there",
+		"&amp;lt;script&amp;gt;alert(1) &amp;lt;/script&amp;gt; x",
+		"&lt;b&gt;x<textarea><i>y</i></textarea>",
+		"<p>a  b</p>\n\n<p title=\"x  y\">c</p> \t d",
+		"<a href=\"x > y\">link</a> <!-- a comment\n with <b> spaces --> z",
+		"<script>\nif (a < b) {\n  x = '</scr'\n}\n</script> text",
+		"a+b%41", "a+b c+d", "a+b c %41 d+e", "%41 a+b c+d", "C++ &#43; x",
+	}
+
+	for _, str := range dataset {
+		expected, _ := strproc.StripTags(str)
+
+		retval := streamString(t, tr, str, true)
+		assert.AssertEquals(t, retval, expected, "Return Value mismatch of a byte at a time (%q).\nExpected: %q\nActual: %q", str, expected, retval)
+
+		retval = streamString(t, tr, str, false)
+		assert.AssertEquals(t, retval, expected, "Return Value mismatch (%q).\nExpected: %q\nActual: %q", str, expected, retval)
+	}
+
+	// check : the error of the decoding depth is returned at EOF with the stripped
+	str := "<b>x</b> %25" + strings.Repeat("25", 20) + "3Cscript%3E"
+	expected, experr := strproc.StripTags(str)
+	assert.AssertNotNil(t, experr, "Couldn't check the `maximum decoding depth`\nError : %v", experr)
+
+	tr.Reset()
+	buf, err := ioutil.ReadAll(strutils.NewReader(iotest.OneByteReader(strings.NewReader(str)), tr))
+	assert.AssertNotNil(t, err, "Couldn't check the `maximum decoding depth`\nError : %v", err)
+	assert.AssertEquals(t, string(buf), expected, "Return Value mismatch.\nExpected: %q\nActual: %q", expected, string(buf))
+}
+
+func Test_strutils_ChainTransformers(t *testing.T) {
+	t.Parallel()
+
+	wordwrap, err := strutils.NewWordWrapTransformer(10, "\n")
+	assert.AssertNil(t, err, "Error : %v", err)
+
+	chain := strutils.ChainTransformers(strutils.NewDecodeURLEncodedTransformer(), strutils.NewStripTagsTransformer(), wordwrap, strutils.NewNl2BrTransformer())
+
+	str := "%3Cb%3EJust%3C/b%3E a String Processing Library\nfor Go-lang"
+	expected := "Just a String<br />Processing<br />Library<br />for<br />Go-lang"
+
+	retval := streamString(t, chain, str, false)
+	assert.AssertEquals(t, retval, expected, "Return Value mismatch.\nExpected: %q\nActual: %q", expected, retval)
+
+	retval = streamString(t, chain, str, true)
+	assert.AssertEquals(t, retval, expected, "Return Value mismatch of a byte at a time.\nExpected: %q\nActual: %q", expected, retval)
+
+	// check : nested NewReader is same as the chain
+	r := strutils.NewReader(strutils.NewReader(strings.NewReader("a\\b\nc"), strutils.NewAddSlashesTransformer()), strutils.NewNl2BrTransformer())
+	buf, err := ioutil.ReadAll(r)
+	assert.AssertNil(t, err, "Error : %v", err)
+	assert.AssertEquals(t, string(buf), `a\\b<br />c`, "Return Value mismatch.\nExpected: %q\nActual: %q", `a\\b<br />c`, string(buf))
+
+	// check : empty chain
+	retval = streamString(t, strutils.ChainTransformers(), "abc", true)
+	assert.AssertEquals(t, retval, "abc", "Return Value mismatch.\nExpected: %q\nActual: %q", "abc", retval)
+}
+
+func Test_strutils_StreamErrors(t *testing.T) {
+	t.Parallel()
+
+	// check : not allow wd
+	_, err := strutils.NewWordWrapTransformer(0, "\n")
+	assert.AssertNotNil(t, err, "Couldn't check the `not allow wd`\nError : %v", err)
+
+	_, err = strutils.NewWordWrapAroundTransformer(0, "\n")
+	assert.AssertNotNil(t, err, "Couldn't check the `not allow wd`\nError : %v", err)
+
+	// check : invalid unicode entity, the decoded bytes before the error are read
+	buf, err := ioutil.ReadAll(strutils.NewReader(strings.NewReader("a%20b%uZZZZc"), strutils.NewDecodeURLEncodedTransformer()))
+	assert.AssertNotNil(t, err, "Couldn't check the `invalid unicode entity`\nError : %v", err)
+	assert.AssertEquals(t, string(buf), "a b", "Return Value mismatch.\nExpected: %q\nActual: %q", "a b", string(buf))
+
+	w := strutils.NewWriter(ioutil.Discard, strutils.ChainTransformers(strutils.NewAddSlashesTransformer(), strutils.NewDecodeURLEncodedTransformer()))
+	_, err = w.Write([]byte("a%uZZZZc"))
+	assert.AssertNotNil(t, err, "Couldn't check the `invalid unicode entity`\nError : %v", err)
+
+	// check : read error
+	_, err = ioutil.ReadAll(strutils.NewReader(iotest.ErrReader(io.ErrUnexpectedEOF), strutils.NewNl2BrTransformer()))
+	assert.AssertEquals(t, err, io.ErrUnexpectedEOF, "Error mismatch.\nExpected: %v\nActual: %v", io.ErrUnexpectedEOF, err)
+}
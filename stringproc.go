@@ -1,14 +1,9 @@
 package strutils
 
 import (
-	"crypto/md5"
-	"encoding/hex"
 	"errors"
 	"fmt"
-	"html"
-	"io"
 	"math"
-	"net/url"
 	"os"
 	"reflect"
 	"regexp"
@@ -22,10 +17,7 @@ import (
 var numericPattern = regexp.MustCompile(`^[-+]?[0-9]*\.?[0-9]+([eE][-+]?[0-9]+)?$`)
 
 var (
-	tagElementsPattern   = regexp.MustCompile(`(?ims)(?P<tag><(/*\s*|\?*|\!*)(figcaption|expression|blockquote|plaintext|textarea|progress|optgroup|noscript|noframes|menuitem|frameset|fieldset|!DOCTYPE|datalist|colgroup|behavior|basefont|summary|section|isindex|details|caption|bgsound|article|address|acronym|strong|strike|source|select|script|output|option|object|legend|keygen|ilayer|iframe|header|footer|figure|dialog|center|canvas|button|applet|video|track|title|thead|tfoot|tbody|table|style|small|param|meter|layer|label|input|frame|embed|blink|audio|aside|alert|time|span|samp|ruby|meta|menu|mark|main|link|html|head|form|font|code|cite|body|base|area|abbr|xss|xml|wbr|var|svg|sup|sub|pre|nav|map|kbd|ins|img|div|dir|dfn|del|col|big|bdo|bdi|!--|ul|tt|tr|th|td|rt|rp|ol|li|hr|em|dt|dl|dd|br|u|s|q|p|i|b|a|(h[0-9]+))([^><]*)([><]*))`)
-	whiteSpacePattern    = regexp.MustCompile(`(?im)\s{2,}`)
-	entityEncodedPattern = regexp.MustCompile(`(?ims)(&(?:[a-z0-9]{2,8}|#[0-9]{2,3});)`)
-	urlEncodedPattern    = regexp.MustCompile(`(?ims)(%[a-zA-Z0-9]{2})`)
+	whiteSpacePattern = regexp.MustCompile(`(?im)\s{2,}`)
 )
 
 // for debug
@@ -42,6 +34,7 @@ func NewStringProc() *StringProc {
 }
 
 // AddSlashes is quote string with slashes
+// NOTE : only the backslash is quoted (not the quotes), use EscapeString for the string literals of SQL, shell, C, JSON, ...
 func (s *StringProc) AddSlashes(str string) string {
 	l := len(str)
 
@@ -52,7 +45,7 @@ func (s *StringProc) AddSlashes(str string) string {
 		buf = append(buf, str[i])
 
 		switch str[i] {
-		case 92: // Dec : /
+		case 92: // Dec : \ (backslash)
 
 			if l >= i+1 {
 				buf = append(buf, 92)
@@ -67,22 +60,52 @@ func (s *StringProc) AddSlashes(str string) string {
 	return string(buf)
 }
 
-// StripSlashes is Un-quotes a quoted string
+// StripSlashes is Un-quotes a quoted string (like stripslashes of PHP)
+// NOTE : a backslash is removed, \\ is a backslash and \0 is NUL
 func (s *StringProc) StripSlashes(str string) string {
 	l := len(str)
 	buf := make([]byte, 0, l) // prealloca
 
 	for i := 0; i < l; i++ {
+		if str[i] != 92 { // backslash
+			buf = append(buf, str[i])
+			continue
+		}
 
-		buf = append(buf, str[i])
-		if l > i+1 && str[i+1] == 92 {
+		if l > i+1 {
 			i++
+			if str[i] == 48 { // \0
+				buf = append(buf, 0)
+			} else {
+				buf = append(buf, str[i])
+			}
 		}
 	}
 
 	return string(buf)
 }
 
+// StripSlashesWithMode : the mode of StripSlashesWithMode
+const (
+	StripSlashesPHP    = 0 // stripslashes of PHP, the same as StripSlashes
+	StripSlashesEscape = 1 // interpret the escape sequences of C (\n, \t, \xHH, \uHHHH, \ooo, ...), unknown escapes are the character itself
+	StripSlashesStrict = 2 // interpret the escape sequences of C, returns an error with the position for unknown or truncated escapes
+)
+
+// StripSlashesWithMode is Un-quotes a quoted string with the mode (StripSlashesPHP, StripSlashesEscape, StripSlashesStrict)
+func (s *StringProc) StripSlashesWithMode(str string, mode int) (string, error) {
+	switch mode {
+	case StripSlashesPHP:
+		return s.StripSlashes(str), nil
+	case StripSlashesEscape:
+		return s.unescapeC(str, 0, 0, false)
+	case StripSlashesStrict:
+		return s.unescapeC(str, 0, 0, true)
+	}
+
+	return "", fmt.Errorf("Not allow mode parameter : %v", mode)
+}
+
 // Nl2Br is breakstr inserted before looks like space (CRLF , LFCR, SPACE, NL)
 func (s *StringProc) Nl2Br(str string) string {
 	// BenchmarkNl2Br-8                   	10000000	      3398 ns/op
@@ -113,53 +136,237 @@ func (s *StringProc) Nl2Br(str string) string {
 
 // Br2Nl is replaces HTML line breaks to a newline
 func (s *StringProc) Br2Nl(str string) string {
-	// <br> , <br /> , <br/>
-	// <BR> , <BR /> , <BR/>
+	// <br> , <br /> , <br/> , <BR> , <BR /> , <BR/>
+	// <br  /> , <br class="x"> , <BR\t/> , </br>
 	nlchar := []byte("\n")
 
 	l := len(str)
 	buf := make([]byte, 0, l) // prealloca
 
 	for i := 0; i < l; i++ {
-		switch str[i] {
+		if str[i] == 60 { // <
+			if n, _ := htmlTagLen(str[i:], "br"); n > 0 {
+				buf = append(buf, nlchar...)
+				i += n - 1
+				continue
+			}
+		}
 
-		case 60: //<
+		buf = append(buf, str[i])
+	}
 
-			if l >= i+3 {
+	return string(buf)
+}
+
+// htmlTagLen returns the length of the start or end tag of the element at the beginning of str, and true if it is the end tag
+// white spaces, attributes and self-closing are allowed. Ex) <br>, <BR/>, <br class="x">, <BR\t/>, </br>
+func htmlTagLen(str string, name string) (int, bool) {
+	l := len(str)
+	if l < 2 || str[0] != 60 { // <
+		return 0, false
+	}
 
-				/*
-					b = 98
-					B = 66
-					r = 82
-					R = 114
-					SPACE = 32
-					/ = 47
-					> = 62
-				*/
+	// the name is immediately after < or </, same as the tokenizer (< br> is text)
+	i := 1
 
-				if l >= i+3 && ((str[i+1] == 98 || str[i+1] == 66) && (str[i+2] == 82 || str[i+2] == 114) && str[i+3] == 62) { // <br> || <BR>
-					buf = append(buf, nlchar...)
-					i += 3
-					continue
+	closing := false
+	if i < l && str[i] == 47 { // /
+		closing = true
+		i++
+	}
+
+	if l-i <= len(name) || !strings.EqualFold(str[i:i+len(name)], name) {
+		return 0, false
+	}
+	i += len(name)
+
+	if c := str[i]; c != 62 && c != 47 && !isHTMLSpace(c) { // > /
+		return 0, false
+	}
+
+	// attributes, quoted value may have >
+	var quote byte
+	for ; i < l; i++ {
+		switch c := str[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == 34 || c == 39: // " '
+			quote = c
+		case c == 62: // >
+			return i + 1, closing
+		}
+	}
+
+	return 0, false
+}
+
+// Nl2BrWithOptions, Br2NlWithOptions : the line break tag
+const (
+	BrTagXHTML   = 0 // <br />
+	BrTagHTML    = 1 // <br>
+	BrTagCompact = 2 // <br/>
+)
+
+// Nl2BrOptions is the options of Nl2BrWithOptions and Br2NlWithOptions
+// NOTE : Br2NlWithOptions with the same options reverts Nl2BrWithOptions
+type Nl2BrOptions struct {
+	Tag         int  // BrTagXHTML, BrTagHTML, BrTagCompact
+	KeepNewline bool // keep the newline after the tag (Br2Nl : the newline after the tag is not doubled)
+	EscapeHTML  bool // html escape the text first (Br2Nl : unescape the html entities)
+	Paragraph   bool // wrap the paragraphs (separated by blank lines) in <p></p> (Br2Nl : paragraphs are separated by a blank line)
+}
+
+// newlineLen returns the length of the newline (LF, CR, CRLF, LFCR) at the beginning of str
+func newlineLen(str string) int {
+	if len(str) == 0 || (str[0] != 10 && str[0] != 13) {
+		return 0
+	}
+
+	if len(str) > 1 && (str[1] == 10 || str[1] == 13) && str[1] != str[0] { // CRLF, LFCR
+		return 2
+	}
+
+	return 1
+}
+
+func appendNl2Br(buf []byte, str string, brtag string, keep bool) []byte {
+	for i := 0; i < len(str); i++ {
+		n := newlineLen(str[i:])
+		if n == 0 {
+			buf = append(buf, str[i])
+			continue
+		}
+
+		buf = append(buf, brtag...)
+		if keep {
+			buf = append(buf, str[i:i+n]...)
+		}
+		i += n - 1
+	}
+
+	return buf
+}
+
+// Nl2BrWithOptions is Inserts HTML line breaks before all newlines in a string with the options (nil is <br /> without the newline)
+// NOTE : CRLF and LFCR are a newline, but LF+LF is two newlines (unlike Nl2Br)
+func (s *StringProc) Nl2BrWithOptions(str string, opts *Nl2BrOptions) (string, error) {
+	if opts == nil {
+		opts = &Nl2BrOptions{}
+	}
+
+	var brtag string
+	switch opts.Tag {
+	case BrTagXHTML:
+		brtag = "<br />"
+	case BrTagHTML:
+		brtag = "<br>"
+	case BrTagCompact:
+		brtag = "<br/>"
+	default:
+		return "", fmt.Errorf("Not allow Tag option : %v", opts.Tag)
+	}
+
+	if opts.EscapeHTML {
+		str, _ = s.EncodeHTMLEntities(str, EntityEncodeMinimal)
+	}
+
+	buf := make([]byte, 0, len(str)*2) // prealloca
+
+	if !opts.Paragraph {
+		return string(appendNl2Br(buf, str, brtag, opts.KeepNewline)), nil
+	}
+
+	// paragraphs, separated by the lines have only white spaces
+	var para []byte
+	for len(str) > 0 {
+		line := str
+		n := strings.IndexAny(str, "\r\n")
+		if n < 0 {
+			str = ""
+		} else {
+			n += newlineLen(str[n:])
+			line, str = str[:n], str[n:]
+		}
+
+		if strings.TrimSpace(line) != "" {
+			para = append(para, line...)
+			if len(str) > 0 {
+				continue
+			}
+		}
+
+		if len(para) == 0 {
+			continue
+		}
+
+		if len(buf) > 0 && opts.KeepNewline {
+			buf = append(buf, "\n\n"...)
+		}
+
+		buf = append(buf, "<p>"...)
+		buf = appendNl2Br(buf, strings.TrimRight(string(para), "\r\n"), brtag, opts.KeepNewline)
+		buf = append(buf, "</p>"...)
+		para = para[:0]
+	}
+
+	return string(buf), nil
+}
+
+// Br2NlWithOptions is replaces HTML line breaks (and paragraphs) to newlines with the options (nil is the same as Br2Nl)
+func (s *StringProc) Br2NlWithOptions(str string, opts *Nl2BrOptions) string {
+	if opts == nil {
+		opts = &Nl2BrOptions{}
+	}
+
+	l := len(str)
+	buf := make([]byte, 0, l) // prealloca
+
+	para := false // after </p>
+	for i := 0; i < l; i++ {
+		if str[i] == 60 { // <
+			if n, _ := htmlTagLen(str[i:], "br"); n > 0 {
+				if para {
+					buf = append(buf, "\n\n"...)
+					para = false
 				}
 
-				if l >= i+4 && ((str[i+1] == 98 || str[i+1] == 66) && (str[i+2] == 82 || str[i+2] == 114) && str[i+3] == 47 && str[i+4] == 62) { // <br/> || <BR/>
-					buf = append(buf, nlchar...)
-					i += 4
-					continue
+				buf = append(buf, 10)
+				i += n
+				if opts.KeepNewline {
+					i += newlineLen(str[i:])
 				}
+				i--
+				continue
+			}
 
-				if l >= i+5 && ((str[i+1] == 98 || str[i+1] == 66) && (str[i+2] == 82 || str[i+2] == 114) && str[i+3] == 32 && str[i+4] == 47 && str[i+5] == 62) { // <br /> || <BR />
-					buf = append(buf, nlchar...)
-					i += 5
-					continue
+			if n, closing := htmlTagLen(str[i:], "p"); n > 0 && opts.Paragraph {
+				if closing {
+					para = true
+				} else if para && len(buf) > 0 {
+					buf = append(buf, "\n\n"...)
+					para = false
 				}
+
+				// white spaces between paragraphs
+				for i += n; closing && i < l && isHTMLSpace(str[i]); i++ {
+				}
+				i--
+				continue
 			}
-			fallthrough
+		}
 
-		default:
-			buf = append(buf, str[i])
+		if para {
+			buf = append(buf, "\n\n"...)
+			para = false
 		}
+
+		buf = append(buf, str[i])
+	}
+
+	if opts.EscapeHTML {
+		return decodeHTMLEntities(string(buf))
 	}
 
 	return string(buf)
@@ -625,22 +832,7 @@ func (s *StringProc) HumanByteSize(obj interface{}, decimals int, unit uint8) (s
 		return "", fmt.Errorf("Not Support obj.(%v)", reflect.TypeOf(obj))
 	}
 
-	var sizeStr []string
-
-	switch unit {
-	case LowerCaseSingle:
-		sizeStr = sizeStrLowerCaseSingle
-	case LowerCaseDouble:
-		sizeStr = sizeStrLowerCaseDouble
-	case UpperCaseSingle:
-		sizeStr = sizeStrUpperCaseSingle
-	case UpperCaseDouble:
-		sizeStr = sizeStrUpperCaseDouble
-	case CamelCaseDouble:
-		sizeStr = sizeStrCamelCaseDouble
-	case CamelCaseLong:
-		sizeStr = sizeStrCamelCaseLong
-	}
+	sizeStr := humanByteUnits[unit]
 
 	strNumLen := len(strNum)
 
@@ -681,95 +873,128 @@ func (s *StringProc) HumanFileSize(filepath string, decimals int, unit uint8) (s
 }
 
 // compare with map
-var recursiveDepth = 0
-
 var recursiveDepthKeypList = struct {
 	sync.RWMutex
 	ar []string
 }{ar: make([]string, 32)}
 
 func (s *StringProc) compareMap(compObj1 reflect.Value, compObj2 reflect.Value) (bool, error) {
-	recursiveDepth++
 	var valueCompareErr bool
 
+	// keys of the parent maps, the key of the sibling is replaced
+	recursiveDepthKeypList.RLock()
+	depth := len(recursiveDepthKeypList.ar)
+	recursiveDepthKeypList.RUnlock()
+
 	for _, k := range compObj1.MapKeys() {
 
 		recursiveDepthKeypList.Lock()
-		recursiveDepthKeypList.ar = append(recursiveDepthKeypList.ar, k.String())
+		recursiveDepthKeypList.ar = append(recursiveDepthKeypList.ar[:depth], fmt.Sprintf("%v", k))
+		depthStr := strings.Join(recursiveDepthKeypList.ar, "][")
 		recursiveDepthKeypList.Unlock()
 
+		val1 := compObj1.MapIndex(k)
+		val2 := compObj2.MapIndex(k)
+
+		// check : Key, the size is same so no key only in obj2 if all keys of obj1 are in obj2
+		if !val2.IsValid() {
+			return false, fmt.Errorf("Missing Key : obj2[%v] doesn't exist", depthStr)
+		}
+
+		// the value of interface{} (decoded JSON, ...) is compared by the dynamic value
+		if val1.Kind() == reflect.Interface && val2.Kind() == reflect.Interface {
+			val1 = val1.Elem()
+			val2 = val2.Elem()
+		}
+
 		// check : Type
-		if compObj1.MapIndex(k).Kind() != compObj2.MapIndex(k).Kind() {
-			return false, fmt.Errorf("Different Type : (obj1[%v] type is `%v`) != (obj2[%v] type is `%v`)", k, compObj1.MapIndex(k).Kind(), k, compObj2.MapIndex(k).Kind())
+		if val1.Kind() != val2.Kind() {
+			return false, fmt.Errorf("Different Type : (obj1[%v] type is `%v`) != (obj2[%v] type is `%v`)", depthStr, val1.Kind(), depthStr, val2.Kind())
 		}
 
-		switch compObj1.MapIndex(k).Kind() {
+		switch val1.Kind() {
+
+		// nil of interface{}
+		case reflect.Invalid:
 
 		// String
 		case reflect.String:
-			if compObj1.MapIndex(k).String() != compObj2.MapIndex(k).String() {
+			if val1.String() != val2.String() {
 				valueCompareErr = true
 			}
 
 		// Integer
 		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-			if compObj1.MapIndex(k).Int() != compObj2.MapIndex(k).Int() {
+			if val1.Int() != val2.Int() {
 				valueCompareErr = true
 			}
 
 		// Un-signed Integer
 		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-			if compObj1.MapIndex(k).Uint() != compObj2.MapIndex(k).Uint() {
+			if val1.Uint() != val2.Uint() {
 				valueCompareErr = true
 			}
 
 		// Float
 		case reflect.Float32, reflect.Float64:
-			if compObj1.MapIndex(k).Float() != compObj2.MapIndex(k).Float() {
+			if val1.Float() != val2.Float() {
 				valueCompareErr = true
 			}
 
 		// Boolean
 		case reflect.Bool:
-			if compObj1.MapIndex(k).Bool() != compObj2.MapIndex(k).Bool() {
+			if val1.Bool() != val2.Bool() {
 				valueCompareErr = true
 			}
 
 		// Complex
 		case reflect.Complex64, reflect.Complex128:
-			if compObj1.MapIndex(k).Complex() != compObj2.MapIndex(k).Complex() {
+			if val1.Complex() != val2.Complex() {
+				valueCompareErr = true
+			}
+
+		// Slice
+		case reflect.Slice:
+			if !anyEqual(val1.Interface(), val2.Interface()) {
 				valueCompareErr = true
 			}
 
 		// Map : recursive loop
 		case reflect.Map:
-			retval, err := s.compareMap(compObj1.MapIndex(k), compObj2.MapIndex(k))
+			if val1.Len() != val2.Len() {
+				return false, fmt.Errorf("Different Size : (obj1[%v] := %d) != (obj2[%v] := %d)", depthStr, val1.Len(), depthStr, val2.Len())
+			}
+
+			retval, err := s.compareMap(val1, val2)
 			if !retval {
 				return retval, err
 			}
 
 		default:
-			return false, fmt.Errorf("Not Support Compare : (obj1[%v] := %v) != (obj2[%v] := %v)", k, compObj1.MapIndex(k), k, compObj2.MapIndex(k))
+			return false, fmt.Errorf("Not Support Compare : (obj1[%v] := %v) != (obj2[%v] := %v)", depthStr, val1, depthStr, val2)
 		}
 
 		if valueCompareErr {
-			if recursiveDepth == 1 {
-				return false, fmt.Errorf("Different Value : (obj1[%v] := %v) != (obj2[%v] := %v)", k, compObj1.MapIndex(k), k, compObj2.MapIndex(k))
-			}
-
-			recursiveDepthKeypList.Lock()
-			depthStr := strings.Join(recursiveDepthKeypList.ar, "][")
-			recursiveDepthKeypList.Unlock()
-			return false, fmt.Errorf("Different Value : (obj1[%v] := %v) != (obj2[%v] := %v)", depthStr, compObj1.MapIndex(k).Interface(), depthStr, compObj2.MapIndex(k))
-
+			return false, fmt.Errorf("Different Value : (obj1[%v] := %v) != (obj2[%v] := %v)", depthStr, val1, depthStr, val2)
 		}
 	}
 
 	return true, nil
 }
 
+// anyEqual returns whether the values are equal, the values of the not comparable type (map, slice) are compared deeply
+func anyEqual(obj1 interface{}, obj2 interface{}) bool {
+	if obj1 != nil && obj2 != nil && (!reflect.TypeOf(obj1).Comparable() || !reflect.TypeOf(obj2).Comparable()) {
+		return reflect.DeepEqual(obj1, obj2)
+	}
+
+	return obj1 == obj2
+}
+
 // AnyCompare is compares two same basic type (without prt) dataset (slice,map,single data).
-// TODO : support interface, struct ...
+// the values of interface{} (decoded JSON, ...) are compared by the dynamic value, nil and nil are equal
+// the error of the maps has the path of the keys. Ex) Missing Key : obj2[a][b] doesn't exist
+// TODO : support struct ...
 // NOTE : Not safe , Not Test Complete. Require more test data based on the complex dataset.
 func (s *StringProc) AnyCompare(obj1 interface{}, obj2 interface{}) (bool, error) {
 	compObjVal1 := reflect.ValueOf(obj1)
@@ -778,6 +1003,11 @@ func (s *StringProc) AnyCompare(obj1 interface{}, obj2 interface{}) (bool, error
 	compObjType1 := reflect.TypeOf(obj1)
 	compObjType2 := reflect.TypeOf(obj2)
 
+	// nil (null of decoded JSON, ...)
+	if !compObjVal1.IsValid() && !compObjVal2.IsValid() {
+		return true, nil
+	}
+
 	if !compObjVal1.IsValid() || !compObjVal2.IsValid() {
 		return false, fmt.Errorf("Invalid, obj1(%v) != obj2(%v)", obj1, obj2)
 	}
@@ -793,8 +1023,8 @@ func (s *StringProc) AnyCompare(obj1 interface{}, obj2 interface{}) (bool, error
 	switch obj1.(type) {
 
 	case string, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64, complex64, complex128, bool:
-		if compObjType1.Comparable() && compObjType2.Comparable() {
-			return obj1 == obj2, nil
+		if obj1 != obj2 {
+			return false, fmt.Errorf("Different Value : obj1(%v) != obj2(%v)", obj1, obj2)
 		}
 
 	default:
@@ -808,7 +1038,7 @@ func (s *StringProc) AnyCompare(obj1 interface{}, obj2 interface{}) (bool, error
 			}
 
 			for i := 0; i < compObjVal1.Len(); i++ {
-				if compObjVal1.Index(i).Interface() != compObjVal2.Index(i).Interface() {
+				if !anyEqual(compObjVal1.Index(i).Interface(), compObjVal2.Index(i).Interface()) {
 					return false, fmt.Errorf("Different Value : (obj1[%d] := %v) != (obj2[%d] := %v)", i, compObjVal1.Index(i).Interface(), i, compObjVal2.Index(i).Interface())
 				}
 			}
@@ -818,7 +1048,6 @@ func (s *StringProc) AnyCompare(obj1 interface{}, obj2 interface{}) (bool, error
 				return false, fmt.Errorf("Different Size : obj1(%d) != obj2(%d)", compObjVal1.Len(), compObjVal2.Len())
 			}
 
-			recursiveDepth = 0
 			retval, err := s.compareMap(compObjVal1, compObjVal2)
 			if !retval {
 				return retval, err
@@ -931,59 +1160,26 @@ func (s *StringProc) DecodeURLEncoded(val string) (string, error) {
 
 // StripTags is remove all tag in string
 func (s *StringProc) StripTags(str string) (string, error) {
-	var retval bool
-	notproccnt := 0
-
-	// looking for html entities code in str
-ENTITY_DECODE:
-
-	if notproccnt > 3 {
-		goto END
-	}
-
-	retval = entityEncodedPattern.MatchString(str)
-	if retval {
-		str = html.UnescapeString(str)
+	// decode html entities and url encoded (including unicode entities) until no more change
+	// NOTE : the last decoded layer is stripped with the error if exceeded the maximum decoding depth, never returns the input unstripped
+	canonicalized, err := s.Canonicalize(str, EncodingHTMLEntity, EncodingUnicodePercent, EncodingFormURL)
+	if canonicalized == nil {
+		return "", err
 	}
 
-	// looking for html entities code in str
-	retval = urlEncodedPattern.MatchString(str)
-	if retval {
-		tmpstr, err := url.QueryUnescape(str)
-		if err == nil {
-			if tmpstr == str {
-				notproccnt++
-			}
-
-			str = tmpstr
-			goto ENTITY_DECODE
-		} else {
-
-			// url.QueryUnescape not support UnicodeEntities
-			tmpstr, err := s.DecodeURLEncoded(str)
-			if err == nil {
-				if tmpstr == str {
-					notproccnt++
-				}
-				str = tmpstr
-				goto ENTITY_DECODE
-			} else {
-				return str, err
-			}
-		}
-	}
-END:
+	str = canonicalized.Str
 
-	// remove tag elements
-	cleanedStr := tagElementsPattern.ReplaceAllString(str, "")
+	// remove tag elements (including contents of script, style and comment)
+	cleanedStr := stripTags(str)
 
 	// remove multiple whitespace
 	cleanedStr = whiteSpacePattern.ReplaceAllString(cleanedStr, "\n")
 
-	return cleanedStr, nil
+	return cleanedStr, err
 }
 
 // ConvertToStr is Convert basic data type to string
+// the other types (pointer, time, error, fmt.Stringer, encoding.TextMarshaler, slice, map) are same as ConvertToStrWithOptions with the default options
 func (s *StringProc) ConvertToStr(obj interface{}) (string, error) {
 	switch obj.(type) {
 	case bool:
@@ -993,11 +1189,16 @@ func (s *StringProc) ConvertToStr(obj interface{}) (string, error) {
 		return "false", nil
 
 	default:
-		return s.numberToString(obj)
+		if retval, err := s.numberToString(obj); err == nil {
+			return retval, nil
+		}
+
+		return s.ConvertToStrWithOptions(obj, nil)
 	}
 }
 
 // ConvertToArByte returns Convert basic data type to []byte
+// the other types are same as ConvertToArByteWithOptions with the default options
 func (s *StringProc) ConvertToArByte(obj interface{}) ([]byte, error) {
 	switch obj.(type) {
 
@@ -1048,11 +1249,11 @@ func (s *StringProc) ConvertToArByte(obj interface{}) ([]byte, error) {
 		return []byte(fmt.Sprintf("%g", obj.(complex128))), nil
 
 	default:
-		return nil, fmt.Errorf("not support type(%s)", reflect.TypeOf(obj).String())
+		return s.ConvertToArByteWithOptions(obj, nil)
 	}
 }
 
-// ReverseStr is Reverse a String , According to value type between ascii or rune
+// ReverseStr is Reverse a String , According to value type between ascii or user-perceived characters (grapheme clusters)
 // TODO : improve performance (use goroutin)
 func (s *StringProc) ReverseStr(str string) string {
 	/*
@@ -1062,8 +1263,9 @@ func (s *StringProc) ReverseStr(str string) string {
 	   BenchmarkReverseReverseUnicode-8   	  100000	     29343 ns/op	    5120 B/op	       2 allocs/op
 	*/
 
-	if len(str) != utf8.RuneCountInString(str) {
-		return s.ReverseUnicode(str)
+	// the non-ascii string and the CRLF are reversed by the user-perceived characters
+	if len(str) != utf8.RuneCountInString(str) || strings.Contains(str, "\r\n") {
+		return s.ReverseGraphemes(str)
 	}
 
 	return s.ReverseNormalStr(str)
@@ -1104,30 +1306,15 @@ func (s *StringProc) ReverseUnicode(str string) string {
 }
 
 // FileMD5Hash is MD5 checksum of the file
+// NOTE : MD5 is not for the integrity check, use FileHash with HashSHA256 or stronger
 func (s *StringProc) FileMD5Hash(filepath string) (string, error) {
-	fd, err := os.Open(filepath)
-	if err != nil {
-		return "", err
-	}
-
-	defer s.closeFd(fd)
-
-	md5Hash := md5.New()
-	if _, err := io.Copy(md5Hash, fd); err != nil {
-		return "", err
-	}
-
-	return hex.EncodeToString(md5Hash.Sum(nil)), nil
+	return s.FileHash(filepath, HashMD5)
 }
 
 // MD5Hash is MD5 checksum of the string
+// NOTE : MD5 is not for the integrity check, use Hash with HashSHA256 or stronger
 func (s *StringProc) MD5Hash(str string) (string, error) {
-	md5Hash := md5.New()
-	if _, err := io.WriteString(md5Hash, str); err != nil {
-		return "", err
-	}
-
-	return hex.EncodeToString(md5Hash.Sum(nil)), nil
+	return s.Hash(str, HashMD5)
 }
 
 func (s *StringProc) closeFd(fd *os.File) {
@@ -1138,7 +1325,7 @@ func (s *StringProc) closeFd(fd *os.File) {
 }
 
 // RegExpNamedGroups is Captures the text matched by regex into the group name
-// NOTE : Not Support the Multiple Groups with The Same Name
+// NOTE : Not Support the Multiple Groups with The Same Name, use RegExpNamedGroupsAll
 func (s *StringProc) RegExpNamedGroups(regex *regexp.Regexp, val string) (map[string]string, error) {
 	ok := false
 	err := errors.New("not all success patterns were matched")
@@ -1164,3 +1351,74 @@ func (s *StringProc) RegExpNamedGroups(regex *regexp.Regexp, val string) (map[st
 
 	return retval, err
 }
+
+// RegExpGroup is a named group of RegExpMatch
+type RegExpGroup struct {
+	Value   string // captured text
+	Start   int    // byte offset of the start, -1 if not participated
+	End     int    // byte offset of the end, -1 if not participated
+	Matched bool   // false if the group did not participate in the match (Value is empty)
+}
+
+// RegExpMatch is a match of RegExpNamedGroupsAll
+type RegExpMatch struct {
+	Value  string                   // matched text
+	Start  int                      // byte offset of the start
+	End    int                      // byte offset of the end
+	Groups map[string][]RegExpGroup // group name => groups, in order of the pattern (several if the same name)
+}
+
+// Get returns the first participated group of the name
+func (m *RegExpMatch) Get(name string) (string, bool) {
+	for _, v := range m.Groups[name] {
+		if v.Matched {
+			return v.Value, true
+		}
+	}
+
+	return "", false
+}
+
+// RegExpNamedGroupsAll is Captures the text matched by regex into the group name, for all matches (up to n, all if n < 0)
+// the groups with the same name are collected in order, the empty capture is told apart from the non-participating group by Matched
+func (s *StringProc) RegExpNamedGroupsAll(regex *regexp.Regexp, val string, n int) ([]RegExpMatch, error) {
+	if regex == nil {
+		return nil, errors.New("Not allow regex parameter : nil")
+	}
+
+	extractSubExpNames := regex.SubexpNames()
+
+	matches := regex.FindAllStringSubmatchIndex(val, n)
+	if len(matches) == 0 {
+		return nil, errors.New("not all success patterns were matched")
+	}
+
+	retval := make([]RegExpMatch, 0, len(matches))
+	for _, loc := range matches {
+		match := RegExpMatch{
+			Value:  val[loc[0]:loc[1]],
+			Start:  loc[0],
+			End:    loc[1],
+			Groups: map[string][]RegExpGroup{},
+		}
+
+		for no := 1; no < len(extractSubExpNames); no++ {
+			name := extractSubExpNames[no]
+			if name == "" {
+				continue
+			}
+
+			group := RegExpGroup{Start: loc[no*2], End: loc[no*2+1]}
+			if group.Start >= 0 {
+				group.Value = val[group.Start:group.End]
+				group.Matched = true
+			}
+
+			match.Groups[name] = append(match.Groups[name], group)
+		}
+
+		retval = append(retval, match)
+	}
+
+	return retval, nil
+}
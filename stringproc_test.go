@@ -1,6 +1,7 @@
 package strutils_test
 
 import (
+	"encoding/json"
 	"io/ioutil"
 	"math"
 	"net/url"
@@ -46,6 +47,10 @@ func Test_strutils_StripSlashes(t *testing.T) {
 		"abcdefgz":     "abcdefgz",
 		`a\\bcdefgz`:   `a\bcdefgz`,
 		`a\\\\bcdefgz`: `a\\bcdefgz`,
+		`a\b`:          `ab`,
+		`\n\'\"`:       `n'"`,
+		`a\0b`:         "a\x00b",
+		`abc\`:         `abc`,
 	}
 
 	// check : common
@@ -57,6 +62,74 @@ func Test_strutils_StripSlashes(t *testing.T) {
 	}
 }
 
+func Test_strutils_StripSlashesWithMode(t *testing.T) {
+	t.Parallel()
+
+	dataset := map[string]string{
+		`abc`:              "abc",
+		`a\\b\'c\"d\?`:     "a\\b'c\"d?",
+		`\a\b\f\n\r\t\v`:   "\a\b\f\n\r\t\v",
+		`\101\60\0\1234`:   "A0\x00S4",
+		`\x41\x4a\xff`:     "AJ\xff",
+		`\u00e9\U0001F600`: "é😀",
+		`대한\n민국`:           "대한\n민국",
+	}
+
+	// check : common
+	for _, mode := range []int{strutils.StripSlashesEscape, strutils.StripSlashesStrict} {
+		for k, v := range dataset {
+			retval, err := strproc.StripSlashesWithMode(k, mode)
+			assert.AssertNil(t, err, "Error : %v", err)
+			assert.AssertEquals(t, retval, v, "Return Value mismatch (%v).\nExpected: %q\nActual: %q", k, v, retval)
+		}
+	}
+
+	// check : unknown or truncated escapes
+	dataset = map[string]string{
+		`a\qb`:   "aqb",
+		`\xZZ`:   "xZZ",
+		`\x123`:  "x123",
+		`\u12`:   "u12",
+		`\ud800`: "ud800",
+		`\777`:   "777",
+		`abc\`:   "abc",
+		`\é`:     "é",
+	}
+
+	for k, v := range dataset {
+		retval, err := strproc.StripSlashesWithMode(k, strutils.StripSlashesEscape)
+		assert.AssertNil(t, err, "Error : %v", err)
+		assert.AssertEquals(t, retval, v, "Return Value mismatch (%v).\nExpected: %q\nActual: %q", k, v, retval)
+
+		_, err = strproc.StripSlashesWithMode(k, strutils.StripSlashesStrict)
+		assert.AssertNotNil(t, err, "Couldn't check the `strict mode` (%v)\nError : %v", k, err)
+	}
+
+	// check : the position of error
+	_, err := strproc.StripSlashesWithMode(`abc\n\q`, strutils.StripSlashesStrict)
+	assert.AssertNotNil(t, err, "Couldn't check the `strict mode`\nError : %v", err)
+	assert.AssertEquals(t, err.Error(), `Not allow escape sequence "\\q" at 5`, "Return Value mismatch.\nExpected: %v\nActual: %v", `Not allow escape sequence "\\q" at 5`, err)
+
+	// check : php mode
+	retval, err := strproc.StripSlashesWithMode(`a\nb\\`, strutils.StripSlashesPHP)
+	assert.AssertNil(t, err, "Error : %v", err)
+	assert.AssertEquals(t, retval, `anb\`, "Return Value mismatch.\nExpected: %v\nActual: %v", `anb\`, retval)
+
+	// check : symmetric with EscapeString(EscapeC)
+	for _, v := range []string{"abc", "a\"b\\c\n\t\x01\x7f??=", "대한\r\n민국"} {
+		escaped, err := strproc.EscapeString(v, strutils.EscapeC)
+		assert.AssertNil(t, err, "Error : %v", err)
+
+		retval, err := strproc.StripSlashesWithMode(escaped[1:len(escaped)-1], strutils.StripSlashesStrict)
+		assert.AssertNil(t, err, "Error : %v", err)
+		assert.AssertEquals(t, retval, v, "Return Value mismatch (%v).\nExpected: %q\nActual: %q", escaped, v, retval)
+	}
+
+	// check : not allow mode
+	_, err = strproc.StripSlashesWithMode("abc", 3)
+	assert.AssertNotNil(t, err, "Couldn't check the `not allow mode`\nError : %v", err)
+}
+
 func Test_strutils_Nl2Br(t *testing.T) {
 	t.Parallel()
 
@@ -114,6 +187,17 @@ func Test_strutils_Br2Nl(t *testing.T) {
 
 		"world peace!!<a href='http://www.president.go.kr/'><br />대한민국만세</a><br>":   "world peace!!<a href='http://www.president.go.kr/'>\n대한민국만세</a>\n",
 		"world peace!!<a href='http://www.president.go.kr/'><br />abcde</a><br>fgh": "world peace!!<a href='http://www.president.go.kr/'>\nabcde</a>\nfgh",
+
+		"abc<br  />def":          "abc\ndef",
+		"abc<br class=\"x\">def": "abc\ndef",
+		"abc<br data-x='>'>def":  "abc\ndef",
+		"abc<BR\t/>def":          "abc\ndef",
+		"abc</br>def":            "abc\ndef",
+		"abc<bR\n>def":           "abc\ndef",
+		"abc<brx>def<b>":         "abc<brx>def<b>",
+		"abc<br class='x":        "abc<br class='x",
+		"a < br> b":              "a < br> b",
+		"a </ br> b":             "a </ br> b",
 	}
 
 	// check : common
@@ -124,6 +208,78 @@ func Test_strutils_Br2Nl(t *testing.T) {
 	}
 }
 
+func Test_strutils_Nl2BrWithOptions(t *testing.T) {
+	t.Parallel()
+
+	dataset := []struct {
+		opts *strutils.Nl2BrOptions
+		str  string
+		want string
+	}{
+		{nil, "a\nb\r\nc\n\rd", "a<br />b<br />c<br />d"},
+		{nil, "a\n\nb", "a<br /><br />b"},
+		{&strutils.Nl2BrOptions{Tag: strutils.BrTagHTML}, "a\nb", "a<br>b"},
+		{&strutils.Nl2BrOptions{Tag: strutils.BrTagCompact}, "a\nb", "a<br/>b"},
+		{&strutils.Nl2BrOptions{KeepNewline: true}, "a\nb\r\nc", "a<br />\nb<br />\r\nc"},
+		{&strutils.Nl2BrOptions{EscapeHTML: true}, "<b>\"a\" & 'b'</b>\n", "&lt;b&gt;&quot;a&quot; &amp; &#39;b&#39;&lt;/b&gt;<br />"},
+		{&strutils.Nl2BrOptions{Paragraph: true}, "a\nb\n\n\nc\r\n \r\nd\n", "<p>a<br />b</p><p>c</p><p>d</p>"},
+		{&strutils.Nl2BrOptions{Paragraph: true, KeepNewline: true, Tag: strutils.BrTagHTML}, "a\nb\n\nc", "<p>a<br>\nb</p>\n\n<p>c</p>"},
+		{&strutils.Nl2BrOptions{Paragraph: true}, "\n\n", ""},
+	}
+
+	// check : common
+	for _, v := range dataset {
+		retval, err := strproc.Nl2BrWithOptions(v.str, v.opts)
+		assert.AssertNil(t, err, "Error : %v", err)
+		assert.AssertEquals(t, retval, v.want, "Return Value mismatch (%q).\nExpected: %q\nActual: %q", v.str, v.want, retval)
+	}
+
+	// check : not allow tag
+	_, err := strproc.Nl2BrWithOptions("a", &strutils.Nl2BrOptions{Tag: 3})
+	assert.AssertNotNil(t, err, "Couldn't check the `not allow Tag option`\nError : %v", err)
+}
+
+func Test_strutils_Br2NlWithOptions(t *testing.T) {
+	t.Parallel()
+
+	dataset := []struct {
+		opts *strutils.Nl2BrOptions
+		str  string
+		want string
+	}{
+		{nil, "a<br>\nb<BR\t/>c", "a\n\nb\nc"},
+		{&strutils.Nl2BrOptions{KeepNewline: true}, "a<br>\nb<br />\r\nc<br>d", "a\nb\nc\nd"},
+		{&strutils.Nl2BrOptions{EscapeHTML: true}, "&lt;b&gt;<br>&amp;", "<b>\n&"},
+		{&strutils.Nl2BrOptions{Paragraph: true}, "<p>a<br>b</p>\n <P class=x>c</p>d", "a\nb\n\nc\n\nd"},
+		{&strutils.Nl2BrOptions{}, "<p>a</p><p>b</p>", "<p>a</p><p>b</p>"},
+		{nil, "1 < br>2</ br>3", "1 < br>2</ br>3"},
+		{&strutils.Nl2BrOptions{Paragraph: true}, "a< p>b", "a< p>b"},
+	}
+
+	// check : common
+	for _, v := range dataset {
+		retval := strproc.Br2NlWithOptions(v.str, v.opts)
+		assert.AssertEquals(t, retval, v.want, "Return Value mismatch (%q).\nExpected: %q\nActual: %q", v.str, v.want, retval)
+	}
+
+	// check : round trip
+	opts := []*strutils.Nl2BrOptions{
+		nil,
+		{Tag: strutils.BrTagHTML, KeepNewline: true},
+		{EscapeHTML: true, Paragraph: true},
+		{Tag: strutils.BrTagCompact, KeepNewline: true, EscapeHTML: true, Paragraph: true},
+	}
+
+	str := "<b>a</b> & b\nc\n\nd"
+	for _, v := range opts {
+		html, err := strproc.Nl2BrWithOptions(str, v)
+		assert.AssertNil(t, err, "Error : %v", err)
+
+		retval := strproc.Br2NlWithOptions(html, v)
+		assert.AssertEquals(t, retval, str, "Return Value mismatch (%q).\nExpected: %q\nActual: %q", html, str, retval)
+	}
+}
+
 type wordwrapTestVal struct {
 	str      string
 	wd       int
@@ -1039,6 +1195,75 @@ func Test_strutils_AnyCompare(t *testing.T) {
 	testMapStruct2 := map[string]testStruct1{"a": {1, 2}}
 	retval, err = strproc.AnyCompare(testMapStruct1, testMapStruct2)
 	assert.AssertFalse(t, retval, "Couldn't make an accurate comparison : %v", err)
+
+	// check : interface{} (decoded JSON)
+	testJSONMap1 := map[string]interface{}{"a": 1.0, "b": []interface{}{1.0, map[string]interface{}{"x": nil}}, "c": map[string]interface{}{"d": "e"}}
+	testJSONMap2 := map[string]interface{}{"a": 1.0, "b": []interface{}{1.0, map[string]interface{}{"x": nil}}, "c": map[string]interface{}{"d": "e"}}
+	retval, err = strproc.AnyCompare(testJSONMap1, testJSONMap2)
+	assert.AssertTrue(t, retval, "Couldn't make an accurate comparison : %v", err)
+
+	testJSONMapDiff := map[string]interface{}{"a": 1.0, "b": []interface{}{1.0, map[string]interface{}{"x": 2.0}}, "c": map[string]interface{}{"d": "e"}}
+	retval, err = strproc.AnyCompare(testJSONMap1, testJSONMapDiff)
+	assert.AssertFalse(t, retval, "Couldn't make an accurate comparison : %v", err)
+	assert.AssertEquals(t, err.Error(), "Different Value : (obj1[b] := [1 map[x:<nil>]]) != (obj2[b] := [1 map[x:2]])", "Return Value mismatch : %v", err)
+
+	testJSONMapSize := map[string]interface{}{"a": 1.0, "b": []interface{}{1.0, map[string]interface{}{"x": nil}}, "c": map[string]interface{}{"d": "e", "f": "g"}}
+	retval, err = strproc.AnyCompare(testJSONMap1, testJSONMapSize)
+	assert.AssertFalse(t, retval, "Couldn't make an accurate comparison : %v", err)
+
+	testJSONMapNil := map[string]interface{}{"a": nil, "b": nil, "c": nil}
+	retval, err = strproc.AnyCompare(testJSONMap1, testJSONMapNil)
+	assert.AssertFalse(t, retval, "Couldn't make an accurate comparison : %v", err)
+
+	testJSONSlice1 := []interface{}{1.0, map[string]interface{}{"a": 2.0}}
+	testJSONSlice2 := []interface{}{1.0, map[string]interface{}{"a": 3.0}}
+	retval, err = strproc.AnyCompare(testJSONSlice1, testJSONSlice1)
+	assert.AssertTrue(t, retval, "Couldn't make an accurate comparison : %v", err)
+
+	retval, err = strproc.AnyCompare(testJSONSlice1, testJSONSlice2)
+	assert.AssertFalse(t, retval, "Couldn't make an accurate comparison : %v", err)
+}
+
+func Test_strutils_AnyCompare_JSON(t *testing.T) {
+	t.Parallel()
+
+	dataset := []struct {
+		json1 string
+		json2 string
+		equal bool
+		err   string
+	}{
+		{`null`, `null`, true, ""},
+		{`1`, `1`, true, ""},
+		{`1`, `2`, false, "Different Value : obj1(1) != obj2(2)"},
+		{`"a"`, `"b"`, false, "Different Value : obj1(a) != obj2(b)"},
+		{`true`, `false`, false, "Different Value : obj1(true) != obj2(false)"},
+		{`null`, `{}`, false, "Invalid, obj1(<nil>) != obj2(map[])"},
+		{`{"a":null}`, `{"a":null}`, true, ""},
+		{`{"a":null}`, `{"b":null}`, false, "Missing Key : obj2[a] doesn't exist"},
+		{`{"a":1}`, `{"b":1}`, false, "Missing Key : obj2[a] doesn't exist"},
+		{`{"a":{"b":{"c":1}}}`, `{"a":{"b":{"d":1}}}`, false, "Missing Key : obj2[a][b][c] doesn't exist"},
+		{`{"a":{"b":{"c":1}}}`, `{"a":{"b":{"c":2}}}`, false, "Different Value : (obj1[a][b][c] := 1) != (obj2[a][b][c] := 2)"},
+		{`{"a":{"b":{"c":1}}}`, `{"a":{"b":{"c":"1"}}}`, false, "Different Type : (obj1[a][b][c] type is `float64`) != (obj2[a][b][c] type is `string`)"},
+		{`{"a":{"b":{"c":1}}}`, `{"a":{"b":{"c":1,"d":2}}}`, false, "Different Size : (obj1[a][b] := 1) != (obj2[a][b] := 2)"},
+		{`{"a":{"b":[1,{"c":2}]}}`, `{"a":{"b":[1,{"c":3}]}}`, false, "Different Value : (obj1[a][b] := [1 map[c:2]]) != (obj2[a][b] := [1 map[c:3]])"},
+	}
+
+	// check : common
+	for _, v := range dataset {
+		var obj1, obj2 interface{}
+		assert.AssertNil(t, json.Unmarshal([]byte(v.json1), &obj1), "Failure : Couldn't decode %v", v.json1)
+		assert.AssertNil(t, json.Unmarshal([]byte(v.json2), &obj2), "Failure : Couldn't decode %v", v.json2)
+
+		retval, err := strproc.AnyCompare(obj1, obj2)
+		assert.AssertEquals(t, retval, v.equal, "Return Value mismatch (%v, %v).\nExpected: %v\nActual: %v (%v)", v.json1, v.json2, v.equal, retval, err)
+
+		errstr := ""
+		if err != nil {
+			errstr = err.Error()
+		}
+		assert.AssertEquals(t, errstr, v.err, "Return Value mismatch (%v, %v).\nExpected: %v\nActual: %v", v.json1, v.json2, v.err, errstr)
+	}
 }
 
 func Test_strutils_DecodeUnicodeEntities(t *testing.T) {
@@ -1299,6 +1524,13 @@ README.md haven’t contain all the examples. Please refer to the the XXXtest.go
 	retval, err = strproc.StripTags(str_fb_urlencoded)
 	assert.AssertNil(t, err, "Error : %v", err)
 	assert.AssertEquals(t, retval, str_fb_urlencoded_ok, "Return Value mismatch.\nExpected: %v\nActual: %v", retval, str_fb_urlencoded_ok)
+
+	// check : exceeded the maximum decoding depth, the last decoded layer is stripped
+	str_nested_urlencoded := "<b>x</b>%25" + strings.Repeat("25", 20) + "3Cscript%3E"
+	retval, err = strproc.StripTags(str_nested_urlencoded)
+	assert.AssertNotNil(t, err, "Failure : Couldn't check the maximum decoding depth")
+	assert.AssertFalse(t, strings.Contains(retval, "<"), "Return Value mismatch.\nExpected: %v\nActual: %v", "without the tag", retval)
+	assert.AssertTrue(t, strings.HasPrefix(retval, "x%"), "Return Value mismatch.\nExpected: %v\nActual: %v", "x%...", retval)
 }
 
 func Test_strutils_ConvertToStr(t *testing.T) {
@@ -1406,10 +1638,14 @@ func Test_strutils_ReverseStr(t *testing.T) {
 	t.Parallel()
 
 	dataset := map[string]string{
-		"0123456789": "9876543210",
-		"가나다라마바사":    "사바마라다나가",
-		"あいうえお":      "おえういあ",
-		"天地玄黃宇宙洪荒":   "荒洪宙宇黃玄地天",
+		"0123456789":  "9876543210",
+		"가나다라마바사":     "사바마라다나가",
+		"あいうえお":       "おえういあ",
+		"天地玄黃宇宙洪荒":    "荒洪宙宇黃玄地天",
+		"a\r\nb":      "b\r\na",
+		"cafe\u0301!": "!e\u0301fac",
+		"\U0001F1F0\U0001F1F7\U0001F1EF\U0001F1F5":     "\U0001F1EF\U0001F1F5\U0001F1F0\U0001F1F7",
+		"a\U0001F468\u200D\U0001F469\u200D\U0001F467b": "b\U0001F468\u200D\U0001F469\u200D\U0001F467a",
 	}
 
 	// check : common
@@ -1505,3 +1741,74 @@ func Test_strutils_RegExpNamedGroups(t *testing.T) {
 	_, ok = verdic["minor"]
 	assert.AssertTrue(t, ok, "Not Exists Minor ver. in Return Value")
 }
+
+func Test_strutils_RegExpNamedGroupsAll(t *testing.T) {
+	t.Parallel()
+
+	// check : common
+	regex := regexp.MustCompile(`(?P<key>[a-z]+)=(?P<val>[0-9]*)`)
+	retval, err := strproc.RegExpNamedGroupsAll(regex, "a=1, bc=, d=23", -1)
+	assert.AssertNil(t, err, "Error : %v", err)
+
+	expected := []strutils.RegExpMatch{
+		{Value: "a=1", Start: 0, End: 3, Groups: map[string][]strutils.RegExpGroup{
+			"key": {{Value: "a", Start: 0, End: 1, Matched: true}},
+			"val": {{Value: "1", Start: 2, End: 3, Matched: true}},
+		}},
+		{Value: "bc=", Start: 5, End: 8, Groups: map[string][]strutils.RegExpGroup{
+			"key": {{Value: "bc", Start: 5, End: 7, Matched: true}},
+			"val": {{Value: "", Start: 8, End: 8, Matched: true}},
+		}},
+		{Value: "d=23", Start: 10, End: 14, Groups: map[string][]strutils.RegExpGroup{
+			"key": {{Value: "d", Start: 10, End: 11, Matched: true}},
+			"val": {{Value: "23", Start: 12, End: 14, Matched: true}},
+		}},
+	}
+	assert.AssertEquals(t, len(retval), len(expected), "Return Value mismatch.\nExpected: %v\nActual: %v", len(expected), len(retval))
+	for no, v := range expected {
+		assert.AssertEquals(t, retval[no].Value, v.Value, "Return Value mismatch.\nExpected: %v\nActual: %v", v.Value, retval[no].Value)
+		assert.AssertEquals(t, retval[no].Start, v.Start, "Return Value mismatch.\nExpected: %v\nActual: %v", v.Start, retval[no].Start)
+		assert.AssertEquals(t, retval[no].End, v.End, "Return Value mismatch.\nExpected: %v\nActual: %v", v.End, retval[no].End)
+		for name, groups := range v.Groups {
+			assert.AssertEquals(t, retval[no].Groups[name], groups, "Return Value mismatch (%v).\nExpected: %v\nActual: %v", name, groups, retval[no].Groups[name])
+		}
+	}
+
+	// check : n
+	retval, err = strproc.RegExpNamedGroupsAll(regex, "a=1, bc=, d=23", 2)
+	assert.AssertNil(t, err, "Error : %v", err)
+	assert.AssertEquals(t, len(retval), 2, "Return Value mismatch.\nExpected: %v\nActual: %v", 2, len(retval))
+
+	// check : same name, not participated
+	regex = regexp.MustCompile(`(?P<date>(?P<y>\d{4})-(?P<m>\d\d))(?P<none>x)?|(?P<date>(?P<m>\d\d)/(?P<y>\d{4}))`)
+	retval, err = strproc.RegExpNamedGroupsAll(regex, "2020-01 12/1999", -1)
+	assert.AssertNil(t, err, "Error : %v", err)
+
+	dataset := map[int]map[string]string{
+		0: {"date": "2020-01", "y": "2020", "m": "01"},
+		1: {"date": "12/1999", "y": "1999", "m": "12"},
+	}
+
+	for k, v := range dataset {
+		for name, val := range v {
+			ret, ok := retval[k].Get(name)
+			assert.AssertTrue(t, ok, "Not Exists %v in Return Value", name)
+			assert.AssertEquals(t, ret, val, "Return Value mismatch (%v).\nExpected: %v\nActual: %v", name, val, ret)
+			assert.AssertEquals(t, len(retval[k].Groups[name]), 2, "Return Value mismatch (%v).\nExpected: %v\nActual: %v", name, 2, len(retval[k].Groups[name]))
+		}
+
+		_, ok := retval[k].Get("none")
+		assert.AssertFalse(t, ok, "Couldn't check the `not participated`")
+		assert.AssertEquals(t, retval[k].Groups["none"], []strutils.RegExpGroup{{Start: -1, End: -1}}, "Return Value mismatch.\nExpected: %v\nActual: %v", -1, retval[k].Groups["none"])
+	}
+
+	assert.AssertFalse(t, retval[0].Groups["date"][1].Matched, "Couldn't check the `not participated`")
+	assert.AssertEquals(t, retval[1].Groups["y"][1].Start, 11, "Return Value mismatch.\nExpected: %v\nActual: %v", 11, retval[1].Groups["y"][1].Start)
+
+	// check : not matched, nil
+	_, err = strproc.RegExpNamedGroupsAll(regexp.MustCompile(`(?P<a>z)`), "abc", -1)
+	assert.AssertNotNil(t, err, "Couldn't check the `not matched`\nError : %v", err)
+
+	_, err = strproc.RegExpNamedGroupsAll(nil, "abc", -1)
+	assert.AssertNotNil(t, err, "Couldn't check the `nil regex`\nError : %v", err)
+}
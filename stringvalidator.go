@@ -2,7 +2,6 @@ package strutils
 
 import (
 	"errors"
-	"html"
 	"net"
 	"net/url"
 	"regexp"
@@ -240,7 +239,7 @@ var controlcharPattern = regexp.MustCompile(`(?im)([\x00-\x08\x0B\x0C\x0E-\x1F\x
 
 // IsPureTextNormal is Validates whether the value is a pure text, Validation use Regular Expressions
 func (s *StringValidator) IsPureTextNormal(str string) (bool, error) {
-	decodedStr := html.UnescapeString(str)
+	decodedStr := decodeHTMLEntities(str)
 
 	matchedUrlencoded := urlencodedPattern.MatchString(decodedStr)
 	if matchedUrlencoded {
@@ -0,0 +1,69 @@
+package strutils
+
+import (
+	"fmt"
+	"html/template"
+)
+
+// FuncMap returns the functions of StringProc and StringValidator for text/template and html/template (Funcs)
+// the parameters come first and the string is the last, for the pipeline. Ex) {{ .Size | humanByteSize 2 "upper_double" }}
+// the error of the StringProc method stops the execution of the template, the validators return false if invalid
+func FuncMap() map[string]interface{} {
+	s := &StringProc{}
+	v := &StringValidator{}
+
+	return map[string]interface{}{
+		"numberFmt": s.NumberFmt,
+		"humanByteSize": func(decimals int, unit string, obj interface{}) (string, error) {
+			u, ok := pipelineByteUnits[unit]
+			if !ok {
+				return "", fmt.Errorf("Not allow unit parameter : %v", unit)
+			}
+
+			return s.HumanByteSize(obj, decimals, uint8(u))
+		},
+		"wordWrapAround": func(wd int, breakstr string, str string) (string, error) {
+			return s.WordWrapAround(str, wd, breakstr)
+		},
+		"paddingLeft": func(fill string, mx int, str string) string {
+			return s.PaddingLeft(str, fill, mx)
+		},
+		"paddingRight": func(fill string, mx int, str string) string {
+			return s.PaddingRight(str, fill, mx)
+		},
+		"paddingBoth": func(fill string, mx int, str string) string {
+			return s.PaddingBoth(str, fill, mx)
+		},
+		"lowerCaseFirstWords": s.LowerCaseFirstWords,
+		"upperCaseFirstWords": s.UpperCaseFirstWords,
+		"swapCaseFirstWords":  s.SwapCaseFirstWords,
+
+		// the string is escaped before the <br />, safe to html/template
+		"nl2br": func(str string) template.HTML {
+			return template.HTML(s.Nl2Br(template.HTMLEscapeString(str)))
+		},
+		"stripTags": s.StripTags,
+		"truncate": func(mx int, tail string, str string) (string, error) {
+			return s.GraphemeTruncate(str, mx, tail)
+		},
+
+		"isValidEmail":                    v.IsValidEmail,
+		"isValidDomain":                   v.IsValidDomain,
+		"isValidURL":                      v.IsValidURL,
+		"isValidMACAddr":                  v.IsValidMACAddr,
+		"isValidFilePath":                 v.IsValidFilePath,
+		"isValidFilePathWithRelativePath": v.IsValidFilePathWithRelativePath,
+		"isValidIPAddr": func(str string) bool {
+			retval, _ := v.IsValidIPAddr(str, IPAny)
+			return retval
+		},
+		"isPureTextStrict": func(str string) bool {
+			retval, _ := v.IsPureTextStrict(str)
+			return retval
+		},
+		"isPureTextNormal": func(str string) bool {
+			retval, _ := v.IsPureTextNormal(str)
+			return retval
+		},
+	}
+}
@@ -0,0 +1,81 @@
+package strutils_test
+
+import (
+	"bytes"
+	htmltemplate "html/template"
+	"testing"
+	"text/template"
+
+	strutils "github.com/torden/go-strutil"
+)
+
+func Test_strutils_FuncMap(t *testing.T) {
+	t.Parallel()
+
+	data := map[string]interface{}{
+		"Size":  2048,
+		"Count": 1234567,
+		"Name":  "just a string",
+		"Body":  "<b>Hello</b>\nWorld & Go",
+		"Email": "abc@example.com",
+		"IP":    "127.0.0.1",
+	}
+
+	dataset := map[string]string{
+		`{{ .Count | numberFmt }}`:                                  "1,234,567",
+		`{{ .Size | humanByteSize 1 "upper_double" }}`:              "2.0KB",
+		`{{ .Name | wordWrapAround 6 "|" }}`:                        "just a|string",
+		`{{ .Name | paddingLeft "*" 15 }}`:                          "**just a string",
+		`{{ .Name | paddingRight "*" 15 }}`:                         "just a string**",
+		`{{ .Name | paddingBoth "*" 15 }}`:                          "*just a string*",
+		`{{ .Name | upperCaseFirstWords }}`:                         "Just A String",
+		`{{ "Just A String" | lowerCaseFirstWords }}`:               "just a string",
+		`{{ "Just a String" | swapCaseFirstWords }}`:                "just A string",
+		`{{ .Body | stripTags }}`:                                   "Hello\nWorld & Go",
+		`{{ .Name | truncate 6 "~" }}`:                              "just ~",
+		`{{ isValidEmail .Email }} {{ isValidEmail .Name }}`:        "true false",
+		`{{ isValidIPAddr .IP }} {{ isValidIPAddr .Name }}`:         "true false",
+		`{{ isPureTextNormal .Name }} {{ isPureTextStrict .Body }}`: "true false",
+		`{{ isValidDomain "example.com" }} {{ isValidURL "x" }}`:    "true false",
+	}
+
+	// check : text/template
+	for tmpl, v := range dataset {
+		var buf bytes.Buffer
+
+		err := template.Must(template.New("").Funcs(strutils.FuncMap()).Parse(tmpl)).Execute(&buf, data)
+		assert.AssertNil(t, err, "Error : %v (%v)", err, tmpl)
+		assert.AssertEquals(t, buf.String(), v, "Return Value mismatch.\nExpected: %q\nActual: %q (%v)", v, buf.String(), tmpl)
+	}
+
+	// check : html/template, nl2br is escaped
+	htmlset := map[string]string{
+		`<p>{{ .Body | nl2br }}</p>`:         "<p>&lt;b&gt;Hello&lt;/b&gt;<br />World &amp; Go</p>",
+		`<p>{{ .Body | stripTags }}</p>`:     "<p>Hello\nWorld &amp; Go</p>",
+		`<p>{{ .Count | numberFmt }}</p>`:    "<p>1,234,567</p>",
+		`<p>{{ "a<b" | paddingLeft "-" 5 }}`: "<p>--a&lt;b",
+	}
+
+	for tmpl, v := range htmlset {
+		var buf bytes.Buffer
+
+		err := htmltemplate.Must(htmltemplate.New("").Funcs(strutils.FuncMap()).Parse(tmpl)).Execute(&buf, data)
+		assert.AssertNil(t, err, "Error : %v (%v)", err, tmpl)
+		assert.AssertEquals(t, buf.String(), v, "Return Value mismatch.\nExpected: %q\nActual: %q (%v)", v, buf.String(), tmpl)
+	}
+
+	// check : the error stops the execution
+	errset := []string{
+		`{{ .Name | numberFmt }}`,
+		`{{ .Size | humanByteSize 1 "huge" }}`,
+		`{{ .Name | wordWrapAround 0 "|" }}`,
+		`{{ .Name | truncate -1 "~" }}`,
+	}
+
+	for _, tmpl := range errset {
+		var buf bytes.Buffer
+
+		err := template.Must(template.New("").Funcs(strutils.FuncMap()).Parse(tmpl)).Execute(&buf, data)
+		assert.AssertNotNil(t, err, "Failure : Couldn't check the error of %v", tmpl)
+	}
+}
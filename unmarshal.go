@@ -0,0 +1,186 @@
+package strutils
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	durationType = reflect.TypeOf(time.Duration(0))
+)
+
+// RegExpFieldError is the conversion error of a field of RegExpUnmarshal
+type RegExpFieldError struct {
+	Field string // struct field name
+	Group string // group name of the regex
+	Value string // captured text
+	Err   error
+}
+
+func (e *RegExpFieldError) Error() string {
+	return fmt.Sprintf("field %s (group %s) : couldn't convert %q : %v", e.Field, e.Group, e.Value, e.Err)
+}
+
+// RegExpUnmarshalError is the all field errors of RegExpUnmarshal
+type RegExpUnmarshalError []*RegExpFieldError
+
+func (e RegExpUnmarshalError) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, v := range e {
+		msgs = append(msgs, v.Error())
+	}
+
+	return strings.Join(msgs, ", ")
+}
+
+// setField is convert the str and set to the field
+func (s *StringProc) setField(field reflect.Value, str string, layout string) error {
+	if field.Kind() == reflect.Ptr {
+		if field.IsNil() {
+			field.Set(reflect.New(field.Type().Elem()))
+		}
+
+		return s.setField(field.Elem(), str, layout)
+	}
+
+	switch field.Type() {
+	case timeType:
+		if layout == "" {
+			layout = time.RFC3339
+		}
+
+		t, err := time.Parse(layout, str)
+		if err != nil {
+			return err
+		}
+
+		field.Set(reflect.ValueOf(t))
+		return nil
+
+	case durationType:
+		d, err := time.ParseDuration(str)
+		if err != nil {
+			return err
+		}
+
+		field.SetInt(int64(d))
+		return nil
+	}
+
+	if field.CanAddr() {
+		if u, ok := field.Addr().Interface().(encoding.TextUnmarshaler); ok {
+			return u.UnmarshalText([]byte(str))
+		}
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(str)
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(str, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		field.SetInt(i)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(str, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		field.SetUint(u)
+
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(str, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		field.SetFloat(f)
+
+	case reflect.Bool:
+		b, err := strconv.ParseBool(str)
+		if err != nil {
+			return err
+		}
+
+		field.SetBool(b)
+
+	default:
+		return fmt.Errorf("Not support type : %v", field.Type())
+	}
+
+	return nil
+}
+
+// RegExpUnmarshal is Captures the text matched by regex into the struct fields by the tag (`re:"name"`), first match only
+// supports string, int, uint, float, bool, time.Time (`layout:"2006-01-02"`, RFC3339 if not given), time.Duration, encoding.TextUnmarshaler and the pointers of them
+// the non-participating groups and the empty captures of the non-string fields are skipped (zero value)
+// the conversion errors are returned as RegExpUnmarshalError (per field) after the other fields are set
+func (s *StringProc) RegExpUnmarshal(regex *regexp.Regexp, val string, dst interface{}) error {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("Not allow dst parameter, not a pointer of struct : %T", dst)
+	}
+
+	matches, err := s.RegExpNamedGroupsAll(regex, val, 1)
+	if err != nil {
+		return err
+	}
+
+	match := matches[0]
+	rv = rv.Elem()
+	rt := rv.Type()
+
+	var errs RegExpUnmarshalError
+	for i := 0; i < rt.NumField(); i++ {
+		sf := rt.Field(i)
+		name, ok := sf.Tag.Lookup("re")
+		if !ok || name == "-" {
+			continue
+		}
+
+		if len(sf.PkgPath) > 0 { // unexported
+			return fmt.Errorf("Not allow re tag of the unexported field : %v", sf.Name)
+		}
+
+		if _, ok := match.Groups[name]; !ok {
+			return fmt.Errorf("Not exists group of the field %v : %v", sf.Name, name)
+		}
+
+		ft := sf.Type
+		for ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+
+		str, ok := match.Get(name)
+		if !ok || (str == "" && ft.Kind() != reflect.String) {
+			continue
+		}
+
+		if err := s.setField(rv.Field(i), str, sf.Tag.Get("layout")); err != nil {
+			var numErr *strconv.NumError
+			if errors.As(err, &numErr) {
+				err = numErr.Err
+			}
+
+			errs = append(errs, &RegExpFieldError{Field: sf.Name, Group: name, Value: str, Err: err})
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
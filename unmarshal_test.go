@@ -0,0 +1,99 @@
+package strutils_test
+
+import (
+	"errors"
+	"net"
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+
+	strutils "github.com/torden/go-strutil"
+)
+
+type accessLog struct {
+	IP       net.IP        `re:"ip"`
+	User     *string       `re:"user"`
+	Time     time.Time     `re:"time" layout:"02/Jan/2006:15:04:05 -0700"`
+	Method   string        `re:"method"`
+	Status   int           `re:"status"`
+	Size     uint64        `re:"size"`
+	Ratio    float64       `re:"ratio"`
+	Cached   bool          `re:"cached"`
+	Elapsed  time.Duration `re:"elapsed"`
+	Referer  *string       `re:"referer"`
+	Ignored  string        `re:"-"`
+	Untagged string
+}
+
+var accessLogPattern = regexp.MustCompile(`^(?P<ip>\S+) (?P<user>\S+) \[(?P<time>[^\]]+)\] "(?P<method>[A-Z]+)[^"]*" (?P<status>\d+|-) (?P<size>\d+|-) (?P<ratio>\S*) (?P<cached>\S+) (?P<elapsed>\S+)(?: "(?P<referer>[^"]*)")?$`)
+
+func Test_strutils_RegExpUnmarshal(t *testing.T) {
+	t.Parallel()
+
+	// check : common
+	var retval accessLog
+	err := strproc.RegExpUnmarshal(accessLogPattern, `127.0.0.1 frank [10/Oct/2000:13:55:36 -0700] "GET /a.gif HTTP/1.0" 200 2326 0.5 true 1.5ms`, &retval)
+	assert.AssertNil(t, err, "Error : %v", err)
+
+	assert.AssertEquals(t, retval.IP.String(), "127.0.0.1", "Return Value mismatch.\nExpected: %v\nActual: %v", "127.0.0.1", retval.IP)
+	assert.AssertNotNil(t, retval.User, "Return Value mismatch.\nExpected: %v\nActual: %v", "frank", retval.User)
+	assert.AssertEquals(t, *retval.User, "frank", "Return Value mismatch.\nExpected: %v\nActual: %v", "frank", *retval.User)
+	assert.AssertEquals(t, retval.Time.Unix(), int64(971211336), "Return Value mismatch.\nExpected: %v\nActual: %v", 971211336, retval.Time.Unix())
+	assert.AssertEquals(t, retval.Method, "GET", "Return Value mismatch.\nExpected: %v\nActual: %v", "GET", retval.Method)
+	assert.AssertEquals(t, retval.Status, 200, "Return Value mismatch.\nExpected: %v\nActual: %v", 200, retval.Status)
+	assert.AssertEquals(t, retval.Size, uint64(2326), "Return Value mismatch.\nExpected: %v\nActual: %v", 2326, retval.Size)
+	assert.AssertEquals(t, retval.Ratio, 0.5, "Return Value mismatch.\nExpected: %v\nActual: %v", 0.5, retval.Ratio)
+	assert.AssertTrue(t, retval.Cached, "Return Value mismatch.\nExpected: %v\nActual: %v", true, retval.Cached)
+	assert.AssertEquals(t, retval.Elapsed, 1500*time.Microsecond, "Return Value mismatch.\nExpected: %v\nActual: %v", "1.5ms", retval.Elapsed)
+	assert.AssertTrue(t, retval.Referer == nil, "Return Value mismatch (not participated).\nExpected: %v\nActual: %v", nil, retval.Referer)
+
+	// check : empty capture
+	retval = accessLog{}
+	err = strproc.RegExpUnmarshal(accessLogPattern, `::1 - [10/Oct/2000:13:55:36 +0000] "POST / HTTP/1.1" 201 0  false 2s ""`, &retval)
+	assert.AssertNil(t, err, "Error : %v", err)
+	assert.AssertEquals(t, retval.Ratio, 0.0, "Return Value mismatch.\nExpected: %v\nActual: %v", 0.0, retval.Ratio)
+	assert.AssertNotNil(t, retval.Referer, "Return Value mismatch.\nExpected: %v\nActual: %v", "", retval.Referer)
+	assert.AssertEquals(t, *retval.Referer, "", "Return Value mismatch.\nExpected: %v\nActual: %v", "", *retval.Referer)
+
+	// check : conversion errors per field
+	retval = accessLog{}
+	err = strproc.RegExpUnmarshal(accessLogPattern, `not-ip frank [10/Oct/2000] "GET / HTTP/1.0" - 99999999999999999999 x yes 10 "ref"`, &retval)
+	assert.AssertNotNil(t, err, "Couldn't check the `conversion errors`\nError : %v", err)
+
+	errs, ok := err.(strutils.RegExpUnmarshalError)
+	assert.AssertTrue(t, ok, "Return Value mismatch.\nExpected: %v\nActual: %T", "RegExpUnmarshalError", err)
+
+	fields := []string{"IP", "Time", "Status", "Size", "Ratio", "Cached", "Elapsed"}
+	assert.AssertEquals(t, len(errs), len(fields), "Return Value mismatch.\nExpected: %v\nActual: %v", len(fields), errs)
+	for no, v := range errs {
+		assert.AssertEquals(t, v.Field, fields[no], "Return Value mismatch.\nExpected: %v\nActual: %v", fields[no], v.Field)
+	}
+
+	assert.AssertTrue(t, errors.Is(errs[3].Err, strconv.ErrRange), "Return Value mismatch.\nExpected: %v\nActual: %v", strconv.ErrRange, errs[3].Err)
+	assert.AssertEquals(t, *retval.Referer, "ref", "Return Value mismatch (other fields are set).\nExpected: %v\nActual: %v", "ref", *retval.Referer)
+	assert.AssertEquals(t, retval.Method, "GET", "Return Value mismatch (other fields are set).\nExpected: %v\nActual: %v", "GET", retval.Method)
+
+	// check : not matched
+	err = strproc.RegExpUnmarshal(accessLogPattern, "abc", &retval)
+	assert.AssertNotNil(t, err, "Couldn't check the `not matched`\nError : %v", err)
+
+	// check : not allow dst
+	for _, v := range []interface{}{nil, retval, new(int), (*accessLog)(nil)} {
+		err = strproc.RegExpUnmarshal(accessLogPattern, "abc", v)
+		assert.AssertNotNil(t, err, "Couldn't check the `not allow dst` (%T)\nError : %v", v, err)
+	}
+
+	// check : not exists group, not support type
+	var notExists struct {
+		Name string `re:"name"`
+	}
+	err = strproc.RegExpUnmarshal(regexp.MustCompile(`(?P<first>\w+)`), "abc", &notExists)
+	assert.AssertNotNil(t, err, "Couldn't check the `not exists group`\nError : %v", err)
+
+	var notSupport struct {
+		Names []string `re:"first"`
+	}
+	err = strproc.RegExpUnmarshal(regexp.MustCompile(`(?P<first>\w+)`), "abc", &notSupport)
+	assert.AssertNotNil(t, err, "Couldn't check the `not support type`\nError : %v", err)
+}